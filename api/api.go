@@ -0,0 +1,155 @@
+package api
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"net/http"
+	"time"
+
+	"github.com/VTGare/boe-tea-go/internal/config"
+	"github.com/VTGare/boe-tea-go/store"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+)
+
+//go:embed web
+var webFS embed.FS
+
+// Server is an HTTP server that exposes a versioned JSON REST API for guild settings,
+// art channels, crosspost groups and bookmarks, and serves the web dashboard.
+type Server struct {
+	store    store.Store
+	auth     Authenticator
+	channels Channels
+	config   *config.API
+	log      *zap.SugaredLogger
+	mux      *http.ServeMux
+}
+
+// Channels looks up Discord channels to validate channel IDs sent to the API.
+type Channels interface {
+	Channel(channelID string) (*discordgo.Channel, error)
+}
+
+func New(store store.Store, auth Authenticator, channels Channels, cfg *config.API, log *zap.SugaredLogger) *Server {
+	s := &Server{
+		store:    store,
+		auth:     auth,
+		channels: channels,
+		config:   cfg,
+		log:      log,
+		mux:      http.NewServeMux(),
+	}
+
+	s.routes()
+	return s
+}
+
+func (s *Server) routes() {
+	s.mux.HandleFunc("GET /api/v1/oauth", s.oauth)
+
+	s.mux.Handle("GET /api/v1/guilds/{guildID}", s.guildOnly(s.guild))
+	s.mux.Handle("PATCH /api/v1/guilds/{guildID}", s.guildOnly(s.updateGuild))
+	s.mux.Handle("GET /api/v1/guilds/{guildID}/artchannels", s.guildOnly(s.artChannels))
+	s.mux.Handle("POST /api/v1/guilds/{guildID}/artchannels", s.guildOnly(s.addArtChannels))
+	s.mux.Handle("DELETE /api/v1/guilds/{guildID}/artchannels", s.guildOnly(s.deleteArtChannels))
+
+	s.mux.Handle("GET /api/v1/users/@me", s.authenticated(s.user))
+	s.mux.Handle("GET /api/v1/users/@me/groups", s.authenticated(s.groups))
+	s.mux.Handle("POST /api/v1/users/@me/groups", s.authenticated(s.createGroup))
+	s.mux.Handle("PATCH /api/v1/users/@me/groups/{name}", s.authenticated(s.updateGroup))
+	s.mux.Handle("DELETE /api/v1/users/@me/groups/{name}", s.authenticated(s.deleteGroup))
+	s.mux.Handle("POST /api/v1/users/@me/groups/{name}/children", s.authenticated(s.addGroupChildren))
+	s.mux.Handle("DELETE /api/v1/users/@me/groups/{name}/children/{channelID}", s.authenticated(s.deleteGroupChild))
+
+	s.mux.Handle("GET /api/v1/users/@me/bookmarks", s.authenticated(s.bookmarks))
+	s.mux.Handle("POST /api/v1/users/@me/bookmarks", s.authenticated(s.addBookmark))
+	s.mux.Handle("DELETE /api/v1/users/@me/bookmarks/{artworkID}", s.authenticated(s.deleteBookmark))
+
+	web, _ := fs.Sub(webFS, "web")
+	s.mux.Handle("GET /", http.FileServerFS(web))
+}
+
+// Handle registers an additional handler, e.g. the image proxy. Patterns follow http.ServeMux.
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// ListenAndServe starts the HTTP server and blocks until the context is cancelled.
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	srv := &http.Server{
+		Addr:              s.config.Address,
+		Handler:           s,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- srv.ListenAndServe()
+	}()
+
+	s.log.With("address", s.config.Address).Info("starting an api server")
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if err := srv.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+
+		return nil
+	}
+}
+
+func (s *Server) oauth(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"client_id": s.config.ClientID,
+		"scopes":    []string{"identify", "guilds"},
+	})
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, errorResponse{Error: msg})
+}
+
+func (s *Server) writeStoreError(w http.ResponseWriter, err error) {
+	switch {
+	case isNotFound(err):
+		writeError(w, http.StatusNotFound, "not found")
+	case errors.Is(err, context.DeadlineExceeded):
+		writeError(w, http.StatusGatewayTimeout, "request timed out")
+	default:
+		s.log.With("error", err).Error("api store error")
+		writeError(w, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+func decodeJSON(w http.ResponseWriter, r *http.Request, v any) error {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20))
+	dec.DisallowUnknownFields()
+
+	return dec.Decode(v)
+}
@@ -0,0 +1,268 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/VTGare/boe-tea-go/api"
+	"github.com/VTGare/boe-tea-go/internal/config"
+	"github.com/VTGare/boe-tea-go/store"
+	"github.com/VTGare/boe-tea-go/store/memory"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestAPI(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "API Suite")
+}
+
+const (
+	adminToken  = "admin"
+	memberToken = "member"
+	guildID     = "100"
+)
+
+type fakeAuth struct{}
+
+func (fakeAuth) Authenticate(_ context.Context, token string) (*api.Identity, error) {
+	switch token {
+	case adminToken:
+		return &api.Identity{UserID: "1", Guilds: map[string]int64{guildID: discordgo.PermissionManageServer}}, nil
+	case memberToken:
+		return &api.Identity{UserID: "2", Guilds: map[string]int64{guildID: discordgo.PermissionSendMessages}}, nil
+	}
+
+	return nil, api.ErrUnauthorized
+}
+
+// fakeChannels has text channels 200-201 and 300-301 and voice channel 400 of the test guild
+// and text channel 500 of a guild the users aren't members of.
+type fakeChannels struct{}
+
+func (fakeChannels) Channel(channelID string) (*discordgo.Channel, error) {
+	switch channelID {
+	case "200", "201", "300", "301":
+		return &discordgo.Channel{ID: channelID, GuildID: guildID, Type: discordgo.ChannelTypeGuildText}, nil
+	case "400":
+		return &discordgo.Channel{ID: channelID, GuildID: guildID, Type: discordgo.ChannelTypeGuildVoice}, nil
+	case "500":
+		return &discordgo.Channel{ID: channelID, GuildID: "101", Type: discordgo.ChannelTypeGuildText}, nil
+	}
+
+	return nil, &discordgo.RESTError{Response: &http.Response{StatusCode: http.StatusNotFound}}
+}
+
+var _ = Describe("REST API", func() {
+	var (
+		srv *api.Server
+		mem store.Store
+	)
+
+	do := func(method, path, token string, body any) *httptest.ResponseRecorder {
+		var buf bytes.Buffer
+		if body != nil {
+			Expect(json.NewEncoder(&buf).Encode(body)).To(Succeed())
+		}
+
+		req := httptest.NewRequest(method, path, &buf)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, req)
+		return rec
+	}
+
+	BeforeEach(func() {
+		var err error
+		mem, err = memory.New("")
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = mem.CreateGuild(context.Background(), guildID)
+		Expect(err).NotTo(HaveOccurred())
+
+		srv = api.New(mem, fakeAuth{}, fakeChannels{}, &config.API{ClientID: "123"}, zap.NewNop().Sugar())
+	})
+
+	Describe("authentication", func() {
+		It("should reject requests without a token", func() {
+			Expect(do("GET", "/api/v1/users/@me", "", nil).Code).To(Equal(http.StatusUnauthorized))
+		})
+
+		It("should reject invalid tokens", func() {
+			Expect(do("GET", "/api/v1/users/@me", "invalid", nil).Code).To(Equal(http.StatusUnauthorized))
+		})
+
+		It("should forbid guild settings without manage server permission", func() {
+			Expect(do("GET", "/api/v1/guilds/"+guildID, memberToken, nil).Code).To(Equal(http.StatusForbidden))
+		})
+	})
+
+	Describe("guild settings", func() {
+		It("should return guild settings", func() {
+			rec := do("GET", "/api/v1/guilds/"+guildID, adminToken, nil)
+			Expect(rec.Code).To(Equal(http.StatusOK))
+
+			var guild store.Guild
+			Expect(json.NewDecoder(rec.Body).Decode(&guild)).To(Succeed())
+			Expect(guild.ID).To(Equal(guildID))
+			Expect(guild.Prefix).To(Equal("bt!"))
+		})
+
+		It("should partially update guild settings", func() {
+			rec := do("PATCH", "/api/v1/guilds/"+guildID, adminToken, map[string]any{"pixiv": false, "limit": 5})
+			Expect(rec.Code).To(Equal(http.StatusOK))
+
+			guild, _ := mem.Guild(context.Background(), guildID)
+			Expect(guild.Pixiv).To(BeFalse())
+			Expect(guild.Limit).To(Equal(5))
+			Expect(guild.Twitter).To(BeTrue())
+		})
+
+		It("should validate guild settings", func() {
+			rec := do("PATCH", "/api/v1/guilds/"+guildID, adminToken, map[string]any{"prefix": "toolong"})
+			Expect(rec.Code).To(Equal(http.StatusUnprocessableEntity))
+		})
+
+		It("should reject unknown settings", func() {
+			rec := do("PATCH", "/api/v1/guilds/"+guildID, adminToken, map[string]any{"unknown": true})
+			Expect(rec.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should add and remove art channels", func() {
+			rec := do("POST", "/api/v1/guilds/"+guildID+"/artchannels", adminToken, map[string]any{"channels": []string{"200", "201"}})
+			Expect(rec.Code).To(Equal(http.StatusOK))
+
+			rec = do("DELETE", "/api/v1/guilds/"+guildID+"/artchannels", adminToken, map[string]any{"channels": []string{"200"}})
+			Expect(rec.Code).To(Equal(http.StatusOK))
+
+			guild, _ := mem.Guild(context.Background(), guildID)
+			Expect(guild.ArtChannels).To(Equal([]string{"201"}))
+		})
+
+		It("should not add an existing art channel", func() {
+			do("POST", "/api/v1/guilds/"+guildID+"/artchannels", adminToken, map[string]any{"channels": []string{"200"}})
+			rec := do("POST", "/api/v1/guilds/"+guildID+"/artchannels", adminToken, map[string]any{"channels": []string{"200"}})
+			Expect(rec.Code).To(Equal(http.StatusConflict))
+		})
+
+		DescribeTable("should reject invalid art channels",
+			func(channelID string) {
+				rec := do("POST", "/api/v1/guilds/"+guildID+"/artchannels", adminToken, map[string]any{"channels": []string{"200", channelID}})
+				Expect(rec.Code).To(Equal(http.StatusBadRequest))
+
+				guild, _ := mem.Guild(context.Background(), guildID)
+				Expect(guild.ArtChannels).To(BeEmpty())
+			},
+			Entry("Unknown channel", "999"),
+			Entry("Voice channel", "400"),
+			Entry("Channel of another guild", "500"),
+		)
+	})
+
+	Describe("crosspost groups", func() {
+		It("should create a group and add children", func() {
+			rec := do("POST", "/api/v1/users/@me/groups", adminToken, map[string]any{"name": "art", "parent": "300"})
+			Expect(rec.Code).To(Equal(http.StatusCreated))
+
+			rec = do("POST", "/api/v1/users/@me/groups/art/children", adminToken, map[string]any{"channels": []string{"301", "300"}})
+			Expect(rec.Code).To(Equal(http.StatusOK))
+
+			user, _ := mem.User(context.Background(), "1")
+			group, ok := user.FindGroupByName("art")
+			Expect(ok).To(BeTrue())
+			Expect(group.Children).To(Equal([]string{"301"}))
+		})
+
+		It("should not reuse a parent channel", func() {
+			do("POST", "/api/v1/users/@me/groups", adminToken, map[string]any{"name": "art", "parent": "300"})
+			rec := do("POST", "/api/v1/users/@me/groups", adminToken, map[string]any{"name": "art2", "parent": "300"})
+			Expect(rec.Code).To(Equal(http.StatusConflict))
+		})
+
+		DescribeTable("should reject invalid group channels",
+			func(channelID string) {
+				rec := do("POST", "/api/v1/users/@me/groups", adminToken, map[string]any{"name": "art", "parent": channelID})
+				Expect(rec.Code).To(Equal(http.StatusBadRequest))
+
+				rec = do("POST", "/api/v1/users/@me/groups", adminToken, map[string]any{"name": "pair", "is_pair": true, "children": []string{"300", channelID}})
+				Expect(rec.Code).To(Equal(http.StatusBadRequest))
+
+				do("POST", "/api/v1/users/@me/groups", adminToken, map[string]any{"name": "art", "parent": "300"})
+				rec = do("POST", "/api/v1/users/@me/groups/art/children", adminToken, map[string]any{"channels": []string{channelID}})
+				Expect(rec.Code).To(Equal(http.StatusBadRequest))
+
+				rec = do("PATCH", "/api/v1/users/@me/groups/art", adminToken, map[string]any{"parent": channelID})
+				Expect(rec.Code).To(Equal(http.StatusBadRequest))
+
+				user, _ := mem.User(context.Background(), "1")
+				Expect(user.Groups).To(HaveLen(1))
+				Expect(user.Groups[0].Parent).To(Equal("300"))
+				Expect(user.Groups[0].Children).To(BeEmpty())
+			},
+			Entry("Unknown channel", "999"),
+			Entry("Voice channel", "400"),
+			Entry("Channel of a guild the user isn't in", "500"),
+		)
+
+		It("should rename and delete a group", func() {
+			do("POST", "/api/v1/users/@me/groups", adminToken, map[string]any{"name": "art", "parent": "300"})
+
+			rec := do("PATCH", "/api/v1/users/@me/groups/art", adminToken, map[string]any{"name": "lewds"})
+			Expect(rec.Code).To(Equal(http.StatusOK))
+
+			rec = do("DELETE", "/api/v1/users/@me/groups/lewds", adminToken, nil)
+			Expect(rec.Code).To(Equal(http.StatusNoContent))
+
+			user, _ := mem.User(context.Background(), "1")
+			Expect(user.Groups).To(BeEmpty())
+		})
+	})
+
+	Describe("bookmarks", func() {
+		It("should add, list and delete bookmarks", func() {
+			artwork, err := mem.CreateArtwork(context.Background(), &store.Artwork{URL: "https://example.com/1"})
+			Expect(err).NotTo(HaveOccurred())
+
+			rec := do("POST", "/api/v1/users/@me/bookmarks", adminToken, map[string]any{"artwork_id": artwork.ID})
+			Expect(rec.Code).To(Equal(http.StatusCreated))
+
+			rec = do("POST", "/api/v1/users/@me/bookmarks", adminToken, map[string]any{"artwork_id": artwork.ID})
+			Expect(rec.Code).To(Equal(http.StatusConflict))
+
+			rec = do("GET", "/api/v1/users/@me/bookmarks", adminToken, nil)
+			Expect(rec.Code).To(Equal(http.StatusOK))
+
+			var bookmarks []map[string]any
+			Expect(json.NewDecoder(rec.Body).Decode(&bookmarks)).To(Succeed())
+			Expect(bookmarks).To(HaveLen(1))
+			Expect(bookmarks[0]["artwork"]).To(HaveKeyWithValue("url", "https://example.com/1"))
+
+			rec = do("DELETE", "/api/v1/users/@me/bookmarks/1", adminToken, nil)
+			Expect(rec.Code).To(Equal(http.StatusNoContent))
+
+			rec = do("DELETE", "/api/v1/users/@me/bookmarks/1", adminToken, nil)
+			Expect(rec.Code).To(Equal(http.StatusNotFound))
+		})
+
+		It("should not bookmark unknown artworks", func() {
+			rec := do("POST", "/api/v1/users/@me/bookmarks", adminToken, map[string]any{"artwork_id": 42})
+			Expect(rec.Code).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	It("should serve the dashboard", func() {
+		rec := do("GET", "/", "", nil)
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).To(ContainSubstring("Boe Tea Dashboard"))
+	})
+})
@@ -0,0 +1,145 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ReneKroon/ttlcache"
+	"github.com/bwmarrin/discordgo"
+)
+
+// ErrUnauthorized is returned by an Authenticator when a bearer token is invalid or expired.
+var ErrUnauthorized = errors.New("unauthorized")
+
+// Authenticator resolves Discord OAuth2 bearer tokens to identities.
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (*Identity, error)
+}
+
+// Identity is a Discord user authenticated by a bearer token with permissions in their guilds.
+type Identity struct {
+	UserID string
+	Guilds map[string]int64
+	Owner  map[string]bool
+}
+
+// CanManage reports whether the user has Manage Server or Administrator permissions in a guild.
+func (i *Identity) CanManage(guildID string) bool {
+	if i.Owner[guildID] {
+		return true
+	}
+
+	perms, ok := i.Guilds[guildID]
+	if !ok {
+		return false
+	}
+
+	return perms&(discordgo.PermissionAdministrator|discordgo.PermissionManageServer) != 0
+}
+
+// IsMember reports whether the user is a member of a guild.
+func (i *Identity) IsMember(guildID string) bool {
+	_, ok := i.Guilds[guildID]
+	return ok
+}
+
+type discordAuthenticator struct {
+	cache *ttlcache.Cache
+}
+
+// DiscordAuthenticator validates bearer tokens against Discord's API and caches identities for 5 minutes.
+func DiscordAuthenticator() Authenticator {
+	cache := ttlcache.NewCache()
+	cache.SetTTL(5 * time.Minute)
+
+	return &discordAuthenticator{cache: cache}
+}
+
+func (d *discordAuthenticator) Authenticate(_ context.Context, token string) (*Identity, error) {
+	if i, ok := d.cache.Get(token); ok {
+		return i.(*Identity), nil
+	}
+
+	s, err := discordgo.New("Bearer " + token)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.User("@me")
+	if err != nil {
+		var restErr *discordgo.RESTError
+		if errors.As(err, &restErr) && restErr.Response != nil && restErr.Response.StatusCode == http.StatusUnauthorized {
+			return nil, ErrUnauthorized
+		}
+
+		return nil, err
+	}
+
+	guilds, err := s.UserGuilds(200, "", "", false)
+	if err != nil {
+		return nil, err
+	}
+
+	identity := &Identity{
+		UserID: user.ID,
+		Guilds: make(map[string]int64, len(guilds)),
+		Owner:  make(map[string]bool),
+	}
+
+	for _, guild := range guilds {
+		identity.Guilds[guild.ID] = guild.Permissions
+		if guild.Owner {
+			identity.Owner[guild.ID] = true
+		}
+	}
+
+	d.cache.Set(token, identity)
+	return identity, nil
+}
+
+type identityKey struct{}
+
+func identityFromContext(ctx context.Context) *Identity {
+	return ctx.Value(identityKey{}).(*Identity)
+}
+
+// authenticated is a middleware that requires a valid bearer token.
+func (s *Server) authenticated(next http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || token == "" {
+			writeError(w, http.StatusUnauthorized, "missing bearer token")
+			return
+		}
+
+		identity, err := s.auth.Authenticate(r.Context(), token)
+		if err != nil {
+			if errors.Is(err, ErrUnauthorized) {
+				writeError(w, http.StatusUnauthorized, "invalid bearer token")
+				return
+			}
+
+			s.log.With("error", err).Warn("failed to authenticate an api request")
+			writeError(w, http.StatusBadGateway, "failed to authenticate with discord")
+			return
+		}
+
+		next(w, r.WithContext(context.WithValue(r.Context(), identityKey{}, identity)))
+	})
+}
+
+// guildOnly is a middleware that requires Manage Server permission in the guild from the path.
+func (s *Server) guildOnly(next http.HandlerFunc) http.Handler {
+	return s.authenticated(func(w http.ResponseWriter, r *http.Request) {
+		identity := identityFromContext(r.Context())
+		if !identity.CanManage(r.PathValue("guildID")) {
+			writeError(w, http.StatusForbidden, "manage server permission required")
+			return
+		}
+
+		next(w, r)
+	})
+}
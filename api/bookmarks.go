@@ -0,0 +1,152 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/VTGare/boe-tea-go/store"
+)
+
+type bookmarkResponse struct {
+	*store.Bookmark
+	Artwork *store.Artwork `json:"artwork,omitempty"`
+}
+
+type bookmarkRequest struct {
+	ArtworkID int  `json:"artwork_id"`
+	NSFW      bool `json:"nsfw"`
+}
+
+func (s *Server) bookmarks(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	var (
+		query  = r.URL.Query()
+		filter = store.BookmarkFilterAll
+		order  = store.Descending
+	)
+
+	switch query.Get("filter") {
+	case "", "all":
+	case "sfw", "safe":
+		filter = store.BookmarkFilterSafe
+	case "nsfw", "unsafe":
+		filter = store.BookmarkFilterUnsafe
+	default:
+		writeError(w, http.StatusBadRequest, "unknown filter, use one of [all, sfw, nsfw]")
+		return
+	}
+
+	switch query.Get("order") {
+	case "", "desc":
+	case "asc":
+		order = store.Ascending
+	default:
+		writeError(w, http.StatusBadRequest, "unknown order, use one of [asc, desc]")
+		return
+	}
+
+	bookmarks, err := s.store.ListBookmarks(ctx, identityFromContext(r.Context()).UserID, filter, order)
+	if err != nil {
+		s.writeStoreError(w, err)
+		return
+	}
+
+	res := make([]bookmarkResponse, 0, len(bookmarks))
+	if len(bookmarks) == 0 {
+		writeJSON(w, http.StatusOK, res)
+		return
+	}
+
+	ids := make([]int, 0, len(bookmarks))
+	for _, bookmark := range bookmarks {
+		ids = append(ids, bookmark.ArtworkID)
+	}
+
+	artworks, err := s.store.SearchArtworks(ctx, store.ArtworkFilter{IDs: ids}, store.ArtworkSearchOptions{
+		Limit: int64(len(ids)),
+		Order: order,
+		Sort:  store.ByTime,
+	})
+	if err != nil {
+		s.writeStoreError(w, err)
+		return
+	}
+
+	byID := make(map[int]*store.Artwork, len(artworks))
+	for _, artwork := range artworks {
+		byID[artwork.ID] = artwork
+	}
+
+	for _, bookmark := range bookmarks {
+		res = append(res, bookmarkResponse{Bookmark: bookmark, Artwork: byID[bookmark.ArtworkID]})
+	}
+
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (s *Server) addBookmark(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	var req bookmarkRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if _, err := s.store.Artwork(ctx, req.ArtworkID, ""); err != nil {
+		s.writeStoreError(w, err)
+		return
+	}
+
+	bookmark := &store.Bookmark{
+		UserID:    identityFromContext(r.Context()).UserID,
+		ArtworkID: req.ArtworkID,
+		NSFW:      req.NSFW,
+		CreatedAt: time.Now(),
+	}
+
+	added, err := s.store.AddBookmark(ctx, bookmark)
+	if err != nil {
+		s.writeStoreError(w, err)
+		return
+	}
+
+	if !added {
+		writeError(w, http.StatusConflict, "artwork is already bookmarked")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, bookmark)
+}
+
+func (s *Server) deleteBookmark(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	id, err := strconv.Atoi(r.PathValue("artworkID"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "artwork id must be an integer")
+		return
+	}
+
+	deleted, err := s.store.DeleteBookmark(ctx, &store.Bookmark{
+		UserID:    identityFromContext(r.Context()).UserID,
+		ArtworkID: id,
+	})
+	if err != nil {
+		s.writeStoreError(w, err)
+		return
+	}
+
+	if !deleted {
+		writeError(w, http.StatusNotFound, "bookmark not found")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,222 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/VTGare/boe-tea-go/store"
+	"github.com/bwmarrin/discordgo"
+)
+
+type channelsRequest struct {
+	Channels []string `json:"channels"`
+}
+
+func (s *Server) guild(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	guild, err := s.store.Guild(ctx, r.PathValue("guildID"))
+	if err != nil {
+		s.writeStoreError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, guild)
+}
+
+func (s *Server) updateGuild(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	guild, err := s.store.Guild(ctx, r.PathValue("guildID"))
+	if err != nil {
+		s.writeStoreError(w, err)
+		return
+	}
+
+	// Decode on top of a copy of current settings so omitted fields stay the same.
+	updated := *guild
+	if err := decodeJSON(w, r, &updated); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Immutable fields and fields managed by their own endpoints.
+	updated.ID = guild.ID
+	updated.ArtChannels = guild.ArtChannels
+	updated.Groups = guild.Groups
+	updated.CreatedAt = guild.CreatedAt
+
+	if err := validateGuild(&updated); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	res, err := s.store.UpdateGuild(ctx, &updated)
+	if err != nil {
+		s.writeStoreError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (s *Server) artChannels(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	guild, err := s.store.Guild(ctx, r.PathValue("guildID"))
+	if err != nil {
+		s.writeStoreError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, channelsRequest{Channels: guild.ArtChannels})
+}
+
+func (s *Server) addArtChannels(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	var req channelsRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := validateSnowflakes(req.Channels); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	guildID := r.PathValue("guildID")
+	if err := s.validateChannels(req.Channels, func(id string) bool { return id == guildID }); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	guild, err := s.store.Guild(ctx, guildID)
+	if err != nil {
+		s.writeStoreError(w, err)
+		return
+	}
+
+	for _, channelID := range req.Channels {
+		if slices.Contains(guild.ArtChannels, channelID) {
+			writeError(w, http.StatusConflict, fmt.Sprintf("channel %v is already an art channel", channelID))
+			return
+		}
+	}
+
+	guild, err = s.store.AddArtChannels(ctx, guild.ID, req.Channels)
+	if err != nil {
+		s.writeStoreError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, channelsRequest{Channels: guild.ArtChannels})
+}
+
+func (s *Server) deleteArtChannels(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	var req channelsRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if len(req.Channels) == 0 {
+		writeError(w, http.StatusUnprocessableEntity, "no channels provided")
+		return
+	}
+
+	guild, err := s.store.DeleteArtChannels(ctx, r.PathValue("guildID"), req.Channels)
+	if err != nil {
+		s.writeStoreError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, channelsRequest{Channels: guild.ArtChannels})
+}
+
+// validateGuild applies the same constraints as bt!set command.
+func validateGuild(guild *store.Guild) error {
+	if guild.Prefix == "" || len(guild.Prefix) > 5 {
+		return fmt.Errorf("prefix must be between 1 and 5 characters long")
+	}
+
+	if guild.Limit < 1 {
+		return fmt.Errorf("limit must be a positive number")
+	}
+
+	switch guild.Repost {
+	case store.GuildRepostEnabled, store.GuildRepostDisabled, store.GuildRepostStrict:
+	default:
+		return fmt.Errorf("unknown repost option %q", guild.Repost)
+	}
+
+	switch guild.NSFWPolicy {
+	case "", store.NSFWPolicyAllow, store.NSFWPolicySpoiler, store.NSFWPolicyBlock:
+	default:
+		return fmt.Errorf("unknown nsfw policy %q", guild.NSFWPolicy)
+	}
+
+	switch guild.AIPolicy {
+	case "", store.AIPolicyLabel, store.AIPolicyHide, store.AIPolicyDelete:
+	default:
+		return fmt.Errorf("unknown ai policy %q", guild.AIPolicy)
+	}
+
+	switch guild.Blacklist.Mode {
+	case "", store.BlacklistSuppress, store.BlacklistSpoiler:
+	default:
+		return fmt.Errorf("unknown blacklist mode %q", guild.Blacklist.Mode)
+	}
+
+	if guild.RepostExpiration < 1*time.Minute || guild.RepostExpiration > 168*time.Hour {
+		return fmt.Errorf("repost expiration is out of range, minimum is 1m and maximum is 168h")
+	}
+
+	return nil
+}
+
+func validateSnowflakes(ids []string) error {
+	if len(ids) == 0 {
+		return fmt.Errorf("no channels provided")
+	}
+
+	for _, id := range ids {
+		if _, err := strconv.ParseUint(id, 10, 64); err != nil {
+			return fmt.Errorf("%q is not a valid channel id", id)
+		}
+	}
+
+	return nil
+}
+
+// validateChannels checks that channels exist and are text channels of guilds accepted by inGuild.
+func (s *Server) validateChannels(ids []string, inGuild func(guildID string) bool) error {
+	for _, id := range ids {
+		ch, err := s.channels.Channel(id)
+		if err != nil {
+			return fmt.Errorf("channel %v not found", id)
+		}
+
+		if !inGuild(ch.GuildID) {
+			return fmt.Errorf("channel %v belongs to another server", id)
+		}
+
+		if ch.Type != discordgo.ChannelTypeGuildText {
+			return fmt.Errorf("channel %v is not a text channel", id)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,315 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/VTGare/boe-tea-go/store"
+)
+
+type groupRequest struct {
+	Name     string   `json:"name"`
+	Parent   string   `json:"parent"`
+	Children []string `json:"children"`
+	IsPair   bool     `json:"is_pair"`
+}
+
+type groupUpdateRequest struct {
+	Name   string `json:"name"`
+	Parent string `json:"parent"`
+}
+
+func (s *Server) user(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	user, err := s.store.User(ctx, identityFromContext(r.Context()).UserID)
+	if err != nil {
+		s.writeStoreError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, user)
+}
+
+func (s *Server) groups(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	user, err := s.store.User(ctx, identityFromContext(r.Context()).UserID)
+	if err != nil {
+		s.writeStoreError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, user.Groups)
+}
+
+func (s *Server) createGroup(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	var req groupRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if req.Name == "" {
+		writeError(w, http.StatusUnprocessableEntity, "group name is required")
+		return
+	}
+
+	identity := identityFromContext(r.Context())
+	user, err := s.store.User(ctx, identity.UserID)
+	if err != nil {
+		s.writeStoreError(w, err)
+		return
+	}
+
+	if _, ok := user.FindGroupByName(req.Name); ok {
+		writeError(w, http.StatusConflict, "group already exists")
+		return
+	}
+
+	group := &store.Group{Name: req.Name, IsPair: req.IsPair}
+	if req.IsPair {
+		if len(req.Children) != 2 || req.Children[0] == req.Children[1] {
+			writeError(w, http.StatusUnprocessableEntity, "pair requires exactly two different channels")
+			return
+		}
+
+		if err := validateSnowflakes(req.Children); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+
+		if err := s.validateChannels(req.Children, identity.IsMember); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		for _, child := range req.Children {
+			if _, ok := user.FindGroup(child); ok {
+				writeError(w, http.StatusConflict, "channel "+child+" is already used by another group")
+				return
+			}
+		}
+
+		group.Children = slices.Clone(req.Children)
+		sort.Strings(group.Children)
+
+		user, err = s.store.CreateCrosspostPair(ctx, user.ID, group)
+	} else {
+		channels := append([]string{req.Parent}, req.Children...)
+		if err := validateSnowflakes(channels); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+
+		if err := s.validateChannels(channels, identity.IsMember); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if _, ok := user.FindGroup(req.Parent); ok {
+			writeError(w, http.StatusConflict, "parent channel is already used by another group")
+			return
+		}
+
+		group.Parent = req.Parent
+		group.Children = make([]string, 0, len(req.Children))
+		for _, child := range req.Children {
+			if child != req.Parent && !slices.Contains(group.Children, child) {
+				group.Children = append(group.Children, child)
+			}
+		}
+
+		user, err = s.store.CreateCrosspostGroup(ctx, user.ID, group)
+	}
+
+	if err != nil {
+		if isNotFound(err) {
+			writeError(w, http.StatusConflict, "failed to create a group")
+			return
+		}
+
+		s.writeStoreError(w, err)
+		return
+	}
+
+	created, _ := user.FindGroupByName(req.Name)
+	writeJSON(w, http.StatusCreated, created)
+}
+
+func (s *Server) updateGroup(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	var req groupUpdateRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	identity := identityFromContext(r.Context())
+	user, err := s.store.User(ctx, identity.UserID)
+	if err != nil {
+		s.writeStoreError(w, err)
+		return
+	}
+
+	name := r.PathValue("name")
+	group, ok := user.FindGroupByName(name)
+	if !ok {
+		writeError(w, http.StatusNotFound, "group not found")
+		return
+	}
+
+	if req.Parent != "" && req.Parent != group.Parent {
+		if group.IsPair {
+			writeError(w, http.StatusUnprocessableEntity, "pairs don't have a parent channel")
+			return
+		}
+
+		if _, ok := user.FindGroup(req.Parent); ok || slices.Contains(group.Children, req.Parent) {
+			writeError(w, http.StatusConflict, "parent channel is already used")
+			return
+		}
+
+		if err := validateSnowflakes([]string{req.Parent}); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+
+		if err := s.validateChannels([]string{req.Parent}, identity.IsMember); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if user, err = s.store.EditCrosspostParent(ctx, user.ID, name, req.Parent); err != nil {
+			s.writeStoreError(w, err)
+			return
+		}
+	}
+
+	if req.Name != "" && req.Name != name {
+		if _, ok := user.FindGroupByName(req.Name); ok {
+			writeError(w, http.StatusConflict, "group already exists")
+			return
+		}
+
+		if user, err = s.store.RenameCrosspostGroup(ctx, user.ID, name, req.Name); err != nil {
+			s.writeStoreError(w, err)
+			return
+		}
+
+		name = req.Name
+	}
+
+	group, _ = user.FindGroupByName(name)
+	writeJSON(w, http.StatusOK, group)
+}
+
+func (s *Server) deleteGroup(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	_, err := s.store.DeleteCrosspostGroup(ctx, identityFromContext(r.Context()).UserID, r.PathValue("name"))
+	if err != nil {
+		s.writeStoreError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) addGroupChildren(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+	defer cancel()
+
+	var req channelsRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := validateSnowflakes(req.Channels); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	identity := identityFromContext(r.Context())
+	if err := s.validateChannels(req.Channels, identity.IsMember); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	user, err := s.store.User(ctx, identity.UserID)
+	if err != nil {
+		s.writeStoreError(w, err)
+		return
+	}
+
+	name := r.PathValue("name")
+	group, ok := user.FindGroupByName(name)
+	if !ok {
+		writeError(w, http.StatusNotFound, "group not found")
+		return
+	}
+
+	if group.IsPair {
+		writeError(w, http.StatusUnprocessableEntity, "channels cannot be added to a pair")
+		return
+	}
+
+	for _, channelID := range req.Channels {
+		if group.Parent == channelID || slices.Contains(group.Children, channelID) {
+			continue
+		}
+
+		if _, ok := user.FindGroup(channelID); ok {
+			continue
+		}
+
+		if user, err = s.store.AddCrosspostChannel(ctx, user.ID, name, channelID); err != nil {
+			s.writeStoreError(w, err)
+			return
+		}
+	}
+
+	group, _ = user.FindGroupByName(name)
+	writeJSON(w, http.StatusOK, group)
+}
+
+func (s *Server) deleteGroupChild(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	user, err := s.store.DeleteCrosspostChannel(
+		ctx,
+		identityFromContext(r.Context()).UserID,
+		r.PathValue("name"),
+		r.PathValue("channelID"),
+	)
+	if err != nil {
+		s.writeStoreError(w, err)
+		return
+	}
+
+	group, ok := user.FindGroupByName(r.PathValue("name"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "group not found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, group)
+}
+
+func isNotFound(err error) bool {
+	return errors.Is(err, store.ErrNotFound) || errors.Is(err, store.ErrArtworkNotFound)
+}
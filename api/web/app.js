@@ -0,0 +1,304 @@
+"use strict";
+
+const MANAGE_SERVER = 1n << 5n;
+const ADMINISTRATOR = 1n << 3n;
+
+const toggles = [
+  "pixiv", "twitter", "deviant", "bluesky", "fanbox", "artstation",
+  "mastodon", "tumblr",
+  "tags", "flavour_text", "crosspost", "reactions", "skip_first", "nsfw",
+];
+
+let token = localStorage.getItem("token");
+
+async function api(method, path, body) {
+  const resp = await fetch("/api/v1" + path, {
+    method,
+    headers: {
+      "Authorization": "Bearer " + token,
+      "Content-Type": "application/json",
+    },
+    body: body === undefined ? undefined : JSON.stringify(body),
+  });
+
+  if (resp.status === 401) {
+    logout();
+    return null;
+  }
+
+  if (resp.status === 204) {
+    return null;
+  }
+
+  const data = await resp.json();
+  if (!resp.ok) {
+    alert(data.error);
+    return null;
+  }
+
+  return data;
+}
+
+async function login() {
+  const oauth = await (await fetch("/api/v1/oauth")).json();
+  const params = new URLSearchParams({
+    client_id: oauth.client_id,
+    response_type: "token",
+    scope: oauth.scopes.join(" "),
+    redirect_uri: location.origin + location.pathname,
+  });
+
+  location.href = "https://discord.com/oauth2/authorize?" + params;
+}
+
+function logout() {
+  localStorage.removeItem("token");
+  location.reload();
+}
+
+async function loadGuilds() {
+  const resp = await fetch("https://discord.com/api/users/@me/guilds", {
+    headers: { "Authorization": "Bearer " + token },
+  });
+
+  const guilds = await resp.json();
+  const select = document.getElementById("guilds");
+  select.replaceChildren();
+
+  for (const guild of guilds) {
+    const perms = BigInt(guild.permissions);
+    if (!guild.owner && (perms & (MANAGE_SERVER | ADMINISTRATOR)) === 0n) {
+      continue;
+    }
+
+    select.append(new Option(guild.name, guild.id));
+  }
+
+  select.onchange = () => loadSettings(select.value);
+  if (select.value) {
+    loadSettings(select.value);
+  }
+}
+
+async function loadSettings(guildID) {
+  const guild = await api("GET", "/guilds/" + guildID);
+  if (!guild) {
+    return;
+  }
+
+  const form = document.getElementById("settings");
+  form.replaceChildren();
+
+  form.append(field("Prefix", "prefix", "text", guild.prefix));
+  form.append(field("Limit", "limit", "number", guild.limit));
+
+  const repost = document.createElement("select");
+  repost.name = "repost";
+  for (const option of ["enabled", "disabled", "strict"]) {
+    repost.append(new Option(option, option, false, guild.repost === option));
+  }
+  form.append(label("Repost", repost));
+
+  const nsfwPolicy = document.createElement("select");
+  nsfwPolicy.name = "nsfw_policy";
+  for (const option of ["allow", "spoiler", "block-in-sfw-channels"]) {
+    nsfwPolicy.append(new Option(option, option, false, (guild.nsfw_policy || "allow") === option));
+  }
+  form.append(label("NSFW policy", nsfwPolicy));
+
+  const aiPolicy = document.createElement("select");
+  aiPolicy.name = "ai_policy";
+  for (const option of ["label", "hide", "delete"]) {
+    aiPolicy.append(new Option(option, option, false, (guild.ai_policy || "label") === option));
+  }
+  form.append(label("AI policy", aiPolicy));
+
+  for (const name of toggles) {
+    form.append(field(name, name, "checkbox", guild[name]));
+  }
+
+  const save = document.createElement("button");
+  save.textContent = "Save";
+  form.append(save);
+
+  form.onsubmit = async (e) => {
+    e.preventDefault();
+
+    const update = {
+      prefix: form.prefix.value,
+      limit: Number(form.limit.value),
+      repost: form.repost.value,
+      nsfw_policy: form.nsfw_policy.value,
+      ai_policy: form.ai_policy.value,
+    };
+
+    for (const name of toggles) {
+      update[name] = form[name].checked;
+    }
+
+    if (await api("PATCH", "/guilds/" + guildID, update)) {
+      loadSettings(guildID);
+    }
+  };
+
+  renderArtChannels(guildID, guild.art_channels);
+}
+
+function renderArtChannels(guildID, channels) {
+  const list = document.getElementById("artchannels");
+  list.replaceChildren();
+
+  for (const channel of channels) {
+    const item = document.createElement("li");
+    const remove = document.createElement("button");
+
+    remove.textContent = "Remove";
+    remove.onclick = async () => {
+      const res = await api("DELETE", "/guilds/" + guildID + "/artchannels", { channels: [channel] });
+      if (res) {
+        renderArtChannels(guildID, res.channels);
+      }
+    };
+
+    item.append(channel, " ", remove);
+    list.append(item);
+  }
+
+  const form = document.getElementById("artchannel-form");
+  form.onsubmit = async (e) => {
+    e.preventDefault();
+
+    const res = await api("POST", "/guilds/" + guildID + "/artchannels", { channels: [form.channel.value] });
+    if (res) {
+      form.reset();
+      renderArtChannels(guildID, res.channels);
+    }
+  };
+}
+
+async function loadGroups() {
+  const groups = await api("GET", "/users/@me/groups");
+  const container = document.getElementById("groups");
+  container.replaceChildren();
+
+  for (const group of groups || []) {
+    const div = document.createElement("div");
+    const title = document.createElement("h3");
+    const remove = document.createElement("button");
+
+    title.textContent = (group.is_pair ? "Pair " : "Group ") + group.name;
+    remove.textContent = "Delete";
+    remove.onclick = async () => {
+      await api("DELETE", "/users/@me/groups/" + encodeURIComponent(group.name));
+      loadGroups();
+    };
+
+    div.append(title, remove);
+    if (!group.is_pair) {
+      div.append(document.createElement("br"), "Parent: " + group.parent);
+    }
+
+    div.append(document.createElement("br"), "Children: " + group.children.join(", "));
+    container.append(div);
+  }
+
+  const form = document.getElementById("group-form");
+  form.onsubmit = async (e) => {
+    e.preventDefault();
+
+    const res = await api("POST", "/users/@me/groups", {
+      name: form.name.value,
+      parent: form.parent.value,
+      children: [],
+    });
+
+    if (res) {
+      form.reset();
+      loadGroups();
+    }
+  };
+}
+
+async function loadBookmarks() {
+  const filter = document.getElementById("bookmark-filter");
+  const bookmarks = await api("GET", "/users/@me/bookmarks?filter=" + filter.value);
+  const container = document.getElementById("bookmarks");
+  container.replaceChildren();
+
+  for (const bookmark of bookmarks || []) {
+    if (!bookmark.artwork) {
+      continue;
+    }
+
+    const div = document.createElement("div");
+    const link = document.createElement("a");
+    const remove = document.createElement("button");
+
+    div.className = "bookmark";
+    link.href = bookmark.artwork.url;
+    link.target = "_blank";
+    link.textContent = bookmark.artwork.title || bookmark.artwork.author;
+
+    if (bookmark.artwork.images.length > 0) {
+      const img = document.createElement("img");
+      img.src = bookmark.artwork.images[0];
+      img.loading = "lazy";
+      div.append(img);
+    }
+
+    remove.textContent = "Remove";
+    remove.onclick = async () => {
+      await api("DELETE", "/users/@me/bookmarks/" + bookmark.artwork_id);
+      loadBookmarks();
+    };
+
+    div.append(link, " ", remove);
+    container.append(div);
+  }
+
+  filter.onchange = loadBookmarks;
+}
+
+function field(text, name, type, value) {
+  const input = document.createElement("input");
+  input.name = name;
+  input.type = type;
+
+  if (type === "checkbox") {
+    input.checked = value;
+  } else {
+    input.value = value;
+  }
+
+  return label(text, input);
+}
+
+function label(text, input) {
+  const l = document.createElement("label");
+  l.append(text + " ", input);
+  return l;
+}
+
+(function main() {
+  const fragment = new URLSearchParams(location.hash.slice(1));
+  if (fragment.has("access_token")) {
+    token = fragment.get("access_token");
+    localStorage.setItem("token", token);
+    history.replaceState(null, "", location.pathname);
+  }
+
+  document.getElementById("login").onclick = login;
+  document.getElementById("logout").onclick = logout;
+
+  if (!token) {
+    document.getElementById("login").hidden = false;
+    return;
+  }
+
+  document.getElementById("logout").hidden = false;
+  document.getElementById("app").hidden = false;
+
+  loadGuilds();
+  loadGroups();
+  loadBookmarks();
+})();
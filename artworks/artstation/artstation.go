@@ -0,0 +1,278 @@
+package artstation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/VTGare/boe-tea-go/artworks"
+	"github.com/VTGare/boe-tea-go/store"
+	"github.com/VTGare/embeds"
+	"github.com/bwmarrin/discordgo"
+	"github.com/julien040/go-ternary"
+)
+
+type ArtStation struct {
+	regex   *regexp.Regexp
+	client  *http.Client
+	limiter *artworks.Limiter
+}
+
+type Project struct {
+	HashID       string    `json:"hash_id"`
+	Title        string    `json:"title"`
+	Description  string    `json:"description"`
+	Permalink    string    `json:"permalink"`
+	LikesCount   int       `json:"likes_count"`
+	ViewsCount   int       `json:"views_count"`
+	AdultContent bool      `json:"adult_content"`
+	PublishedAt  time.Time `json:"published_at"`
+	User         struct {
+		Username string `json:"username"`
+		FullName string `json:"full_name"`
+	} `json:"user"`
+	Assets []struct {
+		AssetType string `json:"asset_type"`
+		HasImage  bool   `json:"has_image"`
+		ImageURL  string `json:"image_url"`
+	} `json:"assets"`
+	Tags          []string `json:"tags"`
+	Mediums       []named  `json:"mediums"`
+	SoftwareItems []named  `json:"software_items"`
+}
+
+type named struct {
+	Name string `json:"name"`
+}
+
+type Artwork struct {
+	artworks.Source
+
+	Title       string
+	Artist      string
+	Username    string
+	Description string
+	Images      []string
+	TagList     []string
+	Mediums     []string
+	Software    []string
+	Likes       int
+	Views       int
+	NSFW        bool
+	AI          bool
+	CreatedAt   time.Time
+}
+
+func init() {
+	artworks.RegisterArtwork("artstation", func() artworks.Artwork { return &Artwork{} })
+}
+
+func New() *ArtStation {
+	return &ArtStation{
+		regex:   regexp.MustCompile(`(?i)https?://(?:(?:www\.)?artstation\.com/artwork|[\w-]+\.artstation\.com/projects)/(\w+)`),
+		client:  artworks.NewHTTPClient(),
+		limiter: artworks.NewLimiter(artworks.DefaultConcurrency),
+	}
+}
+
+// Enabled implements artworks.Provider.
+func (*ArtStation) Enabled(g *store.Guild) bool {
+	return g.ArtStationEnabled()
+}
+
+// Match implements artworks.Provider.
+func (as *ArtStation) Match(url string) (string, bool) {
+	res := as.regex.FindStringSubmatch(url)
+	if res == nil {
+		return "", false
+	}
+
+	return res[1], true
+}
+
+// Find implements artworks.Provider.
+func (as *ArtStation) Find(ctx context.Context, id string) (artworks.Artwork, error) {
+	return artworks.WrapError(as, func() (artworks.Artwork, error) {
+		release, err := as.limiter.Acquire(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://www.artstation.com/projects/"+id+".json", nil)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := as.client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("http get: %w", err)
+		}
+		defer resp.Body.Close()
+
+		switch resp.StatusCode {
+		case http.StatusOK:
+		case http.StatusNotFound:
+			return nil, artworks.ErrArtworkNotFound
+		default:
+			return nil, fmt.Errorf("unexpected response status: %v", resp.Status)
+		}
+
+		project := &Project{}
+		if err := json.NewDecoder(resp.Body).Decode(project); err != nil {
+			return nil, fmt.Errorf("decode: %w", err)
+		}
+
+		return newArtwork(id, project), nil
+	})
+}
+
+func newArtwork(id string, project *Project) *Artwork {
+	// Video assets have a cover image, covers of the project itself duplicate other assets.
+	images := make([]string, 0, len(project.Assets))
+	for _, asset := range project.Assets {
+		if asset.AssetType == "cover" || !asset.HasImage || asset.ImageURL == "" {
+			continue
+		}
+
+		images = append(images, asset.ImageURL)
+	}
+
+	names := func(items []named) []string {
+		res := make([]string, 0, len(items))
+		for _, item := range items {
+			res = append(res, item.Name)
+		}
+
+		return res
+	}
+
+	url := project.Permalink
+	if url == "" {
+		url = "https://www.artstation.com/artwork/" + id
+	}
+
+	tags := ternary.If(project.Tags != nil, project.Tags, []string{})
+	return &Artwork{
+		Source: artworks.Source{SourceID: id, SourceURL: url},
+
+		Title:       project.Title,
+		Artist:      project.User.FullName,
+		Username:    project.User.Username,
+		Description: project.Description,
+		Images:      images,
+		TagList:     tags,
+		Mediums:     names(project.Mediums),
+		Software:    names(project.SoftwareItems),
+		Likes:       project.LikesCount,
+		Views:       project.ViewsCount,
+		NSFW:        project.AdultContent,
+		AI:          artworks.IsAIGenerated(tags...),
+		CreatedAt:   project.PublishedAt,
+	}
+}
+
+// MessageSends implements artworks.Artwork.
+func (a *Artwork) MessageSends(footer string, tagsEnabled bool) ([]*discordgo.MessageSend, error) {
+	var (
+		length = len(a.Images)
+		title  = fmt.Sprintf("%v by %v", a.Title, a.Artist)
+		eb     = embeds.NewBuilder()
+	)
+
+	eb.Title(ternary.If(length > 1,
+		fmt.Sprintf("%v | Page %v / %v", title, 1, length),
+		title,
+	)).URL(a.URL()).Timestamp(a.CreatedAt)
+
+	desc := a.Description
+	if tagsEnabled && len(a.TagList) > 0 {
+		desc = fmt.Sprintf("%v\n\n**Tags**\n%v", desc, strings.Join(a.TagList, " • "))
+	}
+
+	eb.Description(artworks.EscapeMarkdown(desc))
+
+	if a.Likes > 0 {
+		eb.AddField("Likes", strconv.Itoa(a.Likes), true)
+	}
+
+	if a.Views > 0 {
+		eb.AddField("Views", strconv.Itoa(a.Views), true)
+	}
+
+	if len(a.Mediums) > 0 {
+		eb.AddField("Medium", strings.Join(a.Mediums, ", "), true)
+	}
+
+	if len(a.Software) > 0 {
+		eb.AddField("Software", strings.Join(a.Software, ", "), true)
+	}
+
+	if a.AI {
+		eb.AddField("⚠️ Disclaimer", "This artwork is AI-generated.")
+	}
+
+	if footer != "" {
+		eb.Footer(footer, "")
+	}
+
+	if length > 0 {
+		eb.Image(a.Images[0])
+	}
+
+	pages := make([]*discordgo.MessageSend, 0, max(length, 1))
+	pages = append(pages, &discordgo.MessageSend{Embeds: []*discordgo.MessageEmbed{eb.Finalize()}})
+
+	for ind, image := range a.Images[min(length, 1):] {
+		eb := embeds.NewBuilder()
+		eb.Title(fmt.Sprintf("%v | Page %v / %v", title, ind+2, length)).URL(a.URL())
+		eb.Image(image).Timestamp(a.CreatedAt)
+
+		if footer != "" {
+			eb.Footer(footer, "")
+		}
+
+		pages = append(pages, &discordgo.MessageSend{Embeds: []*discordgo.MessageEmbed{eb.Finalize()}})
+	}
+
+	return pages, nil
+}
+
+// StoreArtwork implements artworks.Artwork.
+func (a *Artwork) StoreArtwork() *store.Artwork {
+	return &store.Artwork{
+		Title:  a.Title,
+		Author: a.Artist,
+		URL:    a.URL(),
+		Images: a.Images,
+		AI:     a.AI,
+	}
+}
+
+// Len implements artworks.Artwork.
+func (a *Artwork) Len() int {
+	return len(a.Images)
+}
+
+// IsNSFW implements artworks.Artwork.
+func (a *Artwork) IsNSFW() bool {
+	return a.NSFW
+}
+
+// AIGenerated implements artworks.Artwork.
+func (a *Artwork) AIGenerated() bool {
+	return a.AI
+}
+
+// Tags implements artworks.Artwork. Mediums and software are tags too, so they can be blacklisted or filtered.
+func (a *Artwork) Tags() []string {
+	tags := make([]string, 0, len(a.TagList)+len(a.Mediums)+len(a.Software))
+	tags = append(tags, a.TagList...)
+	tags = append(tags, a.Mediums...)
+	return append(tags, a.Software...)
+}
@@ -0,0 +1,82 @@
+package artstation_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/VTGare/boe-tea-go/artworks/artstation"
+	"github.com/VTGare/boe-tea-go/store"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestArtStation(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "ArtStation Suite")
+}
+
+var _ = DescribeTable(
+	"Match ArtStation URL",
+	func(url string, expectedID string, expectedResult bool) {
+		provider := artstation.New()
+
+		id, ok := provider.Match(url)
+		Expect(id).To(BeEquivalentTo(expectedID))
+		Expect(ok).To(BeEquivalentTo(expectedResult))
+	},
+	Entry("Valid artwork", "https://www.artstation.com/artwork/aBc123", "aBc123", true),
+	Entry("No www", "https://artstation.com/artwork/aBc123", "aBc123", true),
+	Entry("Query params", "https://www.artstation.com/artwork/aBc123?utm=1", "aBc123", true),
+	Entry("Portfolio URL", "https://artist.artstation.com/projects/aBc123", "aBc123", true),
+	Entry("Artist page", "https://www.artstation.com/artist", "", false),
+	Entry("Different domain", "https://www.somethingelse.com/artwork/aBc123", "", false),
+)
+
+var _ = DescribeTable(
+	"Enabled in guild",
+	func(doc string, expected bool) {
+		var guild store.Guild
+		Expect(json.Unmarshal([]byte(doc), &guild)).To(Succeed())
+		Expect(artstation.New().Enabled(&guild)).To(Equal(expected))
+	},
+	Entry("Guild created before the setting", `{"id": "1"}`, true),
+	Entry("Enabled", `{"id": "1", "artstation": true}`, true),
+	Entry("Disabled", `{"id": "1", "artstation": false}`, false),
+)
+
+var _ = Describe("Find", func() {
+	It("should stop when the context is cancelled", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := artstation.New().Find(ctx, "aBc123")
+		Expect(err).To(MatchError(context.Canceled))
+	})
+})
+
+var _ = Describe("Artwork", func() {
+	artwork := &artstation.Artwork{
+		Title:    "Castle",
+		Artist:   "Artist",
+		Images:   []string{"https://cdna.artstation.com/1.jpg", "https://cdna.artstation.com/2.jpg"},
+		TagList:  []string{"fantasy"},
+		Mediums:  []string{"Digital 2D"},
+		Software: []string{"Photoshop"},
+		Likes:    10,
+	}
+
+	It("should embed every asset", func() {
+		sends, err := artwork.MessageSends("", true)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sends).To(HaveLen(2))
+
+		Expect(sends[0].Embeds[0].Title).To(Equal("Castle by Artist | Page 1 / 2"))
+		Expect(sends[0].Embeds[0].Fields).To(ContainElement(HaveField("Value", "Photoshop")))
+		Expect(sends[1].Embeds[0].Image.URL).To(Equal(artwork.Images[1]))
+	})
+
+	It("should tag artworks with mediums and software", func() {
+		Expect(artwork.Tags()).To(Equal([]string{"fantasy", "Digital 2D", "Photoshop"}))
+	})
+})
@@ -1,6 +1,7 @@
 package artworks
 
 import (
+	"context"
 	"strings"
 
 	"github.com/VTGare/boe-tea-go/store"
@@ -9,16 +10,35 @@ import (
 
 type Provider interface {
 	Match(url string) (string, bool)
-	Find(id string) (Artwork, error)
+	// Find fetches an artwork by its ID. Providers stop waiting for the source when the context is done.
+	Find(ctx context.Context, id string) (Artwork, error)
 	Enabled(*store.Guild) bool
 }
 
+// NSFWProvider is implemented by providers of explicit sources. Their artworks are embedded only in NSFW channels.
+type NSFWProvider interface {
+	Provider
+	NSFWOnly() bool
+}
+
+// NSFWOnly reports whether artworks of the provider are embedded only in NSFW channels.
+func NSFWOnly(p Provider) bool {
+	np, ok := p.(NSFWProvider)
+	return ok && np.NSFWOnly()
+}
+
 type Artwork interface {
 	StoreArtwork() *store.Artwork
 	MessageSends(footer string, tags bool) ([]*discordgo.MessageSend, error)
 	ID() string
 	URL() string
 	Len() int
+	// IsNSFW reports whether the source marked the artwork as explicit.
+	IsNSFW() bool
+	// AIGenerated reports whether the artwork was marked or tagged as AI-generated.
+	AIGenerated() bool
+	// Tags returns tags of the artwork or an empty slice if the source doesn't tag artworks.
+	Tags() []string
 }
 
 func EscapeMarkdown(content string) string {
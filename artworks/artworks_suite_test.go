@@ -0,0 +1,153 @@
+package artworks_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/VTGare/boe-tea-go/artworks"
+	"github.com/VTGare/boe-tea-go/artworks/twitter"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestArtworks(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Artworks Suite")
+}
+
+var _ = Describe("Artwork envelope", func() {
+	It("should restore the concrete artwork type", func() {
+		artwork := &twitter.Artwork{
+			Source:   artworks.Source{SourceID: "1"},
+			Username: "watsonameliaEN",
+			Content:  "hello",
+			Photos:   []string{"https://pbs.twimg.com/media/1.jpg"},
+			NSFW:     true,
+		}
+
+		data, err := artworks.MarshalEnvelope(artwork)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(ContainSubstring(`"provider":"twitter"`))
+
+		decoded, err := artworks.UnmarshalEnvelope(data)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(decoded).To(BeAssignableToTypeOf(&twitter.Artwork{}))
+		Expect(decoded).To(Equal(artwork))
+		Expect(decoded.ID()).To(Equal("1"))
+	})
+
+	It("should reject unknown providers", func() {
+		_, err := artworks.UnmarshalEnvelope([]byte(`{"provider":"unknown","artwork":{}}`))
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("Limiter", func() {
+	It("should limit concurrent requests", func() {
+		limiter := artworks.NewLimiter(1)
+
+		release, err := limiter.Acquire(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err = limiter.Acquire(ctx)
+		Expect(err).To(MatchError(context.DeadlineExceeded))
+
+		release()
+		release, err = limiter.Acquire(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		release()
+	})
+})
+
+var _ = Describe("Await", func() {
+	It("should return the result of the function", func() {
+		value, err := artworks.Await(context.Background(), func() (int, error) {
+			return 1, errors.New("failed")
+		})
+
+		Expect(value).To(Equal(1))
+		Expect(err).To(MatchError("failed"))
+	})
+
+	It("should stop waiting when the context is cancelled", func() {
+		block := make(chan struct{})
+		defer close(block)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := artworks.Await(ctx, func() (int, error) {
+			<-block
+			return 1, nil
+		})
+
+		Expect(err).To(MatchError(context.Canceled))
+	})
+})
+
+var _ = Describe("Registry", func() {
+	var (
+		registry *artworks.Registry
+		provider artworks.Provider
+	)
+
+	BeforeEach(func() {
+		registry = artworks.NewRegistry()
+		provider = twitter.New()
+		registry.Register("twitter", provider)
+	})
+
+	status := func() artworks.ProviderStatus {
+		return registry.Status()[0]
+	}
+
+	It("should name registered providers", func() {
+		Expect(registry.Names()).To(Equal([]string{"twitter"}))
+		Expect(registry.Name(provider)).To(Equal("twitter"))
+		Expect(status().Status).To(Equal(artworks.StatusHealthy))
+	})
+
+	It("should skip disabled providers until they're enabled", func() {
+		reason := errors.New("failed to log in")
+		Expect(registry.Disable("twitter", reason)).To(Succeed())
+		Expect(registry.Providers()).To(BeEmpty())
+		Expect(status().Status).To(Equal(artworks.StatusDisabled))
+		Expect(status().LastError).To(Equal(reason))
+
+		Expect(registry.Enable("twitter")).To(Succeed())
+		Expect(registry.Providers()).To(ConsistOf(provider))
+	})
+
+	It("should reject unknown providers", func() {
+		Expect(registry.Enable("fanbox")).To(MatchError(artworks.ErrUnknownProvider))
+		Expect(registry.Disable("fanbox", nil)).To(MatchError(artworks.ErrUnknownProvider))
+	})
+
+	It("should degrade providers until the next success", func() {
+		registry.Report(provider, errors.New("unexpected response status: 503"))
+		Expect(status().Status).To(Equal(artworks.StatusDegraded))
+		Expect(status().LastErrorAt).NotTo(BeZero())
+
+		registry.Report(provider, nil)
+		Expect(status().Status).To(Equal(artworks.StatusHealthy))
+		Expect(status().LastSuccess).NotTo(BeZero())
+	})
+
+	It("should ignore artworks that weren't found", func() {
+		registry.Report(provider, twitter.ErrTweetNotFound)
+		Expect(status().Status).To(Equal(artworks.StatusHealthy))
+		Expect(status().LastError).To(BeNil())
+	})
+
+	It("should ignore unsupported URLs", func() {
+		registry.Report(provider, fmt.Errorf("example.com: %w", artworks.ErrUnsupportedURL))
+		Expect(status().Status).To(Equal(artworks.StatusHealthy))
+		Expect(status().LastError).To(BeNil())
+	})
+})
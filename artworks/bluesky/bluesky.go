@@ -1,6 +1,7 @@
 package bluesky
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -10,15 +11,22 @@ import (
 	"time"
 
 	"github.com/VTGare/boe-tea-go/artworks"
+	"github.com/VTGare/boe-tea-go/messages"
 	"github.com/VTGare/boe-tea-go/store"
 	"github.com/VTGare/embeds"
 	"github.com/bwmarrin/discordgo"
 	"github.com/julien040/go-ternary"
 )
 
+// defaultAPI is the public Bluesky AppView API.
+const defaultAPI = "https://public.api.bsky.app"
+
 type Bluesky struct {
-	regex  *regexp.Regexp
-	client *http.Client
+	regex   *regexp.Regexp
+	client  *http.Client
+	api     string
+	limiter *artworks.Limiter
+	handles *handles
 }
 
 type Response struct {
@@ -29,22 +37,8 @@ type Response struct {
 
 type Post struct {
 	URI    string `json:"uri,omitempty"`
-	Author struct {
-		DID         string    `json:"did,omitempty"`
-		Handle      string    `json:"handle,omitempty"`
-		DisplayName string    `json:"displayName,omitempty"`
-		Avatar      string    `json:"avatar,omitempty"`
-		CreatedAt   time.Time `json:"createdAt,omitempty"`
-	} `json:"author,omitempty"`
-	Embed struct {
-		Type      EmbedType `json:"$type,omitempty"`
-		Playlist  string    `json:"playlist,omitempty"`  // For videos
-		Thumbnail string    `json:"thumbnail,omitempty"` // For videos
-		Images    []struct {
-			Thumb    string `json:"thumb,omitempty"`
-			Fullsize string `json:"fullsize,omitempty"`
-		} `json:"images,omitempty"`
-	} `json:"embed,omitempty"`
+	Author Author `json:"author,omitempty"`
+	Embed  *Embed `json:"embed,omitempty"`
 	Record struct {
 		Facets []struct {
 			Features []struct {
@@ -52,44 +46,69 @@ type Post struct {
 				Tag  string `json:"tag,omitempty"`
 			} `json:"features,omitempty"`
 		} `json:"facets,omitempty"`
-		Text      string    `json:"text,omitempty"`
-		CreatedAt time.Time `json:"createdAt,omitempty"`
+		Text      string      `json:"text,omitempty"`
+		Labels    *SelfLabels `json:"labels,omitempty"`
+		CreatedAt time.Time   `json:"createdAt,omitempty"`
 	} `json:"record,omitempty"`
 
 	ReplyCount  int       `json:"replyCount,omitempty"`
 	RepostCount int       `json:"repostCount,omitempty"`
 	LikeCount   int       `json:"likeCount,omitempty"`
 	IndexedAt   time.Time `json:"indexedAt,omitempty"`
-}
 
-type EmbedType string
-
-const (
-	EmbedTypeVideo EmbedType = "app.bsky.embed.video#view"
-	EmbedTypeImage EmbedType = "app.bsky.embed.images#view"
-)
+	Labels []Label `json:"labels,omitempty"`
+}
 
 type Artwork struct {
-	id  string
-	url string
+	artworks.Source
 
 	AuthorHandle      string
 	AuthorDisplayName string
 	Text              string
-	Tags              []string
-	Images            []string
-
-	Likes       int
-	Reposts     int
-	Replies     int
-	AIGenerated bool
-	CreatedAt   time.Time
+	TagList           []string
+	// Images of the post followed by images of the quoted post.
+	Images   []string
+	Quote    *Quote
+	External *External
+
+	Likes     int
+	Reposts   int
+	Replies   int
+	NSFW      bool
+	AI        bool
+	CreatedAt time.Time
+}
+
+// Quote is a post quoted by the artwork.
+type Quote struct {
+	AuthorHandle string
+	Text         string
+	URL          string
+}
+
+// External is a link card attached to the post.
+type External struct {
+	URL         string
+	Title       string
+	Description string
+	Thumbnail   string
+}
+
+func init() {
+	artworks.RegisterArtwork("bluesky", func() artworks.Artwork { return &Artwork{} })
 }
 
 func New() *Bluesky {
+	return newBluesky(artworks.NewHTTPClient(), defaultAPI)
+}
+
+func newBluesky(client *http.Client, api string) *Bluesky {
 	return &Bluesky{
-		regex:  regexp.MustCompile(`(?i)https://(?:www\.)?bsky\.app/profile/(\w.+)/post/([\w\-]+)`),
-		client: http.DefaultClient,
+		regex:   regexp.MustCompile(`(?i)https://(?:www\.)?bsky\.app/profile/(\w.+)/post/([\w\-]+)`),
+		client:  client,
+		api:     api,
+		limiter: artworks.NewLimiter(artworks.DefaultConcurrency),
+		handles: newHandles(client, api),
 	}
 }
 
@@ -99,12 +118,40 @@ func (*Bluesky) Enabled(g *store.Guild) bool {
 }
 
 // Find implements artworks.Provider.
-func (b *Bluesky) Find(id string) (artworks.Artwork, error) {
+func (b *Bluesky) Find(ctx context.Context, id string) (artworks.Artwork, error) {
 	return artworks.WrapError(b, func() (artworks.Artwork, error) {
-		did, key, _ := strings.Cut(id, ":")
+		release, err := b.limiter.Acquire(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+
+		// DIDs have colons too, the record key goes last.
+		sep := strings.LastIndex(id, ":")
+		if sep == -1 {
+			return nil, artworks.ErrArtworkNotFound
+		}
+
+		// Posts of handles are found by the DID, handles can be moved to another account.
+		did, err := b.handles.DID(ctx, id[:sep])
+		if err != nil {
+			return nil, err
+		}
+
+		key := id[sep+1:]
 		atURI := fmt.Sprintf("at://%v/app.bsky.feed.post/%v", did, key)
 
-		resp, err := b.client.Get("https://public.api.bsky.app/xrpc/app.bsky.feed.getPostThread?uri=" + atURI + "&depth=0")
+		req, err := http.NewRequestWithContext(
+			ctx,
+			http.MethodGet,
+			b.api+"/xrpc/app.bsky.feed.getPostThread?uri="+atURI+"&depth=0",
+			nil,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := b.client.Do(req)
 		if err != nil {
 			return nil, fmt.Errorf("http get: %w", err)
 		}
@@ -127,62 +174,85 @@ func (b *Bluesky) Find(id string) (artworks.Artwork, error) {
 			return nil, fmt.Errorf("decode: %w", err)
 		}
 
-		tags := make([]string, 0)
-		for _, facet := range decoded.Thread.Post.Record.Facets {
-			for _, feature := range facet.Features {
-				if feature.Type != "app.bsky.richtext.facet#tag" {
-					continue
-				}
-
-				tags = append(tags, feature.Tag)
-			}
+		// Blocked and deleted posts come as other thread types without a post.
+		if decoded.Thread.Post == nil {
+			return nil, artworks.ErrArtworkNotFound
 		}
 
-		var images []string
-		switch decoded.Thread.Post.Embed.Type {
-		case EmbedTypeVideo:
-			images = []string{decoded.Thread.Post.Embed.Thumbnail}
-		case EmbedTypeImage:
-			images = make([]string, 0, len(decoded.Thread.Post.Embed.Images))
-			for _, image := range decoded.Thread.Post.Embed.Images {
-				images = append(images, image.Fullsize)
+		return newArtwork(id, fmt.Sprintf("https://bsky.app/profile/%v/post/%v", did, key), decoded.Thread.Post), nil
+	})
+}
+
+func newArtwork(id, url string, post *Post) *Artwork {
+	tags := make([]string, 0)
+	for _, facet := range post.Record.Facets {
+		for _, feature := range facet.Features {
+			if feature.Type != "app.bsky.richtext.facet#tag" {
+				continue
 			}
+
+			tags = append(tags, feature.Tag)
 		}
+	}
 
-		return &Artwork{
-			id:  id,
-			url: fmt.Sprintf("https://bsky.app/profile/%v/post/%v", did, key),
+	artwork := &Artwork{
+		Source: artworks.Source{SourceID: id, SourceURL: url},
 
-			AuthorHandle:      decoded.Thread.Post.Author.Handle,
-			AuthorDisplayName: decoded.Thread.Post.Author.DisplayName,
+		AuthorHandle:      post.Author.Handle,
+		AuthorDisplayName: post.Author.DisplayName,
 
-			Tags:   tags,
-			Images: images,
+		TagList:  tags,
+		Images:   post.Embed.images(),
+		External: post.Embed.external(),
 
-			Text:        decoded.Thread.Post.Record.Text,
-			Likes:       decoded.Thread.Post.LikeCount,
-			Reposts:     decoded.Thread.Post.RepostCount,
-			Replies:     decoded.Thread.Post.ReplyCount,
-			CreatedAt:   decoded.Thread.Post.Record.CreatedAt,
-			AIGenerated: false,
-		}, nil
-	})
+		Text:    post.Record.Text,
+		Likes:   post.LikeCount,
+		Reposts: post.RepostCount,
+		Replies: post.ReplyCount,
+		// Self-labels and moderation labels of adult content.
+		NSFW:      isNSFW(post.Labels, post.Record.Labels),
+		AI:        artworks.IsAIGenerated(tags...),
+		CreatedAt: post.Record.CreatedAt,
+	}
+
+	if artwork.Images == nil {
+		artwork.Images = make([]string, 0)
+	}
+
+	if quote := post.Embed.quote(); quote != nil {
+		artwork.Quote = &Quote{
+			AuthorHandle: quote.Author.Handle,
+			Text:         quote.Value.Text,
+			URL:          postURL(quote.URI),
+		}
+
+		artwork.Images = append(artwork.Images, quote.images()...)
+		artwork.NSFW = artwork.NSFW || isNSFW(quote.Labels, quote.Value.Labels)
+	}
+
+	return artwork
 }
 
-// Match implements artworks.Provider.
+// Match implements artworks.Provider. IDs are the author's handle or DID and the record key,
+// e.g. "artist.bsky.social:abc" or "did:plc:123:abc". Handles are resolved by Find.
 func (b *Bluesky) Match(url string) (string, bool) {
 	res := b.regex.FindStringSubmatch(url)
 	if res == nil {
 		return "", false
 	}
 
-	return res[1] + ":" + res[2], true
+	profile := res[1]
+	if !strings.HasPrefix(profile, "did:") {
+		profile = strings.ToLower(profile)
+	}
+
+	return profile + ":" + res[2], true
 }
 
 // MessageSends implements artworks.Artwork.
 func (a *Artwork) MessageSends(footer string, tagsEnabled bool) ([]*discordgo.MessageSend, error) {
 	eb := embeds.NewBuilder()
-	eb.URL(a.url).Timestamp(a.CreatedAt)
+	eb.URL(a.URL()).Timestamp(a.CreatedAt)
 
 	if a.Reposts > 0 {
 		eb.AddField("Reposts", strconv.Itoa(a.Reposts), true)
@@ -192,11 +262,22 @@ func (a *Artwork) MessageSends(footer string, tagsEnabled bool) ([]*discordgo.Me
 		eb.AddField("Likes", strconv.Itoa(a.Likes), true)
 	}
 
+	if a.Quote != nil {
+		eb.AddField(
+			fmt.Sprintf("Quoting @%v", a.Quote.AuthorHandle),
+			fmt.Sprintf("%v\n%v", a.Quote.Text, messages.ClickHere(a.Quote.URL)),
+		)
+	}
+
+	if a.External != nil {
+		eb.AddField("🔗 "+ternary.If(a.External.Title != "", a.External.Title, "Link"), messages.ClickHere(a.External.URL))
+	}
+
 	if footer != "" {
 		eb.Footer(footer, "")
 	}
 
-	if a.AIGenerated {
+	if a.AI {
 		eb.AddField("⚠️ Disclaimer", "This artwork is AI-generated.")
 	}
 
@@ -207,13 +288,16 @@ func (a *Artwork) MessageSends(footer string, tagsEnabled bool) ([]*discordgo.Me
 		fmt.Sprintf("%v (%v)", a.AuthorDisplayName, a.AuthorHandle),
 	))
 
-	if length > 0 {
+	switch {
+	case length > 0:
 		eb.Image(a.Images[0])
+	case a.External != nil && a.External.Thumbnail != "":
+		eb.Thumbnail(a.External.Thumbnail)
 	}
 
 	desc := a.Text
-	if tagsEnabled && len(a.Tags) > 0 {
-		desc = fmt.Sprintf("%v\n\n**Tags**\n%v", desc, strings.Join(a.Tags, " • "))
+	if tagsEnabled && len(a.TagList) > 0 {
+		desc = fmt.Sprintf("%v\n\n**Tags**\n%v", desc, strings.Join(a.TagList, " • "))
 	}
 
 	eb.Description(desc)
@@ -226,7 +310,7 @@ func (a *Artwork) MessageSends(footer string, tagsEnabled bool) ([]*discordgo.Me
 		for ind, photo := range a.Images[1:] {
 			eb := embeds.NewBuilder()
 
-			eb.Title(fmt.Sprintf("%v (%v) | Page %v / %v", a.AuthorDisplayName, a.AuthorHandle, ind+2, length)).URL(a.url)
+			eb.Title(fmt.Sprintf("%v (%v) | Page %v / %v", a.AuthorDisplayName, a.AuthorHandle, ind+2, length)).URL(a.URL())
 			eb.Image(photo).Timestamp(a.CreatedAt)
 
 			if footer != "" {
@@ -240,11 +324,6 @@ func (a *Artwork) MessageSends(footer string, tagsEnabled bool) ([]*discordgo.Me
 	return posts, nil
 }
 
-// ID implements artworks.Artwork.
-func (a *Artwork) ID() string {
-	return a.id
-}
-
 // Len implements artworks.Artwork.
 func (a *Artwork) Len() int {
 	return len(a.Images)
@@ -255,11 +334,22 @@ func (a *Artwork) StoreArtwork() *store.Artwork {
 	return &store.Artwork{
 		Author: a.AuthorHandle,
 		Images: a.Images,
-		URL:    a.url,
+		URL:    a.URL(),
+		AI:     a.AI,
 	}
 }
 
-// URL implements artworks.Artwork.
-func (a *Artwork) URL() string {
-	return a.url
+// IsNSFW implements artworks.Artwork.
+func (a *Artwork) IsNSFW() bool {
+	return a.NSFW
+}
+
+// AIGenerated implements artworks.Artwork.
+func (a *Artwork) AIGenerated() bool {
+	return a.AI
+}
+
+// Tags implements artworks.Artwork.
+func (a *Artwork) Tags() []string {
+	return a.TagList
 }
@@ -1,6 +1,7 @@
 package bluesky_test
 
 import (
+	"context"
 	"testing"
 
 	"github.com/VTGare/boe-tea-go/artworks/bluesky"
@@ -24,6 +25,61 @@ var _ = DescribeTable(
 	},
 	Entry("Valid artwork", "https://bsky.app/profile/profile.bsky.social/post/1234", "profile.bsky.social:1234", true),
 	Entry("Query params", "https://bsky.app/profile/profile.bsky.social/post/1234?iw=234", "profile.bsky.social:1234", true),
+	Entry("Uppercase handle", "https://bsky.app/profile/Profile.bsky.social/post/1234", "profile.bsky.social:1234", true),
+	Entry("DID", "https://bsky.app/profile/did:plc:abc123/post/3kabc", "did:plc:abc123:3kabc", true),
 	Entry("Invalid URL", "https://bsky.app/profile.bsky.social/post/1234", "", false),
 	Entry("Different domain", "https://www.somethingelse.com/q98e9N", "", false),
 )
+
+var _ = Describe("Find", func() {
+	It("should stop when the context is cancelled", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := bluesky.New().Find(ctx, "profile.bsky.social:1234")
+		Expect(err).To(MatchError(context.Canceled))
+	})
+})
+
+var _ = Describe("Artwork", func() {
+	It("should embed quoted posts and their images", func() {
+		artwork := &bluesky.Artwork{
+			AuthorHandle:      "artist.bsky.social",
+			AuthorDisplayName: "Artist",
+			Images:            []string{"https://cdn.bsky.app/1.jpg", "https://cdn.bsky.app/quoted.jpg"},
+			Quote: &bluesky.Quote{
+				AuthorHandle: "friend.bsky.social",
+				Text:         "Check this out",
+				URL:          "https://bsky.app/profile/did:plc:456/post/def",
+			},
+		}
+
+		sends, err := artwork.MessageSends("", false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sends).To(HaveLen(2))
+
+		Expect(sends[0].Embeds[0].Title).To(Equal("Artist (artist.bsky.social) | Page 1 / 2"))
+		Expect(sends[0].Embeds[0].Fields).To(ContainElement(HaveField("Name", "Quoting @friend.bsky.social")))
+		Expect(sends[1].Embeds[0].Image.URL).To(Equal("https://cdn.bsky.app/quoted.jpg"))
+	})
+
+	It("should show link cards without images as thumbnails", func() {
+		artwork := &bluesky.Artwork{
+			AuthorHandle:      "artist.bsky.social",
+			AuthorDisplayName: "Artist",
+			Images:            []string{},
+			External: &bluesky.External{
+				URL:       "https://example.com",
+				Title:     "Example",
+				Thumbnail: "https://cdn.bsky.app/thumb.jpg",
+			},
+		}
+
+		sends, err := artwork.MessageSends("", false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sends).To(HaveLen(1))
+
+		Expect(sends[0].Embeds[0].Thumbnail.URL).To(Equal("https://cdn.bsky.app/thumb.jpg"))
+		Expect(sends[0].Embeds[0].Fields).To(ContainElement(HaveField("Name", "🔗 Example")))
+	})
+})
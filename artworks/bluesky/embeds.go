@@ -0,0 +1,167 @@
+package bluesky
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+type EmbedType string
+
+const (
+	EmbedTypeVideo           EmbedType = "app.bsky.embed.video#view"
+	EmbedTypeImage           EmbedType = "app.bsky.embed.images#view"
+	EmbedTypeExternal        EmbedType = "app.bsky.embed.external#view"
+	EmbedTypeRecord          EmbedType = "app.bsky.embed.record#view"
+	EmbedTypeRecordWithMedia EmbedType = "app.bsky.embed.recordWithMedia#view"
+)
+
+// viewRecordType is the type of quoted records that are visible posts.
+const viewRecordType = "app.bsky.embed.record#viewRecord"
+
+type Embed struct {
+	Type      EmbedType `json:"$type,omitempty"`
+	Playlist  string    `json:"playlist,omitempty"`  // For videos
+	Thumbnail string    `json:"thumbnail,omitempty"` // For videos
+	Images    []struct {
+		Thumb    string `json:"thumb,omitempty"`
+		Fullsize string `json:"fullsize,omitempty"`
+	} `json:"images,omitempty"`
+	External *struct {
+		URI         string `json:"uri,omitempty"`
+		Title       string `json:"title,omitempty"`
+		Description string `json:"description,omitempty"`
+		Thumb       string `json:"thumb,omitempty"`
+	} `json:"external,omitempty"`
+	Media  *Embed  `json:"media,omitempty"`  // For records with media
+	Record *Record `json:"record,omitempty"` // For quotes
+}
+
+// Record is a quoted post. Records with media wrap it into another record.
+type Record struct {
+	Type   string  `json:"$type,omitempty"`
+	URI    string  `json:"uri,omitempty"`
+	Author Author  `json:"author,omitempty"`
+	Record *Record `json:"record,omitempty"`
+	Value  struct {
+		Text   string      `json:"text,omitempty"`
+		Labels *SelfLabels `json:"labels,omitempty"`
+	} `json:"value,omitempty"`
+	Labels []Label  `json:"labels,omitempty"`
+	Embeds []*Embed `json:"embeds,omitempty"`
+}
+
+type Author struct {
+	DID         string `json:"did,omitempty"`
+	Handle      string `json:"handle,omitempty"`
+	DisplayName string `json:"displayName,omitempty"`
+	Avatar      string `json:"avatar,omitempty"`
+}
+
+type Label struct {
+	Val string `json:"val,omitempty"`
+}
+
+// SelfLabels are labels set by the author in the post record.
+type SelfLabels struct {
+	Values []Label `json:"values,omitempty"`
+}
+
+// images returns images of the embed. Videos are embedded as their thumbnails.
+func (e *Embed) images() []string {
+	if e == nil {
+		return nil
+	}
+
+	switch e.Type {
+	case EmbedTypeVideo:
+		if e.Thumbnail != "" {
+			return []string{e.Thumbnail}
+		}
+	case EmbedTypeImage:
+		images := make([]string, 0, len(e.Images))
+		for _, image := range e.Images {
+			images = append(images, image.Fullsize)
+		}
+
+		return images
+	case EmbedTypeRecordWithMedia:
+		return e.Media.images()
+	}
+
+	return nil
+}
+
+// external returns a link card of the embed.
+func (e *Embed) external() *External {
+	if e == nil {
+		return nil
+	}
+
+	switch {
+	case e.Type == EmbedTypeExternal && e.External != nil:
+		return &External{
+			URL:         e.External.URI,
+			Title:       e.External.Title,
+			Description: e.External.Description,
+			Thumbnail:   e.External.Thumb,
+		}
+	case e.Type == EmbedTypeRecordWithMedia:
+		return e.Media.external()
+	}
+
+	return nil
+}
+
+// quote returns the quoted post if it's visible.
+func (e *Embed) quote() *Record {
+	if e == nil || e.Record == nil {
+		return nil
+	}
+
+	var record *Record
+	switch e.Type {
+	case EmbedTypeRecord:
+		record = e.Record
+	case EmbedTypeRecordWithMedia:
+		record = e.Record.Record
+	}
+
+	if record == nil || record.Type != viewRecordType {
+		return nil
+	}
+
+	return record
+}
+
+// images returns images of the quoted post.
+func (r *Record) images() []string {
+	images := make([]string, 0)
+	for _, embed := range r.Embeds {
+		images = append(images, embed.images()...)
+	}
+
+	return images
+}
+
+// isNSFW reports whether labels or self-labels mark a post as adult content.
+func isNSFW(labels []Label, self *SelfLabels) bool {
+	if self != nil {
+		labels = slices.Concat(labels, self.Values)
+	}
+
+	for _, label := range labels {
+		switch label.Val {
+		case "porn", "sexual", "nudity", "graphic-media":
+			return true
+		}
+	}
+
+	return false
+}
+
+// postURL converts a post AT URI, e.g. "at://did:plc:123/app.bsky.feed.post/abc", to a link.
+func postURL(atURI string) string {
+	did, key, _ := strings.Cut(strings.TrimPrefix(atURI, "at://"), "/app.bsky.feed.post/")
+	return fmt.Sprintf("https://bsky.app/profile/%v/post/%v", did, key)
+}
@@ -0,0 +1,4 @@
+package bluesky
+
+// NewWithAPI creates a provider requesting the Bluesky API at api.
+var NewWithAPI = newBluesky
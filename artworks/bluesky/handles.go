@@ -0,0 +1,108 @@
+package bluesky
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/VTGare/boe-tea-go/artworks"
+	"github.com/VTGare/boe-tea-go/internal/cache"
+)
+
+const (
+	// handleTTL is how long a resolved handle is remembered. Handles can be moved to another DID.
+	handleTTL = 24 * time.Hour
+	// unknownHandleTTL is how long a handle that doesn't exist is remembered.
+	unknownHandleTTL = time.Minute
+	// maxHandles is the number of handles remembered.
+	maxHandles = 4096
+)
+
+// handles resolves handles to DIDs. Post URLs use DIDs because handles can change.
+type handles struct {
+	client *http.Client
+	api    string
+	// dids are resolved DIDs of handles. Handles that don't exist have empty DIDs.
+	dids *cache.LRU[string]
+}
+
+func newHandles(client *http.Client, api string) *handles {
+	return &handles{
+		client: client,
+		api:    api,
+		dids:   cache.NewLRU[string](maxHandles),
+	}
+}
+
+// DID returns the DID of a profile. DIDs are returned as is, handles are resolved and cached.
+// Handles that don't exist return artworks.ErrArtworkNotFound.
+func (h *handles) DID(ctx context.Context, profile string) (string, error) {
+	if strings.HasPrefix(profile, "did:") {
+		return profile, nil
+	}
+
+	handle := strings.ToLower(profile)
+	if did, ok := h.dids.Get(handle); ok {
+		if did == "" {
+			return "", artworks.ErrArtworkNotFound
+		}
+
+		return did, nil
+	}
+
+	did, err := h.resolve(ctx, handle)
+	switch {
+	case err == nil:
+		h.dids.Set(handle, did, handleTTL)
+	// Only missing handles are remembered, other failures are likely temporary.
+	case errors.Is(err, artworks.ErrArtworkNotFound):
+		h.dids.Set(handle, "", unknownHandleTTL)
+	}
+
+	return did, err
+}
+
+func (h *handles) resolve(ctx context.Context, handle string) (string, error) {
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodGet,
+		h.api+"/xrpc/com.atproto.identity.resolveHandle?handle="+url.QueryEscape(handle),
+		nil,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("resolve handle: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusBadRequest:
+		return "", artworks.ErrArtworkNotFound
+	default:
+		return "", fmt.Errorf("resolve handle: unexpected response status: %v", resp.Status)
+	}
+
+	var res struct {
+		DID string `json:"did"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return "", fmt.Errorf("resolve handle: decode: %w", err)
+	}
+
+	if !strings.HasPrefix(res.DID, "did:") {
+		return "", fmt.Errorf("resolve handle: invalid did %q", res.DID)
+	}
+
+	return res.DID, nil
+}
@@ -0,0 +1,116 @@
+package bluesky_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	"github.com/VTGare/boe-tea-go/artworks"
+	"github.com/VTGare/boe-tea-go/artworks/bluesky"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// fakeAPI answers handle resolutions and post threads of the Bluesky API.
+type fakeAPI struct {
+	mu       sync.Mutex
+	resolved map[string]int
+}
+
+func (f *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/xrpc/com.atproto.identity.resolveHandle":
+		handle := r.URL.Query().Get("handle")
+
+		f.mu.Lock()
+		f.resolved[handle]++
+		f.mu.Unlock()
+
+		switch handle {
+		case "artist.bsky.social":
+			w.Write([]byte(`{"did": "did:plc:abc123"}`))
+		case "down.bsky.social":
+			w.WriteHeader(http.StatusBadGateway)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error": "InvalidRequest", "message": "Unable to resolve handle"}`))
+		}
+	case "/xrpc/app.bsky.feed.getPostThread":
+		if r.URL.Query().Get("uri") != "at://did:plc:abc123/app.bsky.feed.post/3kabc" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		w.Write([]byte(`{"thread": {"post": {
+			"uri": "at://did:plc:abc123/app.bsky.feed.post/3kabc",
+			"author": {"did": "did:plc:abc123", "handle": "artist.bsky.social", "displayName": "Artist"},
+			"embed": {"$type": "app.bsky.embed.images#view", "images": [{"fullsize": "https://cdn.bsky.app/1.jpg"}]},
+			"record": {"text": "New drawing", "labels": {"values": [{"val": "sexual"}]}},
+			"likeCount": 3
+		}}}`))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (f *fakeAPI) Resolved(handle string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.resolved[handle]
+}
+
+var _ = Describe("Handles", func() {
+	var (
+		ctx = context.Background()
+		api *fakeAPI
+		b   *bluesky.Bluesky
+	)
+
+	BeforeEach(func() {
+		api = &fakeAPI{resolved: make(map[string]int)}
+
+		srv := httptest.NewServer(api)
+		DeferCleanup(srv.Close)
+
+		b = bluesky.NewWithAPI(srv.Client(), srv.URL)
+	})
+
+	It("should find posts of handles by their DIDs", func() {
+		for _, id := range []string{"artist.bsky.social:3kabc", "did:plc:abc123:3kabc"} {
+			found, err := b.Find(ctx, id)
+			Expect(err).NotTo(HaveOccurred())
+
+			artwork := found.(*bluesky.Artwork)
+			Expect(artwork.ID()).To(Equal(id))
+			Expect(artwork.URL()).To(Equal("https://bsky.app/profile/did:plc:abc123/post/3kabc"))
+			Expect(artwork.Images).To(Equal([]string{"https://cdn.bsky.app/1.jpg"}))
+			Expect(artwork.AuthorHandle).To(Equal("artist.bsky.social"))
+			Expect(artwork.NSFW).To(BeTrue())
+		}
+
+		_, err := b.Find(ctx, "artist.bsky.social:3kabc")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(api.Resolved("artist.bsky.social")).To(Equal(1))
+	})
+
+	It("should remember handles that don't exist", func() {
+		for range 2 {
+			_, err := b.Find(ctx, "missing.bsky.social:3kabc")
+			Expect(err).To(MatchError(artworks.ErrArtworkNotFound))
+		}
+
+		Expect(api.Resolved("missing.bsky.social")).To(Equal(1))
+	})
+
+	It("should report and retry handles that failed to resolve", func() {
+		for range 2 {
+			_, err := b.Find(ctx, "down.bsky.social:3kabc")
+			Expect(err).To(HaveOccurred())
+			Expect(err).NotTo(MatchError(artworks.ErrArtworkNotFound))
+		}
+
+		Expect(api.Resolved("down.bsky.social")).To(Equal(2))
+	})
+})
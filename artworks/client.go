@@ -0,0 +1,109 @@
+package artworks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+const (
+	// DefaultTimeout bounds a single request to a provider, including reading the response body.
+	DefaultTimeout = 20 * time.Second
+	// DefaultConcurrency is the number of concurrent requests a provider makes by default.
+	DefaultConcurrency = 8
+)
+
+// NewHTTPClient returns an HTTP client for a provider. Requests time out even if the caller's context doesn't.
+func NewHTTPClient() *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{
+		Timeout:   5 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}).DialContext
+	transport.TLSHandshakeTimeout = 5 * time.Second
+	transport.ResponseHeaderTimeout = 15 * time.Second
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   DefaultTimeout,
+	}
+}
+
+// ErrPrivateAddress is returned by clients of NewPublicHTTPClient connecting to a private address.
+var ErrPrivateAddress = errors.New("host resolves to a private address")
+
+// NewPublicHTTPClient returns an HTTP client that refuses to connect to loopback, private and link-local addresses.
+// It's used for hosts that come from user messages, addresses are checked after they're resolved to prevent DNS rebinding.
+func NewPublicHTTPClient() *http.Client {
+	client := NewHTTPClient()
+	client.Transport.(*http.Transport).DialContext = (&net.Dialer{
+		Timeout:   5 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   publicOnly,
+	}).DialContext
+
+	return client
+}
+
+func publicOnly(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+
+	addr := addrPort.Addr().Unmap()
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsUnspecified() || addr.IsMulticast() {
+		return fmt.Errorf("%w: %v", ErrPrivateAddress, addr)
+	}
+
+	return nil
+}
+
+// Limiter limits the number of concurrent requests to a provider.
+type Limiter struct {
+	sem chan struct{}
+}
+
+func NewLimiter(n int) *Limiter {
+	return &Limiter{sem: make(chan struct{}, n)}
+}
+
+// Acquire blocks until a request can be made or the context is done.
+// The returned function releases the slot and must be called once the request is complete.
+func (l *Limiter) Acquire(ctx context.Context) (func(), error) {
+	select {
+	case l.sem <- struct{}{}:
+		return func() { <-l.sem }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Await calls fn in a goroutine and returns when it's done or the context is cancelled.
+// It's meant for client libraries without context support, fn must still be bounded by a client timeout.
+func Await[T any](ctx context.Context, fn func() (T, error)) (T, error) {
+	type result struct {
+		value T
+		err   error
+	}
+
+	done := make(chan result, 1)
+	go func() {
+		value, err := fn()
+		done <- result{value, err}
+	}()
+
+	select {
+	case res := <-done:
+		return res.value, res.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
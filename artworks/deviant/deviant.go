@@ -1,10 +1,9 @@
 package deviant
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"net/http"
-	"net/url"
 	"regexp"
 	"strconv"
 	"strings"
@@ -16,26 +15,38 @@ import (
 	"github.com/VTGare/boe-tea-go/store"
 	"github.com/VTGare/embeds"
 	"github.com/bwmarrin/discordgo"
+	"github.com/julien040/go-ternary"
 )
 
+// DeviantArt is a provider of deviations. Metadata comes from oEmbed, full-size images and
+// literature excerpts come from the deviation page data when it's available.
 type DeviantArt struct {
-	regex *regexp.Regexp
+	regex      *regexp.Regexp
+	shortRegex *regexp.Regexp
+	client     *http.Client
+	limiter    *artworks.Limiter
 }
 
 type Artwork struct {
+	artworks.Source
+
 	Title        string
 	Author       *Author
-	ImageURL     string
+	Images       []string
 	ThumbnailURL string
-	Tags         []string
+	Literature   bool
+	Excerpt      string
+	TagList      []string
 	Views        int
 	Favorites    int
 	Comments     int
-	AIGenerated  bool
+	NSFW         bool
+	AI           bool
 	CreatedAt    time.Time
+}
 
-	id  string
-	url string
+func init() {
+	artworks.RegisterArtwork("deviant", func() artworks.Artwork { return &Artwork{} })
 }
 
 type Author struct {
@@ -43,80 +54,141 @@ type Author struct {
 	URL  string
 }
 
-type deviantEmbed struct {
-	Title        string    `json:"title,omitempty"`
-	Category     string    `json:"category,omitempty"`
-	URL          string    `json:"url,omitempty"`
-	ThumbnailURL string    `json:"thumbnail_url,omitempty"`
-	AuthorName   string    `json:"author_name,omitempty"`
-	AuthorURL    string    `json:"author_url,omitempty"`
-	Safety       string    `json:"safety,omitempty"`
-	Pubdate      time.Time `json:"pubdate,omitempty"`
-	Community    struct {
-		Statistics struct {
-			Attributes struct {
-				Views     int `json:"views,omitempty"`
-				Favorites int `json:"favorites,omitempty"`
-				Comments  int `json:"comments,omitempty"`
-				Downloads int `json:"downloads,omitempty"`
-			} `json:"_attributes,omitempty"`
-		} `json:"statistics,omitempty"`
-	} `json:"community,omitempty"`
-	Tags string `json:"tags,omitempty"`
-}
-
 func New() artworks.Provider {
 	return &DeviantArt{
-		regex: regexp.MustCompile(`(?i)https?://(?:www\.)?deviantart\.com/\w.+/art/([\w\-]+)`),
+		regex:      regexp.MustCompile(`(?i)https?://(?:www\.)?deviantart\.com/\w.+/art/([\w\-]+)`),
+		shortRegex: regexp.MustCompile(`(?i)https?://(fav\.me|sta\.sh)/(\w+)`),
+		client:     artworks.NewHTTPClient(),
+		limiter:    artworks.NewLimiter(artworks.DefaultConcurrency),
 	}
 }
 
-func (d *DeviantArt) Find(id string) (artworks.Artwork, error) {
+func (d *DeviantArt) Find(ctx context.Context, id string) (artworks.Artwork, error) {
 	return artworks.WrapError(d, func() (artworks.Artwork, error) {
-		reqURL := "https://backend.deviantart.com/oembed?url=" + url.QueryEscape("deviantart.com/art/"+id)
-		resp, err := http.Get(reqURL)
+		release, err := d.limiter.Acquire(ctx)
 		if err != nil {
 			return nil, err
 		}
-		defer resp.Body.Close()
+		defer release()
 
-		var res deviantEmbed
-		err = json.NewDecoder(resp.Body).Decode(&res)
+		res, err := d.oEmbed(ctx, id)
 		if err != nil {
 			return nil, err
 		}
 
-		artwork := &Artwork{
-			Title: res.Title,
-			Author: &Author{
-				Name: res.AuthorName,
-				URL:  res.AuthorURL,
-			},
-			ImageURL:     res.URL,
-			ThumbnailURL: res.ThumbnailURL,
-			Tags:         strings.Split(res.Tags, ", "),
-			Views:        res.Community.Statistics.Attributes.Views,
-			Favorites:    res.Community.Statistics.Attributes.Favorites,
-			Comments:     res.Community.Statistics.Attributes.Comments,
-			CreatedAt:    res.Pubdate,
-
-			id:  id,
-			url: res.AuthorURL + "/art/" + id,
+		artwork := newArtwork(id, res)
+
+		deviationID, ok := deviationID(id)
+		if !ok {
+			return artwork, nil
 		}
 
-		artwork.AIGenerated = artworks.IsAIGenerated(artwork.Tags...)
+		dev, err := d.deviation(ctx, deviationID, res.AuthorName)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
 
+			// The page data isn't a public API, a preview from oEmbed is better than nothing.
+			return artwork, nil
+		}
+
+		artwork.addDeviation(dev)
 		return artwork, nil
 	})
 }
 
+func newArtwork(id string, res *deviantEmbed) *Artwork {
+	url := res.AuthorURL + "/art/" + id
+	if isShortLink(id) {
+		url = pageURL(id)
+	}
+
+	images := make([]string, 0, 1)
+	if res.Type != "rich" && res.URL != "" {
+		images = append(images, res.URL)
+	}
+
+	tags := make([]string, 0)
+	if res.Tags != "" {
+		tags = strings.Split(res.Tags, ", ")
+	}
+
+	artwork := &Artwork{
+		Title: res.Title,
+		Author: &Author{
+			Name: res.AuthorName,
+			URL:  res.AuthorURL,
+		},
+		Images:       images,
+		ThumbnailURL: res.ThumbnailURL,
+		TagList:      tags,
+		Views:        res.Community.Statistics.Attributes.Views,
+		Favorites:    res.Community.Statistics.Attributes.Favorites,
+		Comments:     res.Community.Statistics.Attributes.Comments,
+		// Safety is "nonadult" unless the deviation is marked mature.
+		NSFW:      res.Safety != "" && res.Safety != "nonadult",
+		CreatedAt: res.Pubdate,
+
+		Source: artworks.Source{SourceID: id, SourceURL: url},
+	}
+
+	if res.Type == "rich" && res.HTML != "" {
+		artwork.Literature = true
+		artwork.Excerpt = excerpt(res.HTML)
+	}
+
+	artwork.AI = artworks.IsAIGenerated(artwork.TagList...)
+	return artwork
+}
+
+// addDeviation replaces the oEmbed preview with full-size images of the deviation.
+func (a *Artwork) addDeviation(dev *deviation) {
+	images := make([]string, 0, len(dev.Deviation.Extended.AdditionalMedia)+1)
+	if image := dev.Deviation.Media.URL(); image != "" {
+		images = append(images, image)
+	}
+
+	for _, additional := range dev.Deviation.Extended.AdditionalMedia {
+		if image := additional.Media.URL(); image != "" {
+			images = append(images, image)
+		}
+	}
+
+	if len(images) > 0 {
+		a.Images = images
+	}
+
+	if dev.Deviation.Type == "literature" {
+		a.Literature = true
+		if dev.Deviation.TextContent.Excerpt != "" {
+			a.Excerpt = excerpt(dev.Deviation.TextContent.Excerpt)
+		}
+	}
+
+	a.NSFW = a.NSFW || dev.Deviation.IsMature
+}
+
+// Match implements artworks.Provider. fav.me links are resolved to the deviation, sta.sh links
+// and fav.me links that failed to resolve are kept as is, e.g. "sta.sh/0abc".
 func (d *DeviantArt) Match(s string) (string, bool) {
-	res := d.regex.FindStringSubmatch(s)
+	if res := d.regex.FindStringSubmatch(s); res != nil {
+		return res[1], true
+	}
+
+	res := d.shortRegex.FindStringSubmatch(s)
 	if res == nil {
 		return "", false
 	}
 
-	return res[1], true
+	host, code := strings.ToLower(res[1]), res[2]
+	if host == "fav.me" {
+		if id, ok := d.resolve(code); ok {
+			return id, true
+		}
+	}
+
+	return host + "/" + code, true
 }
 
 func (*DeviantArt) Enabled(g *store.Guild) bool {
@@ -124,55 +196,94 @@ func (*DeviantArt) Enabled(g *store.Guild) bool {
 }
 
 func (a *Artwork) MessageSends(footer string, tagsEnabled bool) ([]*discordgo.MessageSend, error) {
-	eb := embeds.NewBuilder()
+	var (
+		length = len(a.Images)
+		title  = fmt.Sprintf("%v by %v", a.Title, a.Author.Name)
+		eb     = embeds.NewBuilder()
+	)
 
-	eb.Title(fmt.Sprintf("%v by %v", a.Title, a.Author.Name)).
-		Image(a.ImageURL).
-		URL(a.url).
+	eb.Title(ternary.If(length > 1,
+		fmt.Sprintf("%v | Page %v / %v", title, 1, length),
+		title,
+	)).
+		URL(a.URL()).
 		Timestamp(a.CreatedAt).
 		AddField("Views", strconv.Itoa(a.Views), true).
 		AddField("Favorites", strconv.Itoa(a.Favorites), true)
 
-	if tagsEnabled && len(a.Tags) > 0 {
-		tags := arrays.Map(a.Tags, func(s string) string {
+	desc := make([]string, 0, 2)
+	if a.Literature && a.Excerpt != "" {
+		desc = append(desc, artworks.EscapeMarkdown(a.Excerpt))
+	}
+
+	if tagsEnabled && len(a.TagList) > 0 {
+		tags := arrays.Map(a.TagList, func(s string) string {
 			return messages.NamedLink(
 				s, "https://www.deviantart.com/tag/"+s,
 			)
 		})
 
-		eb.Description("**Tags:**\n" + strings.Join(tags, " • "))
+		desc = append(desc, "**Tags:**\n"+strings.Join(tags, " • "))
+	}
+
+	if len(desc) > 0 {
+		eb.Description(strings.Join(desc, "\n\n"))
 	}
 
 	if footer != "" {
 		eb.Footer(footer, "")
 	}
 
-	if a.AIGenerated {
+	if a.AI {
 		eb.AddField("⚠️ Disclaimer", "This artwork is AI-generated.")
 	}
 
-	return []*discordgo.MessageSend{
-		{Embeds: []*discordgo.MessageEmbed{eb.Finalize()}},
-	}, nil
+	switch {
+	case length > 0:
+		eb.Image(a.Images[0])
+	case a.ThumbnailURL != "":
+		eb.Thumbnail(a.ThumbnailURL)
+	}
+
+	pages := make([]*discordgo.MessageSend, 0, max(length, 1))
+	pages = append(pages, &discordgo.MessageSend{Embeds: []*discordgo.MessageEmbed{eb.Finalize()}})
+
+	for ind, image := range a.Images[min(length, 1):] {
+		eb := embeds.NewBuilder()
+		eb.Title(fmt.Sprintf("%v | Page %v / %v", title, ind+2, length)).URL(a.URL()).Image(image)
+
+		if footer != "" {
+			eb.Footer(footer, "")
+		}
+
+		pages = append(pages, &discordgo.MessageSend{Embeds: []*discordgo.MessageEmbed{eb.Finalize()}})
+	}
+
+	return pages, nil
 }
 
 func (a *Artwork) StoreArtwork() *store.Artwork {
 	return &store.Artwork{
 		Title:  a.Title,
 		Author: a.Author.Name,
-		URL:    a.url,
-		Images: []string{a.ImageURL},
+		URL:    a.URL(),
+		Images: a.Images,
+		AI:     a.AI,
 	}
 }
 
-func (a *Artwork) URL() string {
-	return a.url
+func (a *Artwork) Len() int {
+	return len(a.Images)
+}
+
+func (a *Artwork) IsNSFW() bool {
+	return a.NSFW
 }
 
-func (a *Artwork) ID() string {
-	return a.id
+func (a *Artwork) AIGenerated() bool {
+	return a.AI
 }
 
-func (*Artwork) Len() int {
-	return 1
+func (a *Artwork) Tags() []string {
+	return a.TagList
 }
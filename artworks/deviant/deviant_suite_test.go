@@ -1,6 +1,7 @@
 package deviant_test
 
 import (
+	"context"
 	"testing"
 
 	"github.com/VTGare/boe-tea-go/artworks/deviant"
@@ -25,5 +26,55 @@ var _ = DescribeTable(
 	Entry("Valid artwork", "https://www.deviantart.com/bengeigerart/art/vt-123", "vt-123", true),
 	Entry("Query params", "https://www.deviantart.com/bengeigerart/art/vt-456?iw=234", "vt-456", true),
 	Entry("Invalid URL", "https://www.deviantart.com/art/Arbor-Vitae-877183179", "", false),
+	Entry("Sta.sh link", "https://sta.sh/0abc123def", "sta.sh/0abc123def", true),
 	Entry("Different domain", "https://www.somethingelse.com/q98e9N", "", false),
 )
+
+var _ = Describe("Find", func() {
+	It("should stop when the context is cancelled", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := deviant.New().Find(ctx, "vt-123")
+		Expect(err).To(MatchError(context.Canceled))
+	})
+})
+
+var _ = Describe("Artwork", func() {
+	It("should embed every image", func() {
+		artwork := &deviant.Artwork{
+			Title:  "Castle",
+			Author: &deviant.Author{Name: "Artist"},
+			Images: []string{"https://images-wixmp.com/1.jpg", "https://images-wixmp.com/2.jpg"},
+		}
+
+		sends, err := artwork.MessageSends("", true)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sends).To(HaveLen(2))
+		Expect(artwork.Len()).To(Equal(2))
+
+		Expect(sends[0].Embeds[0].Title).To(Equal("Castle by Artist | Page 1 / 2"))
+		Expect(sends[1].Embeds[0].Image.URL).To(Equal(artwork.Images[1]))
+	})
+
+	It("should show an excerpt of literature", func() {
+		artwork := &deviant.Artwork{
+			Title:        "Poem",
+			Author:       &deviant.Author{Name: "Writer"},
+			Images:       []string{},
+			ThumbnailURL: "https://images-wixmp.com/thumb.jpg",
+			Literature:   true,
+			Excerpt:      "Roses are red",
+			TagList:      []string{"poetry"},
+		}
+
+		sends, err := artwork.MessageSends("", true)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sends).To(HaveLen(1))
+
+		embed := sends[0].Embeds[0]
+		Expect(embed.Title).To(Equal("Poem by Writer"))
+		Expect(embed.Description).To(HavePrefix("Roses are red\n\n**Tags:**"))
+		Expect(embed.Thumbnail.URL).To(Equal(artwork.ThumbnailURL))
+	})
+})
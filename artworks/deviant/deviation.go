@@ -0,0 +1,224 @@
+package deviant
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/VTGare/boe-tea-go/artworks"
+)
+
+const (
+	// resolveTimeout bounds resolving a fav.me link, matching blocks message handling.
+	resolveTimeout = 3 * time.Second
+	// excerptLength is the maximum length of a literature excerpt in runes.
+	excerptLength = 1000
+)
+
+type deviantEmbed struct {
+	Type         string    `json:"type,omitempty"`
+	Title        string    `json:"title,omitempty"`
+	Category     string    `json:"category,omitempty"`
+	URL          string    `json:"url,omitempty"`
+	HTML         string    `json:"html,omitempty"`
+	ThumbnailURL string    `json:"thumbnail_url,omitempty"`
+	AuthorName   string    `json:"author_name,omitempty"`
+	AuthorURL    string    `json:"author_url,omitempty"`
+	Safety       string    `json:"safety,omitempty"`
+	Pubdate      time.Time `json:"pubdate,omitempty"`
+	Community    struct {
+		Statistics struct {
+			Attributes struct {
+				Views     int `json:"views,omitempty"`
+				Favorites int `json:"favorites,omitempty"`
+				Comments  int `json:"comments,omitempty"`
+				Downloads int `json:"downloads,omitempty"`
+			} `json:"_attributes,omitempty"`
+		} `json:"statistics,omitempty"`
+	} `json:"community,omitempty"`
+	Tags string `json:"tags,omitempty"`
+}
+
+// deviation is the full deviation as seen on its page. Unlike oEmbed, it has every image in full size.
+type deviation struct {
+	Deviation struct {
+		Type        string         `json:"type"`
+		IsMature    bool           `json:"isMature"`
+		Media       deviationMedia `json:"media"`
+		TextContent struct {
+			Excerpt string `json:"excerpt"`
+		} `json:"textContent"`
+		Extended struct {
+			AdditionalMedia []struct {
+				Media deviationMedia `json:"media"`
+			} `json:"additionalMedia"`
+		} `json:"extended"`
+	} `json:"deviation"`
+}
+
+type deviationMedia struct {
+	BaseURI    string   `json:"baseUri"`
+	PrettyName string   `json:"prettyName"`
+	Token      []string `json:"token"`
+	Types      []struct {
+		T string `json:"t"`
+		C string `json:"c"`
+	} `json:"types"`
+}
+
+// URL returns the largest public version of the image or an empty string for media without images.
+func (m deviationMedia) URL() string {
+	if m.BaseURI == "" {
+		return ""
+	}
+
+	link := m.BaseURI
+	for _, t := range m.Types {
+		if t.T == "fullview" && t.C != "" {
+			link = strings.TrimSuffix(link, "/") + "/" + strings.TrimPrefix(
+				strings.ReplaceAll(t.C, "<prettyName>", m.PrettyName), "/",
+			)
+			break
+		}
+	}
+
+	if len(m.Token) > 0 {
+		link += "?token=" + m.Token[0]
+	}
+
+	return link
+}
+
+func (d *DeviantArt) oEmbed(ctx context.Context, id string) (*deviantEmbed, error) {
+	res := &deviantEmbed{}
+	err := d.get(ctx, "https://backend.deviantart.com/oembed?url="+url.QueryEscape(pageURL(id)), res)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (d *DeviantArt) deviation(ctx context.Context, deviationID int64, username string) (*deviation, error) {
+	res := &deviation{}
+	err := d.get(ctx, fmt.Sprintf(
+		"https://www.deviantart.com/_puppy/dadeviation/init?deviationid=%v&username=%v&type=art&include_session=false",
+		deviationID, url.QueryEscape(username),
+	), res)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (d *DeviantArt) get(ctx context.Context, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("http get: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return artworks.ErrArtworkNotFound
+	default:
+		return fmt.Errorf("unexpected response status: %v", resp.Status)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("decode: %w", err)
+	}
+
+	return nil
+}
+
+// resolve follows a fav.me link to the deviation page and returns its ID.
+func (d *DeviantArt) resolve(code string) (string, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), resolveTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, "https://fav.me/"+code, nil)
+	if err != nil {
+		return "", false
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return "", false
+	}
+	resp.Body.Close()
+
+	res := d.regex.FindStringSubmatch(resp.Request.URL.String())
+	if res == nil {
+		return "", false
+	}
+
+	return res[1], true
+}
+
+// pageURL returns a link to the deviation known by oEmbed.
+func pageURL(id string) string {
+	if isShortLink(id) {
+		return "https://" + id
+	}
+
+	return "https://www.deviantart.com/art/" + id
+}
+
+// isShortLink reports whether the ID is an unresolved fav.me or sta.sh link, e.g. "sta.sh/0abc".
+func isShortLink(id string) bool {
+	return strings.Contains(id, "/")
+}
+
+// deviationID returns the numeric ID of a deviation. Sta.sh submissions aren't deviations and have none.
+func deviationID(id string) (int64, bool) {
+	if code, ok := strings.CutPrefix(id, "fav.me/"); ok {
+		// fav.me codes are "d" followed by the deviation ID in base 36.
+		if !strings.HasPrefix(code, "d") {
+			return 0, false
+		}
+
+		n, err := strconv.ParseInt(code[1:], 36, 64)
+		return n, err == nil
+	}
+
+	if isShortLink(id) {
+		return 0, false
+	}
+
+	n, err := strconv.ParseInt(id[strings.LastIndex(id, "-")+1:], 10, 64)
+	return n, err == nil
+}
+
+var (
+	lineBreakRegex = regexp.MustCompile(`(?i)<br\s*/?>|</p>\s*<p>`)
+	htmlTagRegex   = regexp.MustCompile(`<[^>]*>`)
+)
+
+// excerpt converts literature HTML to plain text and cuts it to excerptLength.
+func excerpt(content string) string {
+	content = lineBreakRegex.ReplaceAllString(content, "\n")
+	content = htmlTagRegex.ReplaceAllString(content, "")
+	content = strings.TrimSpace(html.UnescapeString(content))
+
+	runes := []rune(content)
+	if len(runes) <= excerptLength {
+		return content
+	}
+
+	return strings.TrimSpace(string(runes[:excerptLength])) + "…"
+}
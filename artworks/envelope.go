@@ -0,0 +1,111 @@
+package artworks
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+)
+
+var (
+	registryMu sync.RWMutex
+	byProvider = make(map[string]func() Artwork)
+	byType     = make(map[reflect.Type]string)
+)
+
+// envelope is a provider-tagged artwork used to restore the concrete artwork type from a cache.
+type envelope struct {
+	Provider string          `json:"provider"`
+	Artwork  json.RawMessage `json:"artwork"`
+}
+
+// RegisterArtwork registers an artwork type of a provider. Providers call it on init
+// to make their artworks serialisable with MarshalEnvelope.
+func RegisterArtwork(provider string, newArtwork func() Artwork) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	byProvider[provider] = newArtwork
+	byType[reflect.TypeOf(newArtwork())] = provider
+}
+
+// MarshalEnvelope encodes an artwork into a provider-tagged JSON envelope.
+func MarshalEnvelope(artwork Artwork) ([]byte, error) {
+	registryMu.RLock()
+	provider, ok := byType[reflect.TypeOf(artwork)]
+	registryMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("artwork type %T is not registered", artwork)
+	}
+
+	data, err := json.Marshal(artwork)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(envelope{Provider: provider, Artwork: data})
+}
+
+// UnmarshalEnvelope decodes an artwork encoded by MarshalEnvelope.
+func UnmarshalEnvelope(data []byte) (Artwork, error) {
+	var env envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, err
+	}
+
+	registryMu.RLock()
+	newArtwork, ok := byProvider[env.Provider]
+	registryMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown artwork provider: %v", env.Provider)
+	}
+
+	artwork := newArtwork()
+	if err := json.Unmarshal(env.Artwork, artwork); err != nil {
+		return nil, err
+	}
+
+	return artwork, nil
+}
+
+// ProviderName returns the name the artwork's provider was registered with or an empty string.
+func ProviderName(artwork Artwork) string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	return byType[reflect.TypeOf(artwork)]
+}
+
+// Providers returns sorted names of registered providers.
+func Providers() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(byProvider))
+	for name := range byProvider {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+	return names
+}
+
+// Source is the ID and the URL of an artwork. Artworks embed it to implement ID and URL,
+// the fields are exported so they're restored from a cache along with the rest of the artwork.
+type Source struct {
+	SourceID  string `json:"id"`
+	SourceURL string `json:"url"`
+}
+
+// ID implements Artwork.
+func (s Source) ID() string {
+	return s.SourceID
+}
+
+// URL implements Artwork.
+func (s Source) URL() string {
+	return s.SourceURL
+}
@@ -9,6 +9,9 @@ import (
 var (
 	ErrArtworkNotFound = errors.New("artwork not found")
 	ErrRateLimited     = errors.New("provider rate limited")
+	// ErrUnsupportedURL is returned by providers matching URLs by their shape when the matched URL
+	// turns out to be of another site, e.g. a server that isn't a Mastodon instance. Such URLs are ignored.
+	ErrUnsupportedURL = errors.New("url is not supported by the provider")
 )
 
 type Error struct {
@@ -35,3 +38,21 @@ func WrapError(p Provider, find func() (Artwork, error)) (Artwork, error) {
 
 	return artwork, nil
 }
+
+// NotFound returns an error with a provider-specific message for artworks that were removed or can't be viewed.
+// It matches ErrArtworkNotFound with errors.Is.
+func NotFound(msg string) error {
+	return &notFoundError{msg: msg}
+}
+
+type notFoundError struct {
+	msg string
+}
+
+func (e *notFoundError) Error() string {
+	return e.msg
+}
+
+func (e *notFoundError) Is(target error) bool {
+	return target == ErrArtworkNotFound
+}
@@ -0,0 +1,228 @@
+package fanbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/VTGare/boe-tea-go/artworks"
+	"github.com/VTGare/boe-tea-go/store"
+	"github.com/VTGare/embeds"
+	"github.com/bwmarrin/discordgo"
+	"github.com/julien040/go-ternary"
+)
+
+// userAgent is sent to Fanbox and Fantia APIs, they reject requests without a browser-like user agent.
+const userAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0 Safari/537.36"
+
+// Fanbox is a provider of Pixiv Fanbox and Fantia posts. Posts are mostly paywalled,
+// so only the cover image and free preview images are embedded.
+type Fanbox struct {
+	fanboxRegex *regexp.Regexp
+	fantiaRegex *regexp.Regexp
+	client      *http.Client
+	limiter     *artworks.Limiter
+}
+
+type Artwork struct {
+	artworks.Source
+
+	Site      string
+	Title     string
+	Creator   string
+	Excerpt   string
+	Plan      string
+	Paywalled bool
+	Cover     string
+	Images    []string
+	TagList   []string
+	NSFW      bool
+	AI        bool
+	CreatedAt time.Time
+}
+
+func init() {
+	artworks.RegisterArtwork("fanbox", func() artworks.Artwork { return &Artwork{} })
+}
+
+func New() *Fanbox {
+	return &Fanbox{
+		fanboxRegex: regexp.MustCompile(`(?i)https?://(?:[\w-]+\.fanbox\.cc|(?:www\.)?fanbox\.cc/@[\w-]+)/posts/(\d+)`),
+		fantiaRegex: regexp.MustCompile(`(?i)https?://(?:www\.)?fantia\.jp/posts/(\d+)`),
+		client:      artworks.NewHTTPClient(),
+		limiter:     artworks.NewLimiter(artworks.DefaultConcurrency),
+	}
+}
+
+// Enabled implements artworks.Provider.
+func (*Fanbox) Enabled(g *store.Guild) bool {
+	return g.FanboxEnabled()
+}
+
+// Match implements artworks.Provider. IDs are prefixed with the site, e.g. "fanbox:123" or "fantia:123".
+func (f *Fanbox) Match(url string) (string, bool) {
+	if res := f.fanboxRegex.FindStringSubmatch(url); res != nil {
+		return "fanbox:" + res[1], true
+	}
+
+	if res := f.fantiaRegex.FindStringSubmatch(url); res != nil {
+		return "fantia:" + res[1], true
+	}
+
+	return "", false
+}
+
+// Find implements artworks.Provider.
+func (f *Fanbox) Find(ctx context.Context, id string) (artworks.Artwork, error) {
+	return artworks.WrapError(f, func() (artworks.Artwork, error) {
+		release, err := f.limiter.Acquire(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+
+		site, postID, _ := strings.Cut(id, ":")
+		switch site {
+		case "fanbox":
+			return f.findFanbox(ctx, postID)
+		case "fantia":
+			return f.findFantia(ctx, postID)
+		default:
+			return nil, artworks.ErrArtworkNotFound
+		}
+	})
+}
+
+// get requests a JSON API and decodes the response. Not found posts return ErrArtworkNotFound.
+func (f *Fanbox) get(ctx context.Context, url string, headers map[string]string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("http get: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusBadRequest, http.StatusNotFound:
+		return artworks.ErrArtworkNotFound
+	default:
+		return fmt.Errorf("unexpected response status: %v", resp.Status)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("decode: %w", err)
+	}
+
+	return nil
+}
+
+// MessageSends implements artworks.Artwork. The cover image goes first, free preview images follow it.
+func (a *Artwork) MessageSends(footer string, tagsEnabled bool) ([]*discordgo.MessageSend, error) {
+	var (
+		images = a.pages()
+		length = len(images)
+		title  = fmt.Sprintf("%v by %v", a.Title, a.Creator)
+		eb     = embeds.NewBuilder()
+	)
+
+	eb.Title(ternary.If(length > 1,
+		fmt.Sprintf("%v | Page %v / %v", title, 1, length),
+		title,
+	)).URL(a.URL()).Timestamp(a.CreatedAt)
+
+	desc := a.Excerpt
+	if tagsEnabled && len(a.TagList) > 0 {
+		desc = fmt.Sprintf("%v\n\n**Tags**\n%v", desc, strings.Join(a.TagList, " • "))
+	}
+
+	eb.Description(artworks.EscapeMarkdown(desc))
+	eb.AddField("Site", a.Site, true)
+	eb.AddField("Plan", ternary.If(a.Plan != "", a.Plan, "Free"), true)
+
+	if a.Paywalled {
+		eb.AddField("🔒 Paywalled", fmt.Sprintf("Only a preview is available. Support %v on %v to see the full post.", a.Creator, a.Site))
+	}
+
+	if a.AI {
+		eb.AddField("⚠️ Disclaimer", "This artwork is AI-generated.")
+	}
+
+	if footer != "" {
+		eb.Footer(footer, "")
+	}
+
+	if length > 0 {
+		eb.Image(images[0])
+	}
+
+	pages := make([]*discordgo.MessageSend, 0, max(length, 1))
+	pages = append(pages, &discordgo.MessageSend{Embeds: []*discordgo.MessageEmbed{eb.Finalize()}})
+
+	for ind, image := range images[min(length, 1):] {
+		eb := embeds.NewBuilder()
+		eb.Title(fmt.Sprintf("%v | Page %v / %v", title, ind+2, length)).URL(a.URL())
+		eb.Image(image).Timestamp(a.CreatedAt)
+
+		if footer != "" {
+			eb.Footer(footer, "")
+		}
+
+		pages = append(pages, &discordgo.MessageSend{Embeds: []*discordgo.MessageEmbed{eb.Finalize()}})
+	}
+
+	return pages, nil
+}
+
+func (a *Artwork) pages() []string {
+	if a.Cover == "" {
+		return a.Images
+	}
+
+	return append([]string{a.Cover}, a.Images...)
+}
+
+// StoreArtwork implements artworks.Artwork.
+func (a *Artwork) StoreArtwork() *store.Artwork {
+	return &store.Artwork{
+		Title:  a.Title,
+		Author: a.Creator,
+		URL:    a.URL(),
+		Images: a.pages(),
+		AI:     a.AI,
+	}
+}
+
+// Len implements artworks.Artwork.
+func (a *Artwork) Len() int {
+	return len(a.pages())
+}
+
+// IsNSFW implements artworks.Artwork.
+func (a *Artwork) IsNSFW() bool {
+	return a.NSFW
+}
+
+// AIGenerated implements artworks.Artwork.
+func (a *Artwork) AIGenerated() bool {
+	return a.AI
+}
+
+// Tags implements artworks.Artwork.
+func (a *Artwork) Tags() []string {
+	return a.TagList
+}
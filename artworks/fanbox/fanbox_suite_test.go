@@ -0,0 +1,91 @@
+package fanbox_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/VTGare/boe-tea-go/artworks/fanbox"
+	"github.com/VTGare/boe-tea-go/store"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestFanbox(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Fanbox Suite")
+}
+
+var _ = DescribeTable(
+	"Match Fanbox and Fantia URL",
+	func(url string, expectedID string, expectedResult bool) {
+		provider := fanbox.New()
+
+		id, ok := provider.Match(url)
+		Expect(id).To(BeEquivalentTo(expectedID))
+		Expect(ok).To(BeEquivalentTo(expectedResult))
+	},
+	Entry("Fanbox subdomain", "https://creator.fanbox.cc/posts/1234", "fanbox:1234", true),
+	Entry("Fanbox handle", "https://www.fanbox.cc/@creator/posts/1234", "fanbox:1234", true),
+	Entry("Fanbox query params", "https://creator.fanbox.cc/posts/1234?utm_source=x", "fanbox:1234", true),
+	Entry("Fantia post", "https://fantia.jp/posts/5678", "fantia:5678", true),
+	Entry("Fantia fanclub", "https://fantia.jp/fanclubs/5678", "", false),
+	Entry("Fanbox creator page", "https://creator.fanbox.cc/", "", false),
+	Entry("Different domain", "https://www.somethingelse.com/posts/1234", "", false),
+)
+
+var _ = DescribeTable(
+	"Enabled in guild",
+	func(doc string, expected bool) {
+		var guild store.Guild
+		Expect(json.Unmarshal([]byte(doc), &guild)).To(Succeed())
+		Expect(fanbox.New().Enabled(&guild)).To(Equal(expected))
+	},
+	Entry("Guild created before the setting", `{"id": "1"}`, true),
+	Entry("Enabled", `{"id": "1", "fanbox": true}`, true),
+	Entry("Disabled", `{"id": "1", "fanbox": false}`, false),
+)
+
+var _ = Describe("Find", func() {
+	It("should stop when the context is cancelled", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := fanbox.New().Find(ctx, "fanbox:1234")
+		Expect(err).To(MatchError(context.Canceled))
+	})
+})
+
+var _ = Describe("Artwork", func() {
+	It("should embed the cover and free previews of paywalled posts", func() {
+		artwork := &fanbox.Artwork{
+			Site:      "Fantia",
+			Title:     "Commission",
+			Creator:   "Artist",
+			Plan:      "Supporter (¥500 / month)",
+			Paywalled: true,
+			Cover:     "https://cc.fantia.jp/cover.jpg",
+			Images:    []string{"https://cc.fantia.jp/1.jpg"},
+		}
+
+		sends, err := artwork.MessageSends("", false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sends).To(HaveLen(2))
+		Expect(artwork.Len()).To(Equal(2))
+
+		embed := sends[0].Embeds[0]
+		Expect(embed.Image.URL).To(Equal(artwork.Cover))
+		Expect(embed.Fields).To(ContainElement(HaveField("Name", "🔒 Paywalled")))
+		Expect(embed.Fields).To(ContainElement(HaveField("Value", artwork.Plan)))
+		Expect(sends[1].Embeds[0].Image.URL).To(Equal(artwork.Images[0]))
+	})
+
+	It("should embed posts without images", func() {
+		artwork := &fanbox.Artwork{Site: "Fanbox", Title: "Update", Creator: "Artist"}
+
+		sends, err := artwork.MessageSends("", false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sends).To(HaveLen(1))
+		Expect(sends[0].Embeds[0].Fields).To(ContainElement(HaveField("Value", "Free")))
+	})
+})
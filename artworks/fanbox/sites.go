@@ -0,0 +1,201 @@
+package fanbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/VTGare/boe-tea-go/artworks"
+)
+
+type fanboxResponse struct {
+	Body struct {
+		ID                string    `json:"id"`
+		Title             string    `json:"title"`
+		Excerpt           string    `json:"excerpt"`
+		FeeRequired       int       `json:"feeRequired"`
+		IsRestricted      bool      `json:"isRestricted"`
+		HasAdultContent   bool      `json:"hasAdultContent"`
+		CoverImageURL     string    `json:"coverImageUrl"`
+		Tags              []string  `json:"tags"`
+		CreatorID         string    `json:"creatorId"`
+		PublishedDatetime time.Time `json:"publishedDatetime"`
+		User              struct {
+			Name string `json:"name"`
+		} `json:"user"`
+		// Body is null for restricted posts. Image posts list images, article posts map them by IDs.
+		Body *struct {
+			Text   string        `json:"text"`
+			Images []fanboxImage `json:"images"`
+			Blocks []struct {
+				Type    string `json:"type"`
+				ImageID string `json:"imageId"`
+			} `json:"blocks"`
+			ImageMap map[string]fanboxImage `json:"imageMap"`
+		} `json:"body"`
+	} `json:"body"`
+}
+
+type fanboxImage struct {
+	OriginalURL  string `json:"originalUrl"`
+	ThumbnailURL string `json:"thumbnailUrl"`
+}
+
+func (f *Fanbox) findFanbox(ctx context.Context, id string) (*Artwork, error) {
+	res := &fanboxResponse{}
+	err := f.get(ctx, "https://api.fanbox.cc/post.info?postId="+id, map[string]string{
+		"Origin":  "https://www.fanbox.cc",
+		"Referer": "https://www.fanbox.cc/",
+	}, res)
+	if err != nil {
+		return nil, err
+	}
+
+	post := res.Body
+	if post.ID == "" {
+		return nil, artworks.ErrArtworkNotFound
+	}
+
+	images := make([]string, 0)
+	if body := post.Body; body != nil {
+		for _, image := range body.Images {
+			images = append(images, image.ThumbnailURL)
+		}
+
+		// Article images are referenced by blocks in the order they appear.
+		for _, block := range body.Blocks {
+			if image, ok := body.ImageMap[block.ImageID]; ok && block.Type == "image" {
+				images = append(images, image.ThumbnailURL)
+			}
+		}
+	}
+
+	var plan string
+	if post.FeeRequired > 0 {
+		plan = fmt.Sprintf("¥%v / month", post.FeeRequired)
+	}
+
+	return &Artwork{
+		Source: artworks.Source{SourceID: "fanbox:" + id, SourceURL: fmt.Sprintf("https://%v.fanbox.cc/posts/%v", post.CreatorID, id)},
+
+		Site:      "Fanbox",
+		Title:     post.Title,
+		Creator:   post.User.Name,
+		Excerpt:   post.Excerpt,
+		Plan:      plan,
+		Paywalled: post.IsRestricted,
+		Cover:     post.CoverImageURL,
+		Images:    images,
+		TagList:   post.Tags,
+		NSFW:      post.HasAdultContent,
+		AI:        artworks.IsAIGenerated(post.Tags...),
+		CreatedAt: post.PublishedDatetime,
+	}, nil
+}
+
+type fantiaResponse struct {
+	Post struct {
+		ID       int       `json:"id"`
+		Title    string    `json:"title"`
+		Comment  string    `json:"comment"`
+		Rating   string    `json:"rating"`
+		PostedAt time.Time `json:"posted_at"`
+		Thumb    *struct {
+			Main     string `json:"main"`
+			Original string `json:"original"`
+		} `json:"thumb"`
+		Tags []struct {
+			Name string `json:"name"`
+		} `json:"tags"`
+		Fanclub struct {
+			CreatorName string `json:"creator_name"`
+			User        struct {
+				Name string `json:"name"`
+			} `json:"user"`
+		} `json:"fanclub"`
+		Contents []struct {
+			VisibleStatus string `json:"visible_status"`
+			Plan          *struct {
+				Name  string `json:"name"`
+				Price int    `json:"price"`
+			} `json:"plan"`
+			Photos []struct {
+				URL struct {
+					Main     string `json:"main"`
+					Original string `json:"original"`
+				} `json:"url"`
+			} `json:"post_content_photos"`
+		} `json:"post_contents"`
+	} `json:"post"`
+}
+
+func (f *Fanbox) findFantia(ctx context.Context, id string) (*Artwork, error) {
+	res := &fantiaResponse{}
+	err := f.get(ctx, "https://fantia.jp/api/v1/posts/"+id, map[string]string{
+		"X-Requested-With": "XMLHttpRequest",
+	}, res)
+	if err != nil {
+		return nil, err
+	}
+
+	post := res.Post
+	if post.ID == 0 {
+		return nil, artworks.ErrArtworkNotFound
+	}
+
+	var (
+		images    = make([]string, 0)
+		plan      string
+		paywalled bool
+		minPrice  int
+	)
+
+	for _, content := range post.Contents {
+		// Contents of plans the viewer doesn't support are hidden. The cheapest of them is the post's plan.
+		if content.VisibleStatus != "visible" {
+			paywalled = true
+			if content.Plan != nil && (plan == "" || content.Plan.Price < minPrice) {
+				plan = fmt.Sprintf("%v (¥%v / month)", content.Plan.Name, content.Plan.Price)
+				minPrice = content.Plan.Price
+			}
+
+			continue
+		}
+
+		for _, photo := range content.Photos {
+			images = append(images, photo.URL.Main)
+		}
+	}
+
+	var cover string
+	if post.Thumb != nil {
+		cover = post.Thumb.Main
+	}
+
+	tags := make([]string, 0, len(post.Tags))
+	for _, tag := range post.Tags {
+		tags = append(tags, tag.Name)
+	}
+
+	creator := post.Fanclub.CreatorName
+	if creator == "" {
+		creator = post.Fanclub.User.Name
+	}
+
+	return &Artwork{
+		Source: artworks.Source{SourceID: "fantia:" + id, SourceURL: "https://fantia.jp/posts/" + id},
+
+		Site:      "Fantia",
+		Title:     post.Title,
+		Creator:   creator,
+		Excerpt:   post.Comment,
+		Plan:      plan,
+		Paywalled: paywalled,
+		Cover:     cover,
+		Images:    images,
+		TagList:   tags,
+		NSFW:      post.Rating == "adult",
+		AI:        artworks.IsAIGenerated(tags...),
+		CreatedAt: post.PostedAt,
+	}, nil
+}
@@ -0,0 +1,113 @@
+package mastodon
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/VTGare/boe-tea-go/internal/cache"
+)
+
+const (
+	// probeTimeout bounds probing an unknown host.
+	probeTimeout = 3 * time.Second
+	// instanceTTL is how long a host is remembered as a Mastodon instance.
+	instanceTTL = 24 * time.Hour
+	// notInstanceTTL is how long a host is remembered as not a Mastodon instance.
+	notInstanceTTL = time.Hour
+	// maxInstances is the number of probed hosts remembered.
+	maxInstances = 1024
+)
+
+// knownInstances are popular instances that are never probed.
+var knownInstances = []string{
+	"mastodon.social",
+	"mastodon.art",
+	"mastodon.online",
+	"mstdn.jp",
+	"pawoo.net",
+	"baraag.net",
+	"fedibird.com",
+	"mas.to",
+}
+
+// instances remembers which hosts are Mastodon-API servers.
+type instances struct {
+	client *http.Client
+	known  map[string]struct{}
+	hosts  *cache.LRU[bool]
+}
+
+func newInstances(client *http.Client, known ...string) *instances {
+	i := &instances{
+		client: client,
+		known:  make(map[string]struct{}, len(known)),
+		hosts:  cache.NewLRU[bool](maxInstances),
+	}
+
+	for _, host := range known {
+		i.known[strings.ToLower(host)] = struct{}{}
+	}
+
+	return i
+}
+
+// IsInstance reports whether the host is a Mastodon-API server. Unknown hosts are probed and cached.
+func (i *instances) IsInstance(ctx context.Context, host string) (bool, error) {
+	host = strings.ToLower(host)
+	if _, ok := i.known[host]; ok {
+		return true, nil
+	}
+
+	if ok, cached := i.hosts.Get(host); cached {
+		return ok, nil
+	}
+
+	isInstance := i.probe(ctx, host)
+
+	// A cancelled probe says nothing about the host.
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	ttl := notInstanceTTL
+	if isInstance {
+		ttl = instanceTTL
+	}
+
+	i.hosts.Set(host, isInstance, ttl)
+	return isInstance, nil
+}
+
+func (i *instances) probe(ctx context.Context, host string) bool {
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://"+host+"/api/v1/instance", nil)
+	if err != nil {
+		return false
+	}
+
+	resp, err := i.client.Do(req)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false
+	}
+
+	var info struct {
+		URI     string `json:"uri"`
+		Version string `json:"version"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return false
+	}
+
+	return info.URI != "" && info.Version != ""
+}
@@ -0,0 +1,313 @@
+package mastodon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/VTGare/boe-tea-go/artworks"
+	"github.com/VTGare/boe-tea-go/messages"
+	"github.com/VTGare/boe-tea-go/store"
+	"github.com/VTGare/embeds"
+	"github.com/bwmarrin/discordgo"
+	"github.com/julien040/go-ternary"
+)
+
+// Mastodon is a provider of statuses from any server implementing Mastodon API.
+type Mastodon struct {
+	regex     *regexp.Regexp
+	client    *http.Client
+	limiter   *artworks.Limiter
+	instances *instances
+}
+
+type Status struct {
+	ID               string    `json:"id"`
+	URL              string    `json:"url"`
+	Content          string    `json:"content"`
+	Sensitive        bool      `json:"sensitive"`
+	SpoilerText      string    `json:"spoiler_text"`
+	CreatedAt        time.Time `json:"created_at"`
+	FavouritesCount  int       `json:"favourites_count"`
+	ReblogsCount     int       `json:"reblogs_count"`
+	MediaAttachments []struct {
+		Type       string `json:"type"`
+		URL        string `json:"url"`
+		PreviewURL string `json:"preview_url"`
+	} `json:"media_attachments"`
+	Account struct {
+		Acct        string `json:"acct"`
+		DisplayName string `json:"display_name"`
+	} `json:"account"`
+	Tags []struct {
+		Name string `json:"name"`
+	} `json:"tags"`
+}
+
+// Media is an attached image or video. Videos are embedded as their preview images.
+type Media struct {
+	URL     string
+	Preview string
+	Video   bool
+}
+
+type Artwork struct {
+	artworks.Source
+
+	Instance    string
+	Acct        string
+	DisplayName string
+	Content     string
+	Media       []Media
+	TagList     []string
+	Favourites  int
+	Reblogs     int
+	NSFW        bool
+	AI          bool
+	CreatedAt   time.Time
+}
+
+func init() {
+	artworks.RegisterArtwork("mastodon", func() artworks.Artwork { return &Artwork{} })
+}
+
+// New creates a Mastodon provider. Status URLs of any host are matched, hosts of popular instances are trusted
+// and other hosts are probed for Mastodon API before their statuses are fetched.
+func New() *Mastodon {
+	client := artworks.NewPublicHTTPClient()
+
+	return &Mastodon{
+		regex:     regexp.MustCompile(`(?i)https://([a-z0-9.-]+\.[a-z]{2,})/@[\w.-]+(?:@[\w.-]+)?/(\d+)`),
+		client:    client,
+		limiter:   artworks.NewLimiter(artworks.DefaultConcurrency),
+		instances: newInstances(client, knownInstances...),
+	}
+}
+
+// Enabled implements artworks.Provider.
+func (*Mastodon) Enabled(g *store.Guild) bool {
+	return g.MastodonEnabled()
+}
+
+// Match implements artworks.Provider. IDs are the instance host and the status ID, e.g. "mastodon.social:123".
+func (m *Mastodon) Match(url string) (string, bool) {
+	res := m.regex.FindStringSubmatch(url)
+	if res == nil {
+		return "", false
+	}
+
+	return strings.ToLower(res[1]) + ":" + res[2], true
+}
+
+// Find implements artworks.Provider. Statuses of hosts that aren't Mastodon instances return artworks.ErrUnsupportedURL.
+func (m *Mastodon) Find(ctx context.Context, id string) (artworks.Artwork, error) {
+	return artworks.WrapError(m, func() (artworks.Artwork, error) {
+		release, err := m.limiter.Acquire(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+
+		host, statusID, _ := strings.Cut(id, ":")
+		isInstance, err := m.instances.IsInstance(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+
+		if !isInstance {
+			return nil, fmt.Errorf("%v: %w", host, artworks.ErrUnsupportedURL)
+		}
+		req, err := http.NewRequestWithContext(
+			ctx,
+			http.MethodGet,
+			fmt.Sprintf("https://%v/api/v1/statuses/%v", host, statusID),
+			nil,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := m.client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("http get: %w", err)
+		}
+		defer resp.Body.Close()
+
+		switch resp.StatusCode {
+		case http.StatusOK:
+		// Statuses of private accounts and instances requiring authorization can't be viewed.
+		case http.StatusNotFound, http.StatusUnauthorized, http.StatusForbidden:
+			return nil, artworks.ErrArtworkNotFound
+		default:
+			return nil, fmt.Errorf("unexpected response status: %v", resp.Status)
+		}
+
+		status := &Status{}
+		if err := json.NewDecoder(resp.Body).Decode(status); err != nil {
+			return nil, fmt.Errorf("decode: %w", err)
+		}
+
+		return newArtwork(id, host, status), nil
+	})
+}
+
+func newArtwork(id, host string, status *Status) *Artwork {
+	media := make([]Media, 0, len(status.MediaAttachments))
+	for _, attachment := range status.MediaAttachments {
+		switch attachment.Type {
+		case "image":
+			media = append(media, Media{URL: attachment.URL, Preview: attachment.URL})
+		case "video", "gifv":
+			media = append(media, Media{URL: attachment.URL, Preview: attachment.PreviewURL, Video: true})
+		}
+	}
+
+	tags := make([]string, 0, len(status.Tags))
+	for _, tag := range status.Tags {
+		tags = append(tags, tag.Name)
+	}
+
+	content := stripHTML(status.Content)
+	if status.SpoilerText != "" {
+		content = fmt.Sprintf("**CW: %v**\n%v", status.SpoilerText, content)
+	}
+
+	url := status.URL
+	if url == "" {
+		url = fmt.Sprintf("https://%v/@%v/%v", host, status.Account.Acct, status.ID)
+	}
+
+	return &Artwork{
+		Source: artworks.Source{SourceID: id, SourceURL: url},
+
+		Instance:    host,
+		Acct:        status.Account.Acct,
+		DisplayName: status.Account.DisplayName,
+		Content:     content,
+		Media:       media,
+		TagList:     tags,
+		Favourites:  status.FavouritesCount,
+		Reblogs:     status.ReblogsCount,
+		NSFW:        status.Sensitive,
+		AI:          artworks.IsAIGenerated(tags...),
+		CreatedAt:   status.CreatedAt,
+	}
+}
+
+var (
+	lineBreakRegex = regexp.MustCompile(`(?i)<br\s*/?>|</p>\s*<p>`)
+	htmlTagRegex   = regexp.MustCompile(`<[^>]*>`)
+)
+
+// stripHTML converts status HTML to plain text keeping line breaks.
+func stripHTML(content string) string {
+	content = lineBreakRegex.ReplaceAllString(content, "\n")
+	content = htmlTagRegex.ReplaceAllString(content, "")
+	return strings.TrimSpace(html.UnescapeString(content))
+}
+
+// MessageSends implements artworks.Artwork.
+func (a *Artwork) MessageSends(footer string, tagsEnabled bool) ([]*discordgo.MessageSend, error) {
+	var (
+		length = len(a.Media)
+		title  = fmt.Sprintf("%v (@%v)", a.DisplayName, a.Acct)
+		eb     = embeds.NewBuilder()
+	)
+
+	eb.Title(ternary.If(length > 1,
+		fmt.Sprintf("%v | Page %v / %v", title, 1, length),
+		title,
+	)).URL(a.URL()).Timestamp(a.CreatedAt)
+
+	desc := a.Content
+	if tagsEnabled && len(a.TagList) > 0 {
+		desc = fmt.Sprintf("%v\n\n**Tags**\n%v", desc, strings.Join(a.TagList, " • "))
+	}
+
+	eb.Description(desc)
+
+	if a.Reblogs > 0 {
+		eb.AddField("Boosts", strconv.Itoa(a.Reblogs), true)
+	}
+
+	if a.Favourites > 0 {
+		eb.AddField("Favourites", strconv.Itoa(a.Favourites), true)
+	}
+
+	if a.AI {
+		eb.AddField("⚠️ Disclaimer", "This artwork is AI-generated.")
+	}
+
+	if footer != "" {
+		eb.Footer(footer, "")
+	}
+
+	if length > 0 {
+		a.Media[0].embed(eb)
+	}
+
+	pages := make([]*discordgo.MessageSend, 0, max(length, 1))
+	pages = append(pages, &discordgo.MessageSend{Embeds: []*discordgo.MessageEmbed{eb.Finalize()}})
+
+	for ind, media := range a.Media[min(length, 1):] {
+		eb := embeds.NewBuilder()
+		eb.Title(fmt.Sprintf("%v | Page %v / %v", title, ind+2, length)).URL(a.URL()).Timestamp(a.CreatedAt)
+		media.embed(eb)
+
+		if footer != "" {
+			eb.Footer(footer, "")
+		}
+
+		pages = append(pages, &discordgo.MessageSend{Embeds: []*discordgo.MessageEmbed{eb.Finalize()}})
+	}
+
+	return pages, nil
+}
+
+func (m Media) embed(eb *embeds.Builder) {
+	eb.Image(m.Preview)
+	if m.Video {
+		eb.AddField("▶️ Video", messages.ClickHere(m.URL))
+	}
+}
+
+// StoreArtwork implements artworks.Artwork.
+func (a *Artwork) StoreArtwork() *store.Artwork {
+	images := make([]string, 0, len(a.Media))
+	for _, media := range a.Media {
+		images = append(images, media.Preview)
+	}
+
+	return &store.Artwork{
+		Author: a.Acct,
+		URL:    a.URL(),
+		Images: images,
+		AI:     a.AI,
+	}
+}
+
+// Len implements artworks.Artwork.
+func (a *Artwork) Len() int {
+	return len(a.Media)
+}
+
+// IsNSFW implements artworks.Artwork. Mastodon doesn't rate content, statuses marked sensitive are treated as NSFW.
+func (a *Artwork) IsNSFW() bool {
+	return a.NSFW
+}
+
+// AIGenerated implements artworks.Artwork.
+func (a *Artwork) AIGenerated() bool {
+	return a.AI
+}
+
+// Tags implements artworks.Artwork.
+func (a *Artwork) Tags() []string {
+	return a.TagList
+}
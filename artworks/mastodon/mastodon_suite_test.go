@@ -0,0 +1,83 @@
+package mastodon_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/VTGare/boe-tea-go/artworks"
+	"github.com/VTGare/boe-tea-go/artworks/mastodon"
+	"github.com/VTGare/boe-tea-go/store"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestMastodon(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Mastodon Suite")
+}
+
+var _ = DescribeTable(
+	"Match Mastodon URL",
+	func(url string, expectedID string, expectedResult bool) {
+		provider := mastodon.New()
+
+		id, ok := provider.Match(url)
+		Expect(id).To(BeEquivalentTo(expectedID))
+		Expect(ok).To(BeEquivalentTo(expectedResult))
+	},
+	Entry("Known instance", "https://mastodon.social/@user/1234", "mastodon.social:1234", true),
+	Entry("Remote account", "https://mastodon.social/@user@mastodon.art/1234", "mastodon.social:1234", true),
+	Entry("Uppercase host", "https://Mastodon.Social/@user/1234", "mastodon.social:1234", true),
+	Entry("Profile", "https://mastodon.social/@user", "", false),
+	Entry("Unknown host", "https://Example.invalid/@user/1234", "example.invalid:1234", true),
+	Entry("Not a status", "https://mastodon.social/@user/media", "", false),
+)
+
+var _ = DescribeTable(
+	"Enabled in guild",
+	func(doc string, expected bool) {
+		var guild store.Guild
+		Expect(json.Unmarshal([]byte(doc), &guild)).To(Succeed())
+		Expect(mastodon.New().Enabled(&guild)).To(Equal(expected))
+	},
+	Entry("Guild created before the setting", `{"id": "1"}`, true),
+	Entry("Enabled", `{"id": "1", "mastodon": true}`, true),
+	Entry("Disabled", `{"id": "1", "mastodon": false}`, false),
+)
+
+var _ = Describe("Find", func() {
+	It("should stop when the context is cancelled", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := mastodon.New().Find(ctx, "mastodon.social:1234")
+		Expect(err).To(MatchError(context.Canceled))
+	})
+
+	It("should refuse hosts resolving to private addresses", func() {
+		_, err := mastodon.New().Find(context.Background(), "localhost:1234")
+		Expect(err).To(MatchError(artworks.ErrUnsupportedURL))
+	})
+})
+
+var _ = Describe("Artwork", func() {
+	It("should embed videos as their previews", func() {
+		artwork := &mastodon.Artwork{
+			Acct:        "user",
+			DisplayName: "User",
+			Media: []mastodon.Media{
+				{URL: "https://files.mastodon.social/1.png", Preview: "https://files.mastodon.social/1.png"},
+				{URL: "https://files.mastodon.social/2.mp4", Preview: "https://files.mastodon.social/2.png", Video: true},
+			},
+		}
+
+		sends, err := artwork.MessageSends("", false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sends).To(HaveLen(2))
+
+		video := sends[1].Embeds[0]
+		Expect(video.Image.URL).To(Equal("https://files.mastodon.social/2.png"))
+		Expect(video.Fields).To(ContainElement(HaveField("Name", "▶️ Video")))
+	})
+})
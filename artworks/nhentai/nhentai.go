@@ -0,0 +1,231 @@
+package nhentai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+
+	"github.com/VTGare/boe-tea-go/artworks"
+	nh "github.com/VTGare/boe-tea-go/internal/apis/nhentai"
+	"github.com/VTGare/boe-tea-go/messages"
+	"github.com/VTGare/boe-tea-go/store"
+	"github.com/VTGare/embeds"
+	"github.com/bwmarrin/discordgo"
+	"github.com/julien040/go-ternary"
+)
+
+// NHentai is a provider of nhentai galleries. Galleries are explicit, so they're embedded only in NSFW channels.
+type NHentai struct {
+	api     *nh.API
+	regex   *regexp.Regexp
+	limiter *artworks.Limiter
+}
+
+// Artwork is an nhentai gallery. Only the cover is embedded, pages are read with bt!nhentai read.
+type Artwork struct {
+	Gallery *nh.Hentai
+}
+
+func init() {
+	artworks.RegisterArtwork("nhentai", func() artworks.Artwork { return &Artwork{} })
+}
+
+// New creates an nhentai provider. Links of the mirror host are matched along with nhentai.net.
+func New(api *nh.API, mirror string) *NHentai {
+	hosts := regexp.QuoteMeta(nh.DefaultHost)
+	if mirror != "" {
+		hosts += "|" + regexp.QuoteMeta(mirror)
+	}
+
+	return &NHentai{
+		api:     api,
+		regex:   regexp.MustCompile(`(?i)https?://(?:www\.)?(?:` + hosts + `)/g/(\d+)`),
+		limiter: artworks.NewLimiter(artworks.DefaultConcurrency),
+	}
+}
+
+// Enabled implements artworks.Provider. Galleries are embedded only in NSFW channels regardless, see NSFWOnly.
+func (*NHentai) Enabled(g *store.Guild) bool {
+	return g.NHentaiEnabled()
+}
+
+// NSFWOnly implements artworks.NSFWProvider.
+func (*NHentai) NSFWOnly() bool {
+	return true
+}
+
+// Match implements artworks.Provider.
+func (n *NHentai) Match(url string) (string, bool) {
+	res := n.regex.FindStringSubmatch(url)
+	if res == nil {
+		return "", false
+	}
+
+	return res[1], true
+}
+
+// Find implements artworks.Provider.
+func (n *NHentai) Find(ctx context.Context, id string) (artworks.Artwork, error) {
+	return artworks.WrapError(n, func() (artworks.Artwork, error) {
+		release, err := n.limiter.Acquire(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+
+		gallery, err := n.api.FindHentai(ctx, id)
+		if err != nil {
+			if errors.Is(err, nh.ErrNotFound) {
+				return nil, artworks.ErrArtworkNotFound
+			}
+
+			return nil, err
+		}
+
+		return &Artwork{Gallery: gallery}, nil
+	})
+}
+
+// MessageSends implements artworks.Artwork.
+func (a *Artwork) MessageSends(footer string, tagsEnabled bool) ([]*discordgo.MessageSend, error) {
+	g := a.Gallery
+	eb := embeds.NewBuilder()
+
+	eb.Title(a.title()).
+		URL(g.URL).
+		Image(g.Cover).
+		Timestamp(g.UploadedAt).
+		AddField("Pages", strconv.Itoa(g.Pages), true).
+		AddField("Favorites", strconv.Itoa(g.Favorites), true)
+
+	if artists := g.Artists(); len(artists) > 0 {
+		eb.AddField("Artists", tagLinks(artists), true)
+	}
+
+	if characters := g.Characters(); len(characters) > 0 {
+		eb.AddField("Characters", tagLinks(characters), true)
+	}
+
+	if parodies := g.Parodies(); len(parodies) > 0 {
+		eb.AddField("Parodies", tagLinks(parodies), true)
+	}
+
+	if lang, ok := g.Language(); ok {
+		eb.AddField("Language", cases.Title(language.English).String(lang.String()), true)
+	}
+
+	if tags := a.Tags(); tagsEnabled && len(tags) > 0 {
+		eb.AddField("Tags", strings.Join(tags, " • "))
+	}
+
+	if a.AIGenerated() {
+		eb.AddField("⚠️ Disclaimer", "This artwork is AI-generated.")
+	}
+
+	if footer != "" {
+		eb.Footer(footer, "")
+	}
+
+	return []*discordgo.MessageSend{
+		{Embeds: []*discordgo.MessageEmbed{eb.Finalize()}},
+	}, nil
+}
+
+// PageEmbeds returns an embed for every page of the gallery.
+func (a *Artwork) PageEmbeds() []*discordgo.MessageEmbed {
+	var (
+		pages  = a.Gallery.PageURLs
+		title  = a.title()
+		result = make([]*discordgo.MessageEmbed, 0, len(pages))
+	)
+
+	for ind, page := range pages {
+		eb := embeds.NewBuilder()
+		eb.Title(fmt.Sprintf("%v | Page %v / %v", title, ind+1, len(pages))).
+			URL(fmt.Sprintf("%v/%v/", a.Gallery.URL, ind+1)).
+			Image(page)
+
+		result = append(result, eb.Finalize())
+	}
+
+	return result
+}
+
+func (a *Artwork) title() string {
+	return ternary.If(a.Gallery.Titles != nil && a.Gallery.Titles.Pretty != "",
+		a.Gallery.Titles.Pretty,
+		"No title",
+	)
+}
+
+func tagLinks(tags []*nh.Tag) string {
+	links := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		links = append(links, messages.NamedLink(tag.Name, tag.URL))
+	}
+
+	return strings.Join(links, " • ")
+}
+
+// StoreArtwork implements artworks.Artwork.
+func (a *Artwork) StoreArtwork() *store.Artwork {
+	artists := make([]string, 0)
+	for _, artist := range a.Gallery.Artists() {
+		artists = append(artists, artist.Name)
+	}
+
+	return &store.Artwork{
+		Title:  a.title(),
+		Author: strings.Join(artists, ", "),
+		URL:    a.Gallery.URL,
+		Images: []string{a.Gallery.Cover},
+		AI:     a.AIGenerated(),
+	}
+}
+
+// ID implements artworks.Artwork.
+func (a *Artwork) ID() string {
+	return strconv.Itoa(a.Gallery.ID)
+}
+
+// URL implements artworks.Artwork.
+func (a *Artwork) URL() string {
+	return a.Gallery.URL
+}
+
+// Len implements artworks.Artwork. Only the cover is embedded.
+func (*Artwork) Len() int {
+	return 1
+}
+
+// IsNSFW implements artworks.Artwork. Every gallery is explicit.
+func (*Artwork) IsNSFW() bool {
+	return true
+}
+
+// AIGenerated implements artworks.Artwork. Tags have spaces, e.g. "ai generated".
+func (a *Artwork) AIGenerated() bool {
+	tags := a.Tags()
+	for i, tag := range tags {
+		tags[i] = strings.ReplaceAll(tag, " ", "")
+	}
+
+	return artworks.IsAIGenerated(tags...)
+}
+
+// Tags implements artworks.Artwork. Only genre tags are returned.
+func (a *Artwork) Tags() []string {
+	genres := a.Gallery.Genres()
+	tags := make([]string, 0, len(genres))
+	for _, tag := range genres {
+		tags = append(tags, tag.Name)
+	}
+
+	return tags
+}
@@ -0,0 +1,103 @@
+package nhentai_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/VTGare/boe-tea-go/artworks"
+	"github.com/VTGare/boe-tea-go/artworks/nhentai"
+	nh "github.com/VTGare/boe-tea-go/internal/apis/nhentai"
+	"github.com/VTGare/boe-tea-go/store"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestNHentai(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "NHentai Suite")
+}
+
+func newProvider() *nhentai.NHentai {
+	api, err := nh.New("nh.example.com", nil)
+	Expect(err).NotTo(HaveOccurred())
+
+	return nhentai.New(api, "nh.example.com")
+}
+
+var _ = DescribeTable(
+	"Match nhentai URL",
+	func(url string, expectedID string, expectedResult bool) {
+		id, ok := newProvider().Match(url)
+		Expect(id).To(BeEquivalentTo(expectedID))
+		Expect(ok).To(BeEquivalentTo(expectedResult))
+	},
+	Entry("Valid gallery", "https://nhentai.net/g/177013/", "177013", true),
+	Entry("Gallery page", "https://nhentai.net/g/177013/3/", "177013", true),
+	Entry("Mirror", "https://nh.example.com/g/177013", "177013", true),
+	Entry("Search page", "https://nhentai.net/search/?q=tag", "", false),
+	Entry("Different domain", "https://www.somethingelse.com/g/177013", "", false),
+)
+
+var _ = DescribeTable(
+	"Enabled in guild",
+	func(doc string, expected bool) {
+		var guild store.Guild
+		Expect(json.Unmarshal([]byte(doc), &guild)).To(Succeed())
+		Expect(newProvider().Enabled(&guild)).To(Equal(expected))
+	},
+	Entry("Guild created before the setting", `{"id": "1"}`, true),
+	Entry("NSFW quotes disabled", `{"id": "1", "nsfw": false}`, true),
+	Entry("Enabled", `{"id": "1", "nhentai": true}`, true),
+	Entry("Disabled", `{"id": "1", "nsfw": true, "nhentai": false}`, false),
+)
+
+var _ = Describe("Find", func() {
+	It("should stop when the context is cancelled", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := newProvider().Find(ctx, "177013")
+		Expect(err).To(MatchError(context.Canceled))
+	})
+})
+
+var _ = Describe("Provider", func() {
+	It("should be embedded only in NSFW channels", func() {
+		Expect(artworks.NSFWOnly(newProvider())).To(BeTrue())
+	})
+})
+
+var _ = Describe("Artwork", func() {
+	artwork := &nhentai.Artwork{
+		Gallery: &nh.Hentai{
+			ID:       177013,
+			URL:      "https://nhentai.net/g/177013",
+			Cover:    "https://t.nhentai.net/galleries/987560/cover.jpg",
+			PageURLs: []string{"https://i.nhentai.net/galleries/987560/1.jpg", "https://i.nhentai.net/galleries/987560/2.png"},
+			Titles:   &nh.Titles{Pretty: "Metamorphosis"},
+			Tags:     []*nh.Tag{{Name: "ai generated", Type: nh.GenreTag}},
+			Pages:    2,
+		},
+	}
+
+	It("should embed the cover", func() {
+		sends, err := artwork.MessageSends("", true)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sends).To(HaveLen(1))
+
+		Expect(sends[0].Embeds[0].Title).To(Equal("Metamorphosis"))
+		Expect(sends[0].Embeds[0].Image.URL).To(Equal(artwork.Gallery.Cover))
+		Expect(artwork.IsNSFW()).To(BeTrue())
+		Expect(artwork.AIGenerated()).To(BeTrue())
+	})
+
+	It("should embed every page for reading", func() {
+		pages := artwork.PageEmbeds()
+		Expect(pages).To(HaveLen(2))
+
+		Expect(pages[1].Title).To(Equal("Metamorphosis | Page 2 / 2"))
+		Expect(pages[1].URL).To(Equal("https://nhentai.net/g/177013/2/"))
+		Expect(pages[1].Image.URL).To(Equal(artwork.Gallery.PageURLs[1]))
+	})
+})
@@ -1,6 +1,7 @@
 package pixiv
 
 import (
+	"context"
 	"fmt"
 	"path"
 	"regexp"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/VTGare/boe-tea-go/artworks"
 	"github.com/VTGare/boe-tea-go/internal/arrays"
+	"github.com/VTGare/boe-tea-go/internal/imageproxy"
 	"github.com/VTGare/boe-tea-go/messages"
 	"github.com/VTGare/boe-tea-go/store"
 	"github.com/VTGare/embeds"
@@ -18,50 +20,59 @@ import (
 	"github.com/julien040/go-ternary"
 )
 
+// pximgHost is Pixiv's image host. It requires a Referer header, so images are embedded through a proxy.
+const pximgHost = "https://i.pximg.net"
+
 type Pixiv struct {
 	app       *pixiv.AppPixivAPI
+	limiter   *artworks.Limiter
 	proxyHost string
+	signer    *imageproxy.Signer
 	regex     *regexp.Regexp
 }
 
 type Artwork struct {
-	Type        string
-	Author      string
-	Title       string
-	Likes       int
-	Pages       int
-	Tags        []string
-	Images      []*Image
-	NSFW        bool
-	AIGenerated bool
-	CreatedAt   time.Time
-
-	id    string
-	url   string
-	proxy string
+	artworks.Source
+
+	Type      string
+	Author    string
+	Title     string
+	Likes     int
+	Pages     int
+	TagList   []string
+	Images    []*Image
+	NSFW      bool
+	AI        bool
+	CreatedAt time.Time
+
+	// Proxy is the host replacing i.pximg.net in images cached before proxied URLs were introduced.
+	Proxy string
 }
 
-type Image struct {
-	Preview  string
-	Original string
+func init() {
+	artworks.RegisterArtwork("pixiv", func() artworks.Artwork { return &Artwork{} })
 }
 
-func LoadAuth(authToken, refreshToken string) error {
-	_, err := pixiv.LoadAuth(authToken, refreshToken, time.Now())
-	if err != nil {
-		return err
-	}
-	return nil
+// Image is a page of an artwork. Proxied URLs are empty in artworks cached before they were introduced.
+type Image struct {
+	Preview       string
+	Original      string
+	ProxyPreview  string
+	ProxyOriginal string
 }
 
-func New(proxyHost string) artworks.Provider {
+// New creates a Pixiv provider that embeds images through the proxy host. A non-nil signer signs proxied URLs
+// for the built-in image proxy, an external proxy is used without signatures.
+func New(proxyHost string, signer *imageproxy.Signer) artworks.Provider {
 	if proxyHost == "" {
 		proxyHost = "https://boetea.dev"
 	}
 
 	return &Pixiv{
-		app:       pixiv.NewApp(),
-		proxyHost: proxyHost,
+		app:       pixiv.NewApp().WithClient(artworks.NewHTTPClient()),
+		limiter:   artworks.NewLimiter(artworks.DefaultConcurrency),
+		proxyHost: strings.TrimSuffix(proxyHost, "/"),
+		signer:    signer,
 		regex:     regexp.MustCompile(`(?i)https?://(?:www\.)?pixiv\.net/(?:en/)?(?:artworks/|member_illust\.php\?)(?:mode=medium&)?(?:illust_id=)?([0-9]+)`),
 	}
 }
@@ -75,14 +86,24 @@ func (p *Pixiv) Match(s string) (string, bool) {
 	return res[1], true
 }
 
-func (p *Pixiv) Find(id string) (artworks.Artwork, error) {
+func (p *Pixiv) Find(ctx context.Context, id string) (artworks.Artwork, error) {
 	return artworks.WrapError(p, func() (artworks.Artwork, error) {
 		i, err := strconv.ParseUint(id, 10, 64)
 		if err != nil {
 			return nil, err
 		}
 
-		illust, err := p.app.IllustDetail(i)
+		release, err := p.limiter.Acquire(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		// The Pixiv client doesn't accept a context, requests are bounded by the client's timeout instead.
+		// The slot is held until the request is complete even if the caller stopped waiting.
+		illust, err := artworks.Await(ctx, func() (*pixiv.Illust, error) {
+			defer release()
+			return p.app.IllustDetail(i)
+		})
 		if err != nil {
 			return nil, err
 		}
@@ -113,8 +134,10 @@ func (p *Pixiv) Find(id string) (artworks.Artwork, error) {
 		if page := illust.MetaSinglePage; page != nil {
 			if page.OriginalImageURL != "" {
 				img := &Image{
-					Original: page.OriginalImageURL,
-					Preview:  illust.Images.Medium,
+					Original:      page.OriginalImageURL,
+					Preview:       illust.Images.Medium,
+					ProxyOriginal: p.proxyURL(page.OriginalImageURL),
+					ProxyPreview:  p.proxyURL(illust.Images.Medium),
 				}
 
 				images = append(images, img)
@@ -123,19 +146,20 @@ func (p *Pixiv) Find(id string) (artworks.Artwork, error) {
 
 		for _, page := range illust.MetaPages {
 			img := &Image{
-				Original: page.Images.Original,
-				Preview:  page.Images.Large,
+				Original:      page.Images.Original,
+				Preview:       page.Images.Large,
+				ProxyOriginal: p.proxyURL(page.Images.Original),
+				ProxyPreview:  p.proxyURL(page.Images.Large),
 			}
 
 			images = append(images, img)
 		}
 
 		artwork := &Artwork{
-			id:        id,
-			url:       "https://www.pixiv.net/en/artworks/" + id,
+			Source:    artworks.Source{SourceID: id, SourceURL: "https://www.pixiv.net/en/artworks/" + id},
 			Title:     illust.Title,
 			Author:    author,
-			Tags:      tags,
+			TagList:   tags,
 			Images:    images,
 			NSFW:      nsfw,
 			Type:      illust.Type,
@@ -143,7 +167,7 @@ func (p *Pixiv) Find(id string) (artworks.Artwork, error) {
 			Likes:     illust.TotalBookmarks,
 			CreatedAt: illust.CreateDate,
 
-			proxy: p.proxyHost,
+			Proxy: p.proxyHost,
 		}
 
 		imgFile := path.Base(artwork.Images[0].Original)
@@ -152,13 +176,27 @@ func (p *Pixiv) Find(id string) (artworks.Artwork, error) {
 		}
 
 		if illust.IllustAIType == pixiv.IllustAITypeAIGenerated {
-			artwork.AIGenerated = true
+			artwork.AI = true
 		}
 
 		return artwork, nil
 	})
 }
 
+// proxyURL rewrites an image URL to the proxy host.
+func (p *Pixiv) proxyURL(imageURL string) string {
+	if !strings.HasPrefix(imageURL, pximgHost) {
+		return imageURL
+	}
+
+	imagePath := strings.TrimPrefix(imageURL, pximgHost)
+	if p.signer == nil {
+		return p.proxyHost + imagePath
+	}
+
+	return p.proxyHost + imagePath + "?sig=" + p.signer.Sign(imagePath)
+}
+
 func (*Pixiv) Enabled(g *store.Guild) bool {
 	return g.Pixiv
 }
@@ -167,8 +205,9 @@ func (a *Artwork) StoreArtwork() *store.Artwork {
 	return &store.Artwork{
 		Title:  a.Title,
 		Author: a.Author,
-		URL:    a.url,
+		URL:    a.URL(),
 		Images: a.imageURLs(),
+		AI:     a.AI,
 	}
 }
 
@@ -184,43 +223,43 @@ func (a *Artwork) MessageSends(footer string, tagsEnabled bool) ([]*discordgo.Me
 		fmt.Sprintf("%v by %v", a.Title, a.Author),
 	))
 
-	if tagsEnabled && len(a.Tags) > 0 {
-		tags := arrays.Map(a.Tags, func(s string) string {
+	if tagsEnabled && len(a.TagList) > 0 {
+		tags := arrays.Map(a.TagList, func(s string) string {
 			return fmt.Sprintf("[%v](https://pixiv.net/en/tags/%v/artworks)", s, s)
 		})
 
 		eb.Description(fmt.Sprintf("**Tags**\n%v", strings.Join(tags, " • ")))
 	}
 
-	eb.URL(a.url).
+	eb.URL(a.URL()).
 		AddField("Likes", strconv.Itoa(a.Likes), true).
-		AddField("Original quality", messages.ClickHere(a.Images[0].originalProxy(a.proxy)), true).
+		AddField("Original quality", messages.ClickHere(a.Images[0].originalProxy(a.Proxy)), true).
 		Timestamp(a.CreatedAt)
 
 	if footer != "" {
 		eb.Footer(footer, "")
 	}
 
-	if a.AIGenerated {
+	if a.AI {
 		eb.AddField("⚠️ Disclaimer", "This artwork is AI-generated.")
 	}
 
-	eb.Image(a.Images[0].previewProxy(a.proxy))
+	eb.Image(a.Images[0].previewProxy(a.Proxy))
 	pages = append(pages, &discordgo.MessageSend{Embeds: []*discordgo.MessageEmbed{eb.Finalize()}})
 	if length > 1 {
 		for ind, image := range a.Images[1:] {
 			eb := embeds.NewBuilder()
 
 			eb.Title(fmt.Sprintf("%v by %v | Page %v / %v", a.Title, a.Author, ind+2, length))
-			eb.Image(image.previewProxy(a.proxy))
-			eb.URL(a.url).Timestamp(a.CreatedAt)
+			eb.Image(image.previewProxy(a.Proxy))
+			eb.URL(a.URL()).Timestamp(a.CreatedAt)
 
 			if footer != "" {
 				eb.Footer(footer, "")
 			}
 
 			eb.AddField("Likes", strconv.Itoa(a.Likes), true)
-			eb.AddField("Original quality", messages.ClickHere(image.originalProxy(a.proxy)), true)
+			eb.AddField("Original quality", messages.ClickHere(image.originalProxy(a.Proxy)), true)
 
 			pages = append(pages, &discordgo.MessageSend{Embeds: []*discordgo.MessageEmbed{eb.Finalize()}})
 		}
@@ -229,32 +268,44 @@ func (a *Artwork) MessageSends(footer string, tagsEnabled bool) ([]*discordgo.Me
 	return pages, nil
 }
 
-func (a *Artwork) URL() string {
-	return a.url
-}
-
 func (a *Artwork) Len() int {
 	return a.Pages
 }
 
-func (a *Artwork) ID() string {
-	return a.id
-}
-
 func (a *Artwork) imageURLs() []string {
 	urls := make([]string, 0, len(a.Images))
 
 	for _, img := range a.Images {
-		urls = append(urls, img.originalProxy(a.proxy))
+		urls = append(urls, img.originalProxy(a.Proxy))
 	}
 
 	return urls
 }
 
 func (i Image) originalProxy(host string) string {
-	return strings.Replace(i.Original, "https://i.pximg.net", host, 1)
+	if i.ProxyOriginal != "" {
+		return i.ProxyOriginal
+	}
+
+	return strings.Replace(i.Original, pximgHost, host, 1)
 }
 
 func (i Image) previewProxy(host string) string {
-	return strings.Replace(i.Preview, "https://i.pximg.net", host, 1)
+	if i.ProxyPreview != "" {
+		return i.ProxyPreview
+	}
+
+	return strings.Replace(i.Preview, pximgHost, host, 1)
+}
+
+func (a *Artwork) IsNSFW() bool {
+	return a.NSFW
+}
+
+func (a *Artwork) AIGenerated() bool {
+	return a.AI
+}
+
+func (a *Artwork) Tags() []string {
+	return a.TagList
 }
@@ -1,7 +1,17 @@
 package pixiv_test
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/VTGare/boe-tea-go/artworks/pixiv"
 	. "github.com/onsi/ginkgo/v2"
@@ -16,7 +26,7 @@ func TestPixiv(t *testing.T) {
 var _ = DescribeTable(
 	"Match Pixiv URL",
 	func(url string, expectedID string, expectedResult bool) {
-		provider := pixiv.New("test.com")
+		provider := pixiv.New("test.com", nil)
 
 		id, ok := provider.Match(url)
 		Expect(id).To(BeEquivalentTo(expectedID))
@@ -31,3 +41,150 @@ var _ = DescribeTable(
 	Entry("ID with letters", "https://pixiv.net/artworks/qwerty", "", false),
 	Entry("Different domain", "https://google.com/artworks/123456", "", false),
 )
+
+// fakeAuth answers Pixiv OAuth refreshes, rotating the refresh token every time.
+type fakeAuth struct {
+	mu        sync.Mutex
+	revoked   map[string]bool
+	refreshes []string
+}
+
+func (f *fakeAuth) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	refresh := form.Get("refresh_token")
+	f.refreshes = append(f.refreshes, refresh)
+
+	status, res := http.StatusOK, map[string]any{
+		"response": map[string]any{
+			"access_token":  fmt.Sprintf("auth-%v", len(f.refreshes)),
+			"refresh_token": fmt.Sprintf("rotated-%v", len(f.refreshes)),
+			"expires_in":    3600,
+		},
+	}
+
+	if f.revoked[refresh] {
+		status, res = http.StatusBadRequest, map[string]any{
+			"has_error": true,
+			"errors":    map[string]any{"system": map[string]any{"message": "Invalid refresh token"}},
+		}
+	}
+
+	data, err := json.Marshal(res)
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Response{
+		StatusCode:    status,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(strings.NewReader(string(data))),
+		ContentLength: int64(len(data)),
+		Request:       req,
+	}, nil
+}
+
+func (f *fakeAuth) Refreshes() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.refreshes
+}
+
+var _ = Describe("Token manager", func() {
+	var (
+		auth *fakeAuth
+		path string
+	)
+
+	// The Pixiv client authenticates with http.DefaultClient.
+	BeforeEach(func() {
+		auth = &fakeAuth{revoked: make(map[string]bool)}
+		path = filepath.Join(GinkgoT().TempDir(), "tokens.json")
+
+		transport := http.DefaultClient.Transport
+		http.DefaultClient.Transport = auth
+		DeferCleanup(func() { http.DefaultClient.Transport = transport })
+	})
+
+	saved := func() pixiv.Tokens {
+		data, err := os.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+
+		var tokens pixiv.Tokens
+		Expect(json.Unmarshal(data, &tokens)).To(Succeed())
+		return tokens
+	}
+
+	It("should keep and save rotated tokens", func() {
+		manager := pixiv.NewTokenManager(path)
+
+		Expect(manager.Login("auth", "refresh")).To(Succeed())
+		Expect(manager.Tokens().AuthToken).To(Equal("auth-1"))
+		Expect(manager.Tokens().RefreshToken).To(Equal("rotated-1"))
+		Expect(manager.Tokens().Expiry).To(BeTemporally("~", time.Now().Add(time.Hour), time.Minute))
+
+		Expect(manager.Login(manager.Tokens().AuthToken, manager.Tokens().RefreshToken)).To(Succeed())
+		Expect(auth.Refreshes()).To(Equal([]string{"refresh", "rotated-1"}))
+		Expect(manager.Tokens().RefreshToken).To(Equal("rotated-2"))
+
+		Expect(saved().RefreshToken).To(Equal("rotated-2"))
+		Expect(saved().Expiry).To(BeTemporally("==", manager.Tokens().Expiry))
+
+		info, err := os.Stat(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0o600)))
+	})
+
+	It("should restore saved tokens over configured ones", func() {
+		Expect(pixiv.NewTokenManager(path).Login("auth", "configured")).To(Succeed())
+
+		manager := pixiv.NewTokenManager(path)
+		Expect(manager.Restore("auth", "configured")).To(Succeed())
+
+		Expect(auth.Refreshes()).To(Equal([]string{"configured", "rotated-1"}))
+		Expect(manager.Tokens().RefreshToken).To(Equal("rotated-2"))
+		Expect(saved().RefreshToken).To(Equal("rotated-2"))
+	})
+
+	It("should fall back to configured tokens if saved ones are revoked", func() {
+		Expect(pixiv.NewTokenManager(path).Login("auth", "configured")).To(Succeed())
+		auth.revoked["rotated-1"] = true
+
+		manager := pixiv.NewTokenManager(path)
+		Expect(manager.Restore("auth", "configured")).To(Succeed())
+
+		Expect(auth.Refreshes()).To(Equal([]string{"configured", "rotated-1", "configured"}))
+		Expect(manager.Tokens().RefreshToken).To(Equal("rotated-3"))
+		Expect(saved().RefreshToken).To(Equal("rotated-3"))
+	})
+
+	It("should report tokens that fail to save", func() {
+		var reported error
+		manager := pixiv.NewTokenManager(filepath.Join(path, "missing", "tokens.json"))
+		manager.OnError = func(err error) { reported = err }
+
+		Expect(manager.Login("auth", "refresh")).To(Succeed())
+		Expect(manager.Tokens().RefreshToken).To(Equal("rotated-1"))
+		Expect(reported).To(MatchError(ContainSubstring("failed to save pixiv tokens")))
+	})
+
+	It("should require a refresh token to log in", func() {
+		manager := pixiv.NewTokenManager("")
+
+		Expect(manager.Login("auth", "")).To(HaveOccurred())
+		Expect(manager.Restore("auth", "")).To(HaveOccurred())
+		Expect(manager.Tokens()).To(BeZero())
+	})
+})
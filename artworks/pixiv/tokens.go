@@ -0,0 +1,182 @@
+package pixiv
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/everpcpc/pixiv"
+)
+
+const (
+	// refreshMargin is how long before expiry the access token is refreshed.
+	refreshMargin = 5 * time.Minute
+	// retryInterval is how long to wait before refreshing again after a failure.
+	retryInterval = 10 * time.Minute
+)
+
+var errNoRefreshToken = errors.New("no refresh token")
+
+// Tokens are Pixiv OAuth tokens. Pixiv may rotate the refresh token on every refresh.
+type Tokens struct {
+	AuthToken    string    `json:"auth_token"`
+	RefreshToken string    `json:"refresh_token"`
+	Expiry       time.Time `json:"expiry"`
+}
+
+// TokenManager keeps Pixiv logged in. It refreshes the access token before it expires
+// and saves rotated tokens to a file to survive restarts.
+type TokenManager struct {
+	// OnError is called when tokens fail to refresh or save.
+	OnError func(error)
+
+	path    string
+	login   sync.Mutex
+	mu      sync.RWMutex
+	tokens  Tokens
+	renewed chan struct{}
+}
+
+// NewTokenManager creates a token manager that saves tokens to path. Empty path doesn't save tokens.
+// Only one token manager may exist at a time, because the Pixiv client's auth is global.
+func NewTokenManager(path string) *TokenManager {
+	m := &TokenManager{
+		path:    path,
+		renewed: make(chan struct{}, 1),
+	}
+
+	pixiv.HookAuth(m.onAuth)
+	return m
+}
+
+// Restore logs in with saved tokens, falling back to the given ones if they're missing or revoked.
+func (m *TokenManager) Restore(authToken, refreshToken string) error {
+	saved, err := m.load()
+	if err == nil && saved.RefreshToken != "" && saved.RefreshToken != refreshToken {
+		if err := m.Login(saved.AuthToken, saved.RefreshToken); err == nil {
+			return nil
+		}
+	}
+
+	return m.Login(authToken, refreshToken)
+}
+
+// Login logs in with the refresh token and replaces the current tokens.
+func (m *TokenManager) Login(authToken, refreshToken string) error {
+	if refreshToken == "" {
+		return errNoRefreshToken
+	}
+
+	m.login.Lock()
+	defer m.login.Unlock()
+
+	if _, err := pixiv.LoadAuth(authToken, refreshToken, time.Now()); err != nil {
+		return err
+	}
+
+	select {
+	case m.renewed <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
+
+// Tokens returns the current tokens.
+func (m *TokenManager) Tokens() Tokens {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.tokens
+}
+
+// Run refreshes tokens before they expire until the context is done.
+// Failed refreshes are reported to OnError and retried.
+func (m *TokenManager) Run(ctx context.Context) {
+	var failed bool
+	for {
+		wait := retryInterval
+		if tokens := m.Tokens(); !failed && !tokens.Expiry.IsZero() {
+			wait = time.Until(tokens.Expiry.Add(-refreshMargin))
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-m.renewed:
+			timer.Stop()
+			failed = false
+			continue
+		case <-timer.C:
+		}
+
+		tokens := m.Tokens()
+		err := m.Login(tokens.AuthToken, tokens.RefreshToken)
+
+		// Report only the first failure in a row, retries are likely to fail the same way.
+		if err != nil && !failed {
+			m.report(fmt.Errorf("failed to refresh pixiv tokens: %w", err))
+		}
+
+		failed = err != nil
+	}
+}
+
+// onAuth is called by the Pixiv client after every successful auth, including refreshes it does on its own.
+func (m *TokenManager) onAuth(authToken, refreshToken string, expiry time.Time) error {
+	m.mu.Lock()
+	m.tokens = Tokens{
+		AuthToken:    authToken,
+		RefreshToken: refreshToken,
+		Expiry:       expiry,
+	}
+	err := m.save()
+	m.mu.Unlock()
+
+	// Pixiv is logged in even if tokens can't be saved, so the error is only reported.
+	if err != nil {
+		m.report(fmt.Errorf("failed to save pixiv tokens: %w", err))
+	}
+
+	return nil
+}
+
+func (m *TokenManager) load() (Tokens, error) {
+	var tokens Tokens
+	if m.path == "" {
+		return tokens, os.ErrNotExist
+	}
+
+	data, err := os.ReadFile(m.path)
+	if err != nil {
+		return tokens, err
+	}
+
+	err = json.Unmarshal(data, &tokens)
+	return tokens, err
+}
+
+func (m *TokenManager) save() error {
+	if m.path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(m.tokens)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(m.path, data, 0o600)
+}
+
+func (m *TokenManager) report(err error) {
+	if m.OnError != nil {
+		m.OnError(err)
+	}
+}
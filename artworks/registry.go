@@ -0,0 +1,210 @@
+package artworks
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrUnknownProvider is returned by the registry for names that weren't registered.
+var ErrUnknownProvider = errors.New("unknown provider")
+
+// Status is the health of a provider.
+type Status string
+
+const (
+	// StatusHealthy providers found their last artwork.
+	StatusHealthy Status = "healthy"
+	// StatusDegraded providers failed their last request to the source.
+	StatusDegraded Status = "degraded"
+	// StatusDisabled providers don't match any URLs until they're enabled.
+	StatusDisabled Status = "disabled"
+)
+
+// ProviderStatus is a snapshot of a registered provider's health.
+type ProviderStatus struct {
+	Name        string
+	Status      Status
+	LastError   error
+	LastErrorAt time.Time
+	LastSuccess time.Time
+}
+
+// Registry keeps named artwork providers in the order they were registered
+// and tracks their health. It's safe for concurrent use.
+type Registry struct {
+	mu        sync.RWMutex
+	providers []*registeredProvider
+}
+
+type registeredProvider struct {
+	name        string
+	provider    Provider
+	disabled    bool
+	degraded    bool
+	lastError   error
+	lastErrorAt time.Time
+	lastSuccess time.Time
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register adds an enabled provider. A provider registered under an existing name replaces it
+// in place and keeps its history.
+func (r *Registry) Register(name string, provider Provider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if rp := r.find(name); rp != nil {
+		rp.provider = provider
+		rp.disabled = false
+		rp.degraded = false
+		return
+	}
+
+	r.providers = append(r.providers, &registeredProvider{name: name, provider: provider})
+}
+
+// Providers returns enabled providers in the order they were registered.
+func (r *Registry) Providers() []Provider {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	providers := make([]Provider, 0, len(r.providers))
+	for _, rp := range r.providers {
+		if !rp.disabled {
+			providers = append(providers, rp.provider)
+		}
+	}
+
+	return providers
+}
+
+// Names returns names of all registered providers including disabled ones.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.providers))
+	for _, rp := range r.providers {
+		names = append(names, rp.name)
+	}
+
+	return names
+}
+
+// Name returns the name a provider was registered under or an empty string.
+func (r *Registry) Name(provider Provider) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, rp := range r.providers {
+		if rp.provider == provider {
+			return rp.name
+		}
+	}
+
+	return ""
+}
+
+// Enable enables a provider and resets its health.
+func (r *Registry) Enable(name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rp := r.find(name)
+	if rp == nil {
+		return fmt.Errorf("%w: %v", ErrUnknownProvider, name)
+	}
+
+	rp.disabled = false
+	rp.degraded = false
+	return nil
+}
+
+// Disable disables a provider. A non-nil reason is recorded as the provider's last error.
+func (r *Registry) Disable(name string, reason error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rp := r.find(name)
+	if rp == nil {
+		return fmt.Errorf("%w: %v", ErrUnknownProvider, name)
+	}
+
+	rp.disabled = true
+	if reason != nil {
+		rp.lastError = reason
+		rp.lastErrorAt = time.Now()
+	}
+
+	return nil
+}
+
+// Report records the result of a provider's request. Artworks that weren't found and unsupported URLs
+// don't affect the provider's health, any other error degrades it until the next success.
+func (r *Registry) Report(provider Provider, err error) {
+	if errors.Is(err, ErrArtworkNotFound) || errors.Is(err, ErrUnsupportedURL) {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, rp := range r.providers {
+		if rp.provider != provider {
+			continue
+		}
+
+		if err != nil {
+			rp.degraded = true
+			rp.lastError = err
+			rp.lastErrorAt = time.Now()
+		} else {
+			rp.degraded = false
+			rp.lastSuccess = time.Now()
+		}
+
+		return
+	}
+}
+
+// Status returns health of all registered providers.
+func (r *Registry) Status() []ProviderStatus {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	statuses := make([]ProviderStatus, 0, len(r.providers))
+	for _, rp := range r.providers {
+		status := StatusHealthy
+		switch {
+		case rp.disabled:
+			status = StatusDisabled
+		case rp.degraded:
+			status = StatusDegraded
+		}
+
+		statuses = append(statuses, ProviderStatus{
+			Name:        rp.name,
+			Status:      status,
+			LastError:   rp.lastError,
+			LastErrorAt: rp.lastErrorAt,
+			LastSuccess: rp.lastSuccess,
+		})
+	}
+
+	return statuses
+}
+
+func (r *Registry) find(name string) *registeredProvider {
+	for _, rp := range r.providers {
+		if rp.name == name {
+			return rp
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,135 @@
+package tumblr
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/VTGare/boe-tea-go/artworks"
+)
+
+// npfResponse is a post in Neue Post Format. Reblogged content is in the trail.
+type npfResponse struct {
+	Response struct {
+		BlogName  string       `json:"blog_name"`
+		PostURL   string       `json:"post_url"`
+		Timestamp int64        `json:"timestamp"`
+		Tags      []string     `json:"tags"`
+		NoteCount int          `json:"note_count"`
+		Content   []npfBlock   `json:"content"`
+		Trail     []npfTrail   `json:"trail"`
+		Blog      npfBlogFlags `json:"blog"`
+	} `json:"response"`
+}
+
+type npfTrail struct {
+	Content []npfBlock `json:"content"`
+}
+
+type npfBlogFlags struct {
+	IsAdult bool `json:"is_adult"`
+}
+
+type npfBlock struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+	// Media of image blocks is a list of sizes, the largest goes first.
+	Media  []npfMedia `json:"media"`
+	Poster []npfMedia `json:"poster"`
+}
+
+type npfMedia struct {
+	URL string `json:"url"`
+}
+
+func (t *Tumblr) findNPF(ctx context.Context, id, blog, postID string) (*Artwork, error) {
+	res := &npfResponse{}
+	err := t.get(ctx, fmt.Sprintf(
+		"https://api.tumblr.com/v2/blog/%v/posts/%v?npf=true&api_key=%v",
+		url.PathEscape(blog), postID, url.QueryEscape(t.apiKey),
+	), res)
+	if err != nil {
+		return nil, err
+	}
+
+	post := res.Response
+	blocks := make([]npfBlock, 0, len(post.Content))
+	for _, trail := range post.Trail {
+		blocks = append(blocks, trail.Content...)
+	}
+	blocks = append(blocks, post.Content...)
+
+	var (
+		text   = make([]string, 0)
+		images = make([]string, 0)
+	)
+
+	for _, block := range blocks {
+		switch block.Type {
+		case "text":
+			text = append(text, block.Text)
+		case "image":
+			if len(block.Media) > 0 {
+				images = append(images, block.Media[0].URL)
+			}
+		case "video":
+			if len(block.Poster) > 0 {
+				images = append(images, block.Poster[0].URL)
+			}
+		}
+	}
+
+	link := post.PostURL
+	if link == "" {
+		link = postURL(blog, postID)
+	}
+
+	return &Artwork{
+		Source: artworks.Source{SourceID: id, SourceURL: link},
+
+		Blog:      post.BlogName,
+		Text:      strings.Join(text, "\n"),
+		Images:    images,
+		TagList:   post.Tags,
+		Notes:     post.NoteCount,
+		NSFW:      post.Blog.IsAdult,
+		AI:        artworks.IsAIGenerated(post.Tags...),
+		CreatedAt: time.Unix(post.Timestamp, 0),
+	}, nil
+}
+
+type oEmbedResponse struct {
+	AuthorName   string `json:"author_name"`
+	Title        string `json:"title"`
+	ThumbnailURL string `json:"thumbnail_url"`
+}
+
+func (t *Tumblr) findOEmbed(ctx context.Context, id, blog, postID string) (*Artwork, error) {
+	link := postURL(blog, postID)
+
+	res := &oEmbedResponse{}
+	if err := t.get(ctx, "https://www.tumblr.com/oembed/1.0?url="+url.QueryEscape(link), res); err != nil {
+		return nil, err
+	}
+
+	images := make([]string, 0, 1)
+	if res.ThumbnailURL != "" {
+		images = append(images, res.ThumbnailURL)
+	}
+
+	author := res.AuthorName
+	if author == "" {
+		author = blog
+	}
+
+	return &Artwork{
+		Source: artworks.Source{SourceID: id, SourceURL: link},
+
+		Blog:    author,
+		Text:    res.Title,
+		Images:  images,
+		TagList: []string{},
+	}, nil
+}
@@ -0,0 +1,207 @@
+package tumblr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/VTGare/boe-tea-go/artworks"
+	"github.com/VTGare/boe-tea-go/store"
+	"github.com/VTGare/embeds"
+	"github.com/bwmarrin/discordgo"
+	"github.com/julien040/go-ternary"
+)
+
+// Tumblr is a provider of Tumblr posts. Posts are fetched in Neue Post Format if an API key is set,
+// otherwise oEmbed is used, which only has the author and the first image.
+type Tumblr struct {
+	apiKey  string
+	regex   *regexp.Regexp
+	client  *http.Client
+	limiter *artworks.Limiter
+}
+
+type Artwork struct {
+	artworks.Source
+
+	Blog      string
+	Text      string
+	Images    []string
+	TagList   []string
+	Notes     int
+	NSFW      bool
+	AI        bool
+	CreatedAt time.Time
+}
+
+func init() {
+	artworks.RegisterArtwork("tumblr", func() artworks.Artwork { return &Artwork{} })
+}
+
+// New creates a Tumblr provider. Empty API key falls back to oEmbed.
+func New(apiKey string) *Tumblr {
+	return &Tumblr{
+		apiKey:  apiKey,
+		regex:   regexp.MustCompile(`(?i)https?://(?:(?:www\.)?tumblr\.com/(?:blog/view/)?([\w-]+)|([\w-]+)\.tumblr\.com/post)/(\d+)`),
+		client:  artworks.NewHTTPClient(),
+		limiter: artworks.NewLimiter(artworks.DefaultConcurrency),
+	}
+}
+
+// Enabled implements artworks.Provider.
+func (*Tumblr) Enabled(g *store.Guild) bool {
+	return g.TumblrEnabled()
+}
+
+// Match implements artworks.Provider. IDs are the blog name and the post ID, e.g. "staff:123".
+func (t *Tumblr) Match(url string) (string, bool) {
+	res := t.regex.FindStringSubmatch(url)
+	if res == nil {
+		return "", false
+	}
+
+	blog := ternary.If(res[1] != "", res[1], res[2])
+	return strings.ToLower(blog) + ":" + res[3], true
+}
+
+// Find implements artworks.Provider.
+func (t *Tumblr) Find(ctx context.Context, id string) (artworks.Artwork, error) {
+	return artworks.WrapError(t, func() (artworks.Artwork, error) {
+		release, err := t.limiter.Acquire(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+
+		blog, postID, _ := strings.Cut(id, ":")
+		if t.apiKey != "" {
+			return t.findNPF(ctx, id, blog, postID)
+		}
+
+		return t.findOEmbed(ctx, id, blog, postID)
+	})
+}
+
+func (t *Tumblr) get(ctx context.Context, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("http get: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return artworks.ErrArtworkNotFound
+	default:
+		return fmt.Errorf("unexpected response status: %v", resp.Status)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("decode: %w", err)
+	}
+
+	return nil
+}
+
+// MessageSends implements artworks.Artwork.
+func (a *Artwork) MessageSends(footer string, tagsEnabled bool) ([]*discordgo.MessageSend, error) {
+	var (
+		length = len(a.Images)
+		title  = a.Blog
+		eb     = embeds.NewBuilder()
+	)
+
+	eb.Title(ternary.If(length > 1,
+		fmt.Sprintf("%v | Page %v / %v", title, 1, length),
+		title,
+	)).URL(a.URL())
+
+	if !a.CreatedAt.IsZero() {
+		eb.Timestamp(a.CreatedAt)
+	}
+
+	desc := a.Text
+	if tagsEnabled && len(a.TagList) > 0 {
+		desc = fmt.Sprintf("%v\n\n**Tags**\n%v", desc, strings.Join(a.TagList, " • "))
+	}
+
+	eb.Description(artworks.EscapeMarkdown(desc))
+
+	if a.Notes > 0 {
+		eb.AddField("Notes", strconv.Itoa(a.Notes), true)
+	}
+
+	if a.AI {
+		eb.AddField("⚠️ Disclaimer", "This artwork is AI-generated.")
+	}
+
+	if footer != "" {
+		eb.Footer(footer, "")
+	}
+
+	if length > 0 {
+		eb.Image(a.Images[0])
+	}
+
+	pages := make([]*discordgo.MessageSend, 0, max(length, 1))
+	pages = append(pages, &discordgo.MessageSend{Embeds: []*discordgo.MessageEmbed{eb.Finalize()}})
+
+	for ind, image := range a.Images[min(length, 1):] {
+		eb := embeds.NewBuilder()
+		eb.Title(fmt.Sprintf("%v | Page %v / %v", title, ind+2, length)).URL(a.URL()).Image(image)
+
+		if footer != "" {
+			eb.Footer(footer, "")
+		}
+
+		pages = append(pages, &discordgo.MessageSend{Embeds: []*discordgo.MessageEmbed{eb.Finalize()}})
+	}
+
+	return pages, nil
+}
+
+// StoreArtwork implements artworks.Artwork.
+func (a *Artwork) StoreArtwork() *store.Artwork {
+	return &store.Artwork{
+		Author: a.Blog,
+		URL:    a.URL(),
+		Images: a.Images,
+		AI:     a.AI,
+	}
+}
+
+// Len implements artworks.Artwork.
+func (a *Artwork) Len() int {
+	return len(a.Images)
+}
+
+// IsNSFW implements artworks.Artwork.
+func (a *Artwork) IsNSFW() bool {
+	return a.NSFW
+}
+
+// AIGenerated implements artworks.Artwork.
+func (a *Artwork) AIGenerated() bool {
+	return a.AI
+}
+
+// Tags implements artworks.Artwork.
+func (a *Artwork) Tags() []string {
+	return a.TagList
+}
+
+func postURL(blog, postID string) string {
+	return fmt.Sprintf("https://www.tumblr.com/%v/%v", url.PathEscape(blog), postID)
+}
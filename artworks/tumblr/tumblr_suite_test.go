@@ -0,0 +1,56 @@
+package tumblr_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/VTGare/boe-tea-go/artworks/tumblr"
+	"github.com/VTGare/boe-tea-go/store"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestTumblr(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Tumblr Suite")
+}
+
+var _ = DescribeTable(
+	"Match Tumblr URL",
+	func(url string, expectedID string, expectedResult bool) {
+		provider := tumblr.New("")
+
+		id, ok := provider.Match(url)
+		Expect(id).To(BeEquivalentTo(expectedID))
+		Expect(ok).To(BeEquivalentTo(expectedResult))
+	},
+	Entry("Dashboard URL", "https://www.tumblr.com/staff/1234", "staff:1234", true),
+	Entry("Dashboard URL with slug", "https://www.tumblr.com/staff/1234/some-post", "staff:1234", true),
+	Entry("Blog view URL", "https://www.tumblr.com/blog/view/staff/1234", "staff:1234", true),
+	Entry("Blog subdomain", "https://Staff.tumblr.com/post/1234/some-post", "staff:1234", true),
+	Entry("Blog page", "https://staff.tumblr.com/", "", false),
+	Entry("Different domain", "https://www.somethingelse.com/staff/1234", "", false),
+)
+
+var _ = DescribeTable(
+	"Enabled in guild",
+	func(doc string, expected bool) {
+		var guild store.Guild
+		Expect(json.Unmarshal([]byte(doc), &guild)).To(Succeed())
+		Expect(tumblr.New("").Enabled(&guild)).To(Equal(expected))
+	},
+	Entry("Guild created before the setting", `{"id": "1"}`, true),
+	Entry("Enabled", `{"id": "1", "tumblr": true}`, true),
+	Entry("Disabled", `{"id": "1", "tumblr": false}`, false),
+)
+
+var _ = Describe("Find", func() {
+	It("should stop when the context is cancelled", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := tumblr.New("").Find(ctx, "staff:1234")
+		Expect(err).To(MatchError(context.Canceled))
+	})
+})
@@ -1,6 +1,7 @@
 package twitter
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -16,7 +17,8 @@ var nonAlphanumericRegex = regexp.MustCompile(`[^\p{L}\p{N} -]+`)
 
 type fxTwitter struct {
 	twitterMatcher
-	client *http.Client
+	client  *http.Client
+	limiter *artworks.Limiter
 }
 
 type fxTwitterResponse struct {
@@ -57,14 +59,25 @@ type fxTwitterResponse struct {
 func newFxTwitter() artworks.Provider {
 	return &fxTwitter{
 		twitterMatcher: twitterMatcher{},
-		client:         &http.Client{},
+		client:         artworks.NewHTTPClient(),
+		limiter:        artworks.NewLimiter(artworks.DefaultConcurrency),
 	}
 }
 
-func (fxt *fxTwitter) Find(id string) (artworks.Artwork, error) {
+func (fxt *fxTwitter) Find(ctx context.Context, id string) (artworks.Artwork, error) {
+	release, err := fxt.limiter.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	url := fmt.Sprintf("https://api.fxtwitter.com/i/status/%v", id)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
 
-	resp, err := fxt.client.Get(url)
+	resp, err := fxt.client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("http get: %w", err)
 	}
@@ -114,7 +127,7 @@ func (fxt *fxTwitter) Find(id string) (artworks.Artwork, error) {
 	artwork := &Artwork{
 		Videos:    videos,
 		Photos:    photos,
-		id:        fxArtwork.Tweet.ID,
+		Source:    artworks.Source{SourceID: fxArtwork.Tweet.ID},
 		FullName:  fxArtwork.Tweet.Author.Name,
 		Username:  username,
 		Content:   fxArtwork.Tweet.Text,
@@ -126,7 +139,7 @@ func (fxt *fxTwitter) Find(id string) (artworks.Artwork, error) {
 		NSFW:      true,
 	}
 
-	artwork.AIGenerated = artworks.IsAIGenerated(arrays.Map(strings.Fields(artwork.Content), func(s string) string {
+	artwork.AI = artworks.IsAIGenerated(arrays.Map(strings.Fields(artwork.Content), func(s string) string {
 		return nonAlphanumericRegex.ReplaceAllString(s, "")
 	})...)
 
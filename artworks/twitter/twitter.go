@@ -2,6 +2,7 @@ package twitter
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -22,8 +23,8 @@ import (
 
 // Common Twitter errors
 var (
-	ErrTweetNotFound  = errors.New("tweet not found")
-	ErrPrivateAccount = errors.New("unable to view this tweet because account is private")
+	ErrTweetNotFound  = artworks.NotFound("tweet not found")
+	ErrPrivateAccount = artworks.NotFound("unable to view this tweet because account is private")
 )
 
 type Twitter struct {
@@ -32,19 +33,24 @@ type Twitter struct {
 }
 
 type Artwork struct {
-	Videos      []Video
-	Photos      []string
-	id          string
-	FullName    string
-	Username    string
-	Content     string
-	Permalink   string
-	Timestamp   time.Time
-	Likes       int
-	Replies     int
-	Retweets    int
-	NSFW        bool
-	AIGenerated bool
+	artworks.Source
+
+	Videos    []Video
+	Photos    []string
+	FullName  string
+	Username  string
+	Content   string
+	Permalink string
+	Timestamp time.Time
+	Likes     int
+	Replies   int
+	Retweets  int
+	NSFW      bool
+	AI        bool
+}
+
+func init() {
+	artworks.RegisterArtwork("twitter", func() artworks.Artwork { return &Artwork{} })
 }
 
 type Video struct {
@@ -61,7 +67,7 @@ func New() artworks.Provider {
 	}
 }
 
-func (t *Twitter) Find(id string) (artworks.Artwork, error) {
+func (t *Twitter) Find(ctx context.Context, id string) (artworks.Artwork, error) {
 	return artworks.WrapError(t, func() (artworks.Artwork, error) {
 		var (
 			artwork artworks.Artwork
@@ -70,8 +76,8 @@ func (t *Twitter) Find(id string) (artworks.Artwork, error) {
 
 		for _, provider := range t.providers {
 			var err error
-			artwork, err = provider.Find(id)
-			if errors.Is(err, ErrTweetNotFound) || errors.Is(err, ErrPrivateAccount) {
+			artwork, err = provider.Find(ctx, id)
+			if errors.Is(err, ErrTweetNotFound) || errors.Is(err, ErrPrivateAccount) || ctx.Err() != nil {
 				return nil, err
 			}
 
@@ -99,6 +105,7 @@ func (a *Artwork) StoreArtwork() *store.Artwork {
 		Author: a.Username,
 		URL:    a.Permalink,
 		Images: media,
+		AI:     a.AI,
 	}
 }
 
@@ -129,7 +136,7 @@ func (a *Artwork) MessageSends(footer string, _ bool) ([]*discordgo.MessageSend,
 		eb.Footer(footer, "")
 	}
 
-	if a.AIGenerated {
+	if a.AI {
 		eb.AddField("⚠️ Disclaimer", "This artwork is AI-generated.")
 	}
 
@@ -170,10 +177,6 @@ func (a *Artwork) MessageSends(footer string, _ bool) ([]*discordgo.MessageSend,
 	return tweets, nil
 }
 
-func (a *Artwork) ID() string {
-	return a.id
-}
-
 func (a *Artwork) videoEmbed(eb *embeds.Builder) ([]*discordgo.MessageSend, error) {
 	files := make([]*discordgo.File, 0, len(a.Videos))
 	for _, video := range a.Videos {
@@ -231,3 +234,28 @@ func (a *Artwork) Len() int {
 
 	return len(a.Photos)
 }
+
+func (a *Artwork) IsNSFW() bool {
+	return a.NSFW
+}
+
+func (a *Artwork) AIGenerated() bool {
+	return a.AI
+}
+
+// Tags returns hashtags of the tweet.
+func (a *Artwork) Tags() []string {
+	tags := make([]string, 0)
+	for _, word := range strings.Fields(a.Content) {
+		tag, ok := strings.CutPrefix(word, "#")
+		if !ok {
+			continue
+		}
+
+		if tag = nonAlphanumericRegex.ReplaceAllString(tag, ""); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+
+	return tags
+}
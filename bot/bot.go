@@ -5,19 +5,20 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/ReneKroon/ttlcache"
 	"github.com/VTGare/boe-tea-go/artworks"
+	"github.com/VTGare/boe-tea-go/artworks/pixiv"
 	"github.com/VTGare/boe-tea-go/internal/apis/nhentai"
+	"github.com/VTGare/boe-tea-go/internal/bus"
 	"github.com/VTGare/boe-tea-go/internal/cache"
 	"github.com/VTGare/boe-tea-go/internal/config"
+	"github.com/VTGare/boe-tea-go/messages"
 	"github.com/VTGare/boe-tea-go/repost"
 	"github.com/VTGare/boe-tea-go/stats"
 	"github.com/VTGare/boe-tea-go/store"
+	"github.com/VTGare/embeds"
 	"github.com/VTGare/gumi"
 	"github.com/VTGare/sengoku"
 	"github.com/bwmarrin/discordgo"
-	goCache "github.com/patrickmn/go-cache"
-	"github.com/servusdei2018/shards/v2"
 	"go.uber.org/zap"
 )
 
@@ -31,17 +32,19 @@ type Bot struct {
 	Context   context.Context
 
 	// caches
-	BannedUsers  *ttlcache.Cache
-	EmbedCache   *cache.EmbedCache
-	ArtworkCache *goCache.Cache
+	BannedUsers  *cache.BannedUsers
+	EmbedCache   *EmbedCache
+	ArtworkCache cache.Backend
 
 	// services
-	Sengoku          *sengoku.Sengoku
-	NHentai          *nhentai.API
-	ArtworkProviders []artworks.Provider
-	RepostDetector   repost.Detector
-
-	ShardManager *shards.Manager
+	Sengoku        *sengoku.Sengoku
+	NHentai        *nhentai.API
+	Providers      *artworks.Registry
+	PixivTokens    *pixiv.TokenManager
+	RepostDetector repost.Detector
+
+	ShardManager *ShardManager
+	Bus          bus.Bus
 	Store        store.Store
 }
 
@@ -50,46 +53,82 @@ func New(
 	store store.Store,
 	logger *zap.SugaredLogger,
 	rd repost.Detector,
+	sharedCache cache.Backend,
+	msgBus bus.Bus,
 ) (*Bot, error) {
-	mgr, err := shards.New("Bot " + config.Discord.Token)
+	var (
+		shardCount int
+		shardIDs   []int
+	)
+
+	if config.Shards != nil {
+		ids, err := config.Shards.ShardIDs()
+		if err != nil {
+			return nil, err
+		}
+
+		shardCount, shardIDs = config.Shards.Count, ids
+	}
+
+	mgr, err := NewShardManager("Bot "+config.Discord.Token, shardCount, shardIDs)
 	if err != nil {
 		return nil, fmt.Errorf("failed to init a shard manager: %w", err)
 	}
 
 	mgr.RegisterIntent(discordgo.IntentsAllWithoutPrivileged | discordgo.IntentMessageContent)
-	banned := ttlcache.NewCache()
-	banned.SetTTL(15 * time.Second)
 
 	sg := sengoku.NewSengoku(config.SauceNAO, sengoku.Config{
 		DB:      999,
 		Results: 10,
 	})
 
-	nh, err := nhentai.New()
+	nh, err := nhentai.New(config.NHentai.Host(), sharedCache)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create nhentai api client: %w", err)
 	}
 
-	return &Bot{
+	b := &Bot{
 		Log:            logger,
 		Config:         config,
 		RepostDetector: rd,
-		BannedUsers:    banned,
-		EmbedCache:     cache.NewEmbedCache(),
-		ArtworkCache:   goCache.New(60*time.Minute, 90*time.Minute),
+		BannedUsers:    cache.NewBannedUsers(sharedCache),
+		EmbedCache:     NewEmbedCache(sharedCache, store, config.Embeds.Retention()),
+		ArtworkCache:   sharedCache,
 		NHentai:        nh,
+		Providers:      artworks.NewRegistry(),
+		PixivTokens:    pixiv.NewTokenManager(config.Pixiv.Tokens()),
 		Sengoku:        sg,
 		ShardManager:   mgr,
+		Bus:            msgBus,
 		Store:          store,
-	}, nil
+	}
+
+	b.PixivTokens.OnError = b.onPixivTokensError
+	return b, nil
 }
 
 func (b *Bot) AddRouter(router *gumi.Router) {
 	b.Router = gumi.Create(router)
 }
 
-func (b *Bot) AddProvider(provider artworks.Provider) {
-	b.ArtworkProviders = append(b.ArtworkProviders, provider)
+func (b *Bot) AddProvider(name string, provider artworks.Provider) {
+	b.Providers.Register(name, provider)
+}
+
+// NotifyOwner sends a direct message to the bot's author.
+func (b *Bot) NotifyOwner(embed *discordgo.MessageEmbed) error {
+	s, err := b.ShardManager.SessionForDM()
+	if err != nil {
+		return err
+	}
+
+	ch, err := s.UserChannelCreate(b.Config.Discord.AuthorID)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.ChannelMessageSendEmbed(ch.ID, embed)
+	return err
 }
 
 func (b *Bot) AddHandler(handler any) {
@@ -100,14 +139,21 @@ func (b *Bot) Start(ctx context.Context) error {
 	b.ShardManager.AddHandler(b.Router.Handler())
 
 	b.StartTime = time.Now()
-	b.Stats = stats.New(b.Router, b.ArtworkProviders)
+	b.Stats = stats.New(b.Router, b.Providers.Names())
 	b.Context = ctx
 
-	b.Log.Debug("starting a bot")
+	b.Log.With("shards", b.ShardManager.IDs(), "shard_count", b.ShardManager.ShardCount).Debug("starting a bot")
 	if err := b.ShardManager.Start(); err != nil {
 		return err
 	}
 
+	if err := b.handleMessages(); err != nil {
+		return fmt.Errorf("failed to subscribe to the message bus: %w", err)
+	}
+
+	go b.EmbedCache.deleteExpired(ctx, b.Log)
+	go b.PixivTokens.Run(ctx)
+
 	select {
 	case <-ctx.Done():
 		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -115,8 +161,20 @@ func (b *Bot) Start(ctx context.Context) error {
 
 		b.Store.Close(shutdownCtx)
 		b.RepostDetector.Close()
+		b.ArtworkCache.Close()
+		b.Bus.Close()
 		b.ShardManager.Shutdown()
 
 		return ctx.Err()
 	}
 }
+
+// onPixivTokensError lets the owner know Pixiv needs a new refresh token.
+func (b *Bot) onPixivTokensError(err error) {
+	b.Log.With("error", err).Warn("pixiv token manager error")
+
+	eb := embeds.NewBuilder().FailureTemplate(messages.PixivTokensFailed(err))
+	if err := b.NotifyOwner(eb.Finalize()); err != nil {
+		b.Log.With("error", err).Error("failed to notify the owner")
+	}
+}
@@ -0,0 +1,107 @@
+package bot_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/VTGare/boe-tea-go/bot"
+	"github.com/VTGare/boe-tea-go/internal/cache"
+	"github.com/VTGare/boe-tea-go/store"
+	"github.com/VTGare/boe-tea-go/store/memory"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestBot(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Bot Suite")
+}
+
+var _ = Describe("EmbedCache", func() {
+	var (
+		ctx context.Context
+		db  store.Store
+	)
+
+	BeforeEach(func() {
+		var err error
+		ctx = context.Background()
+		db, err = memory.New("")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should find posts in the store after the hot cache is gone", func() {
+		children := []*cache.MessageInfo{{ChannelID: "3", MessageID: "4", ArtworkID: "5"}}
+
+		ec := bot.NewEmbedCache(cache.NewMemory(time.Minute, time.Minute), db, 30*24*time.Hour)
+		Expect(ec.Set(ctx, "1", "2", "10", children...)).To(Succeed())
+
+		// A restarted process has an empty hot cache.
+		ec = bot.NewEmbedCache(cache.NewMemory(time.Minute, time.Minute), db, 30*24*time.Hour)
+
+		post, ok := ec.Get(ctx, "2", "10")
+		Expect(ok).To(BeTrue())
+		Expect(post.AuthorID).To(Equal("1"))
+		Expect(post.IsParent).To(BeTrue())
+		Expect(post.Children).To(Equal(children))
+
+		Expect(ec.Remove(ctx, "2", "10")).To(Succeed())
+
+		_, ok = ec.Get(ctx, "2", "10")
+		Expect(ok).To(BeFalse())
+
+		child, ok := ec.Get(ctx, "3", "4")
+		Expect(ok).To(BeTrue())
+		Expect(child.AuthorID).To(Equal("1"))
+		Expect(child.IsParent).To(BeFalse())
+	})
+
+	It("should remember messages that aren't posts until they're set", func() {
+		ec := bot.NewEmbedCache(cache.NewMemory(time.Minute, time.Minute), db, 30*24*time.Hour)
+
+		_, ok := ec.Get(ctx, "2", "10")
+		Expect(ok).To(BeFalse())
+
+		// Saved by another process, the miss is still remembered.
+		Expect(db.SavePosts(ctx, &store.Post{
+			ChannelID: "2",
+			MessageID: "10",
+			AuthorID:  "1",
+			ExpiresAt: time.Now().Add(time.Hour),
+		})).To(Succeed())
+
+		_, ok = ec.Get(ctx, "2", "10")
+		Expect(ok).To(BeFalse())
+
+		Expect(ec.Set(ctx, "1", "2", "10")).To(Succeed())
+
+		_, ok = ec.Get(ctx, "2", "10")
+		Expect(ok).To(BeTrue())
+	})
+
+	It("should forget posts after the retention period", func() {
+		ec := bot.NewEmbedCache(cache.NewMemory(time.Minute, time.Minute), db, -time.Minute)
+		Expect(ec.Set(ctx, "1", "2", "10")).To(Succeed())
+
+		ec = bot.NewEmbedCache(cache.NewMemory(time.Minute, time.Minute), db, -time.Minute)
+
+		_, ok := ec.Get(ctx, "2", "10")
+		Expect(ok).To(BeFalse())
+	})
+})
+
+var _ = Describe("ShardManager", func() {
+	It("should have no session before it's started and after it's shut down", func() {
+		m, err := bot.NewShardManager("token", 2, []int{0})
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = m.Session()
+		Expect(err).To(MatchError(bot.ErrNoShards))
+
+		Expect(m.Shutdown()).To(Succeed())
+
+		_, err = m.SessionForDM()
+		Expect(err).To(MatchError(bot.ErrNoShards))
+	})
+})
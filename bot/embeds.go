@@ -0,0 +1,166 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/VTGare/boe-tea-go/internal/cache"
+	"github.com/VTGare/boe-tea-go/store"
+	"go.uber.org/zap"
+)
+
+// EmbedCache keeps track of embeds users posted, so OnReactionAdd and OnMessageRemove can remove them
+// with their crossposts. Posts are persisted in the store for the retention period, recently used posts
+// are also kept in the cache backend to avoid hitting the store on every reaction.
+type EmbedCache struct {
+	hot       cache.Backend
+	store     store.PostStore
+	retention time.Duration
+}
+
+const (
+	hotEmbedTTL = 15 * time.Minute
+	// missEmbedTTL is how long messages that aren't posts are remembered. Reactions and deletions
+	// of any message look posts up, it keeps them from hitting the store every time.
+	missEmbedTTL = time.Minute
+)
+
+// NewEmbedCache creates a new embed cache for storing IDs of embeds users posted.
+func NewEmbedCache(hot cache.Backend, store store.PostStore, retention time.Duration) *EmbedCache {
+	return &EmbedCache{hot, store, retention}
+}
+
+func (ec *EmbedCache) makeKey(channelID, messageID string) string {
+	return fmt.Sprintf(
+		"embeds:channel:%v:message:%v",
+		channelID,
+		messageID,
+	)
+}
+
+// Get returns a post from the cache or the store. Errors are treated as misses.
+func (ec *EmbedCache) Get(ctx context.Context, channelID, messageID string) (*cache.CachedPost, bool) {
+	key := ec.makeKey(
+		channelID, messageID,
+	)
+
+	if data, err := ec.hot.Get(ctx, key); err == nil {
+		// Messages that aren't posts are cached as empty values.
+		if len(data) == 0 {
+			return nil, false
+		}
+
+		var embed cache.CachedPost
+		if err := json.Unmarshal(data, &embed); err == nil {
+			return &embed, true
+		}
+	}
+
+	post, err := ec.store.Post(ctx, channelID, messageID)
+	if err != nil {
+		if errors.Is(err, store.ErrPostNotFound) {
+			ec.hot.Set(ctx, key, []byte{}, missEmbedTTL)
+		}
+
+		return nil, false
+	}
+
+	embed := &cache.CachedPost{
+		AuthorID: post.AuthorID,
+		IsParent: post.IsParent,
+		Children: make([]*cache.MessageInfo, 0, len(post.Children)),
+	}
+
+	for _, child := range post.Children {
+		embed.Children = append(embed.Children, &cache.MessageInfo{
+			MessageID: child.MessageID,
+			ChannelID: child.ChannelID,
+			ArtworkID: child.ArtworkID,
+		})
+	}
+
+	cache.SetJSON(ctx, ec.hot, key, embed, hotEmbedTTL)
+	return embed, true
+}
+
+// Set saves a message users posted and all messages Boe Tea sent for it in one write.
+// Sent messages are saved as posts without children, so they can be removed on their own.
+func (ec *EmbedCache) Set(ctx context.Context, userID, channelID, messageID string, children ...*cache.MessageInfo) error {
+	now := time.Now()
+	newPost := func(channelID, messageID string, isParent bool) *store.Post {
+		return &store.Post{
+			ChannelID: channelID,
+			MessageID: messageID,
+			AuthorID:  userID,
+			IsParent:  isParent,
+			Children:  make([]*store.PostMessage, 0),
+			CreatedAt: now,
+			ExpiresAt: now.Add(ec.retention),
+		}
+	}
+
+	parent := newPost(channelID, messageID, true)
+	posts := []*store.Post{parent}
+	for _, child := range children {
+		parent.Children = append(parent.Children, &store.PostMessage{
+			ChannelID: child.ChannelID,
+			MessageID: child.MessageID,
+			ArtworkID: child.ArtworkID,
+		})
+
+		posts = append(posts, newPost(child.ChannelID, child.MessageID, false))
+	}
+
+	if err := ec.store.SavePosts(ctx, posts...); err != nil {
+		return err
+	}
+
+	errs := []error{cache.SetJSON(ctx, ec.hot, ec.makeKey(channelID, messageID), &cache.CachedPost{
+		AuthorID: userID,
+		IsParent: true,
+		Children: children,
+	}, hotEmbedTTL)}
+
+	for _, child := range children {
+		errs = append(errs, cache.SetJSON(ctx, ec.hot, ec.makeKey(child.ChannelID, child.MessageID), &cache.CachedPost{
+			AuthorID: userID,
+		}, hotEmbedTTL))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (ec *EmbedCache) Remove(ctx context.Context, channelID, messageID string) error {
+	key := ec.makeKey(
+		channelID, messageID,
+	)
+
+	return errors.Join(
+		ec.hot.Delete(ctx, key),
+		ec.store.DeletePost(ctx, channelID, messageID),
+	)
+}
+
+// deleteExpired periodically removes posts older than the retention period from the store.
+func (ec *EmbedCache) deleteExpired(ctx context.Context, log *zap.SugaredLogger) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			deleted, err := ec.store.DeleteExpiredPosts(ctx, time.Now())
+			if err != nil {
+				log.With("error", err).Warn("failed to delete expired posts")
+				continue
+			}
+
+			log.With("deleted", deleted).Debug("deleted expired posts")
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/VTGare/boe-tea-go/internal/bus"
+	"github.com/bwmarrin/discordgo"
+)
+
+// ErrUnknownDelivery is returned if a message was requested from another process, but the reply never came.
+// The message may have been sent anyway, requesting it again could send a duplicate.
+var ErrUnknownDelivery = errors.New("message delivery is unknown")
+
+// sendRequest is a message sent over the message bus to a process running guild's shard.
+type sendRequest struct {
+	ChannelID string                 `json:"channel_id"`
+	Message   *discordgo.MessageSend `json:"message"`
+}
+
+// sendReply is a reply to sendRequest. Discord errors are replied with their status and body
+// instead of failing the request, so the requesting process gets a *discordgo.RESTError back.
+type sendReply struct {
+	Message *discordgo.Message `json:"message,omitempty"`
+	Error   *restError         `json:"error,omitempty"`
+}
+
+// restError is a *discordgo.RESTError or a *discordgo.RateLimitError sent over the message bus.
+type restError struct {
+	StatusCode int    `json:"status_code"`
+	Body       []byte `json:"body,omitempty"`
+	RetryAfter string `json:"retry_after,omitempty"`
+}
+
+// newRESTError encodes Discord errors. Returns false for other errors.
+func newRESTError(err error) (*restError, bool) {
+	var rateLimitErr *discordgo.RateLimitError
+	if errors.As(err, &rateLimitErr) && rateLimitErr.RateLimit != nil {
+		return &restError{
+			StatusCode: http.StatusTooManyRequests,
+			RetryAfter: strconv.FormatFloat(rateLimitErr.RetryAfter.Seconds(), 'f', -1, 64),
+		}, true
+	}
+
+	var restErr *discordgo.RESTError
+	if errors.As(err, &restErr) && restErr.Response != nil {
+		return &restError{
+			StatusCode: restErr.Response.StatusCode,
+			Body:       restErr.ResponseBody,
+			RetryAfter: restErr.Response.Header.Get("Retry-After"),
+		}, true
+	}
+
+	return nil, false
+}
+
+// err rebuilds the Discord error, the request is unknown to the requesting process.
+func (e *restError) err() error {
+	header := make(http.Header)
+	if e.RetryAfter != "" {
+		header.Set("Retry-After", e.RetryAfter)
+	}
+
+	restErr := &discordgo.RESTError{
+		Response: &http.Response{
+			Status:     fmt.Sprintf("%v %v", e.StatusCode, http.StatusText(e.StatusCode)),
+			StatusCode: e.StatusCode,
+			Header:     header,
+		},
+		ResponseBody: e.Body,
+	}
+
+	var msg *discordgo.APIErrorMessage
+	if err := json.Unmarshal(e.Body, &msg); err == nil {
+		restErr.Message = msg
+	}
+
+	return restErr
+}
+
+func messagesSubject(shardID int) string {
+	return fmt.Sprintf("shards:%v:messages", shardID)
+}
+
+// SendComplex sends a message to a channel of the guild. If guild's shard runs on another process, the message
+// is sent by that process over the message bus. Messages with files can't be encoded and are sent by the first
+// local session, as well as messages to shards no process handles. Failed requests to other processes return
+// ErrUnknownDelivery.
+func (b *Bot) SendComplex(ctx context.Context, guildID, channelID string, message *discordgo.MessageSend) (*discordgo.Message, error) {
+	id, err := strconv.ParseInt(guildID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse guild id: %w", err)
+	}
+
+	if s, ok := b.ShardManager.SessionForGuild(id); ok {
+		return s.ChannelMessageSendComplex(channelID, message)
+	}
+
+	if len(message.Files) != 0 {
+		return b.sendLocal(channelID, message)
+	}
+
+	data, err := json.Marshal(sendRequest{ChannelID: channelID, Message: message})
+	if err != nil {
+		return nil, err
+	}
+
+	shardID := b.ShardManager.ShardForGuild(id)
+	res, err := b.Bus.Request(ctx, messagesSubject(shardID), data)
+	if err != nil {
+		if errors.Is(err, bus.ErrNoHandler) {
+			b.Log.With("guild_id", guildID, "shard_id", shardID).Warn("no process handles the shard, sending a message locally")
+			return b.sendLocal(channelID, message)
+		}
+
+		return nil, fmt.Errorf("%w: %w", ErrUnknownDelivery, err)
+	}
+
+	var reply sendReply
+	if err := json.Unmarshal(res, &reply); err != nil {
+		return nil, err
+	}
+
+	if reply.Error != nil {
+		return nil, reply.Error.err()
+	}
+
+	return reply.Message, nil
+}
+
+// sendLocal sends a message by the first local session.
+func (b *Bot) sendLocal(channelID string, message *discordgo.MessageSend) (*discordgo.Message, error) {
+	s, err := b.ShardManager.Session()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.ChannelMessageSendComplex(channelID, message)
+}
+
+// handleMessages sends messages other processes requested to send to guilds of local shards.
+func (b *Bot) handleMessages() error {
+	for _, shard := range b.ShardManager.Shards {
+		session := shard.Session
+
+		err := b.Bus.Handle(messagesSubject(shard.ID), func(_ context.Context, data []byte) ([]byte, error) {
+			var req sendRequest
+			if err := json.Unmarshal(data, &req); err != nil {
+				return nil, err
+			}
+
+			msg, err := session.ChannelMessageSendComplex(req.ChannelID, req.Message)
+			if err != nil {
+				restErr, ok := newRESTError(err)
+				if !ok {
+					return nil, err
+				}
+
+				return json.Marshal(sendReply{Error: restErr})
+			}
+
+			return json.Marshal(sendReply{Message: msg})
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/VTGare/boe-tea-go/internal/bus"
+	"github.com/bwmarrin/discordgo"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Send reply", func() {
+	roundTrip := func(err error) error {
+		restErr, ok := newRESTError(err)
+		Expect(ok).To(BeTrue())
+
+		data, err := json.Marshal(sendReply{Error: restErr})
+		Expect(err).NotTo(HaveOccurred())
+
+		var reply sendReply
+		Expect(json.Unmarshal(data, &reply)).To(Succeed())
+		Expect(reply.Error).NotTo(BeNil())
+
+		return reply.Error.err()
+	}
+
+	It("should keep the Discord error code", func() {
+		err := roundTrip(fmt.Errorf("failed to send: %w", &discordgo.RESTError{
+			Response:     &http.Response{StatusCode: http.StatusNotFound, Status: "404 Not Found"},
+			ResponseBody: []byte(`{"code": 10003, "message": "Unknown Channel"}`),
+		}))
+
+		var restErr *discordgo.RESTError
+		Expect(errors.As(err, &restErr)).To(BeTrue())
+		Expect(restErr.Response.StatusCode).To(Equal(http.StatusNotFound))
+		Expect(restErr.Message).NotTo(BeNil())
+		Expect(restErr.Message.Code).To(Equal(discordgo.ErrCodeUnknownChannel))
+		Expect(err.Error()).To(ContainSubstring("404 Not Found"))
+	})
+
+	It("should keep the retry delay of rate limits", func() {
+		err := roundTrip(&discordgo.RateLimitError{RateLimit: &discordgo.RateLimit{
+			TooManyRequests: &discordgo.TooManyRequests{RetryAfter: 1500 * time.Millisecond},
+		}})
+
+		var restErr *discordgo.RESTError
+		Expect(errors.As(err, &restErr)).To(BeTrue())
+		Expect(restErr.Response.StatusCode).To(Equal(http.StatusTooManyRequests))
+		Expect(restErr.Response.Header.Get("Retry-After")).To(Equal("1.5"))
+	})
+
+	It("should not encode other errors", func() {
+		_, ok := newRESTError(errors.New("connection reset"))
+		Expect(ok).To(BeFalse())
+	})
+})
+
+var _ = Describe("SendComplex", func() {
+	It("should report messages other processes failed to reply to as unknown", func() {
+		b := &Bot{ShardManager: &ShardManager{ShardCount: 1}, Bus: bus.NewMemory()}
+		Expect(b.Bus.Handle(messagesSubject(0), func(context.Context, []byte) ([]byte, error) {
+			return nil, context.DeadlineExceeded
+		})).To(Succeed())
+
+		_, err := b.SendComplex(context.Background(), "1", "2", &discordgo.MessageSend{Content: "art"})
+		Expect(err).To(MatchError(ErrUnknownDelivery))
+	})
+})
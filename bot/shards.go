@@ -0,0 +1,226 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/servusdei2018/shards/v2"
+)
+
+// ErrNoShards is returned when the process runs no shards, before the shard manager is started or after it's shut down.
+var ErrNoShards = errors.New("no running shards")
+
+// ShardManager runs a subset of the bot's shards. Other shards may run on different processes,
+// guilds of those shards have no local session.
+type ShardManager struct {
+	sync.RWMutex
+
+	// Shards running on this process.
+	Shards []*shards.Shard
+	// Total shard count of all processes.
+	ShardCount int
+
+	ids      []int
+	token    string
+	intent   discordgo.Intent
+	handlers []any
+	// stopped is set by Shutdown to stop Start from connecting the remaining shards.
+	stopped bool
+}
+
+// NewShardManager creates a shard manager for given shard IDs. If count is 0, Discord's recommended
+// shard count is used. If IDs are empty, the manager runs all shards.
+func NewShardManager(token string, count int, ids []int) (*ShardManager, error) {
+	if count == 0 {
+		gateway, err := discordgo.New(token)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := gateway.GatewayBot()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get recommended shard count: %w", err)
+		}
+
+		count = max(resp.Shards, 1)
+	}
+
+	if len(ids) == 0 {
+		for id := range count {
+			ids = append(ids, id)
+		}
+	}
+
+	ids = slices.Compact(slices.Sorted(slices.Values(ids)))
+	for _, id := range ids {
+		if id < 0 || id >= count {
+			return nil, fmt.Errorf("shard id %v is out of range [0, %v)", id, count)
+		}
+	}
+
+	return &ShardManager{
+		ShardCount: count,
+		ids:        ids,
+		token:      token,
+	}, nil
+}
+
+// IDs returns IDs of shards running on this process.
+func (m *ShardManager) IDs() []int {
+	return m.ids
+}
+
+// Owns reports whether a shard is running on this process.
+func (m *ShardManager) Owns(shardID int) bool {
+	return slices.Contains(m.ids, shardID)
+}
+
+// ShardForGuild returns shard ID of a guild. See https://discord.com/developers/docs/topics/gateway#sharding
+func (m *ShardManager) ShardForGuild(guildID int64) int {
+	return int((guildID >> 22) % int64(m.ShardCount))
+}
+
+func (m *ShardManager) RegisterIntent(intent discordgo.Intent) {
+	m.Lock()
+	defer m.Unlock()
+
+	m.intent = intent
+}
+
+func (m *ShardManager) AddHandler(handler any) {
+	m.Lock()
+	defer m.Unlock()
+
+	m.handlers = append(m.handlers, handler)
+	for _, shard := range m.Shards {
+		shard.AddHandler(handler)
+	}
+}
+
+// Start connects all shards of the process. Shards are usable as soon as they're connected,
+// the lock isn't held while waiting to connect the next one.
+func (m *ShardManager) Start() error {
+	m.Lock()
+	m.Shards = make([]*shards.Shard, 0, len(m.ids))
+	m.stopped = false
+	m.Unlock()
+
+	for i, id := range m.ids {
+		started, err := m.startShard(id)
+		if err != nil || !started {
+			return err
+		}
+
+		// Discord allows one identify request every 5 seconds.
+		if i != len(m.ids)-1 {
+			time.Sleep(shards.TIMELIMIT)
+		}
+	}
+
+	return nil
+}
+
+// startShard connects a shard and adds it to running shards. Returns false if the manager has been shut down.
+func (m *ShardManager) startShard(id int) (bool, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	if m.stopped {
+		return false, nil
+	}
+
+	shard := &shards.Shard{}
+	for _, handler := range m.handlers {
+		shard.AddHandler(handler)
+	}
+
+	if err := shard.Init(m.token, id, m.ShardCount, m.intent); err != nil {
+		return false, fmt.Errorf("failed to start shard %v: %w", id, err)
+	}
+
+	m.Shards = append(m.Shards, shard)
+	return true, nil
+}
+
+// Shutdown disconnects all shards of the process.
+func (m *ShardManager) Shutdown() error {
+	m.Lock()
+	defer m.Unlock()
+
+	m.stopped = true
+
+	for _, shard := range m.Shards {
+		if err := shard.Stop(); err != nil {
+			return err
+		}
+	}
+
+	m.Shards = nil
+	return nil
+}
+
+// GuildCount returns the amount of guilds handled by shards of the process.
+func (m *ShardManager) GuildCount() int {
+	m.RLock()
+	defer m.RUnlock()
+
+	var count int
+	for _, shard := range m.Shards {
+		count += shard.GuildCount()
+	}
+
+	return count
+}
+
+// SessionForGuild returns a session of the guild's shard. Returns false if the shard runs on another process.
+func (m *ShardManager) SessionForGuild(guildID int64) (*discordgo.Session, bool) {
+	m.RLock()
+	defer m.RUnlock()
+
+	id := m.ShardForGuild(guildID)
+	for _, shard := range m.Shards {
+		if shard.ID == id {
+			return shard.Session, true
+		}
+	}
+
+	return nil, false
+}
+
+// SessionForDM returns a session to send direct messages. Only shard 0 receives direct messages,
+// but any session can send them because it's a REST request. Shards are sorted, so it's shard 0 if the process runs it.
+func (m *ShardManager) SessionForDM() (*discordgo.Session, error) {
+	return m.Session()
+}
+
+// Session returns a session of the first shard of the process. It's used for REST requests
+// that don't depend on a shard.
+func (m *ShardManager) Session() (*discordgo.Session, error) {
+	m.RLock()
+	defer m.RUnlock()
+
+	if len(m.Shards) == 0 {
+		return nil, ErrNoShards
+	}
+
+	return m.Shards[0].Session, nil
+}
+
+// Channel returns a channel from the state of the process's first shard or requests it from Discord.
+// It's used to validate channels of guilds the process may not run the shard of.
+func (m *ShardManager) Channel(channelID string) (*discordgo.Channel, error) {
+	s, err := m.Session()
+	if err != nil {
+		return nil, err
+	}
+
+	if ch, err := s.State.Channel(channelID); err == nil {
+		return ch, nil
+	}
+
+	return s.Channel(channelID)
+}
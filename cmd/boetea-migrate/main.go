@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/VTGare/boe-tea-go/internal/config"
+	"github.com/VTGare/boe-tea-go/store"
+	"github.com/VTGare/boe-tea-go/store/backend"
+	"github.com/VTGare/boe-tea-go/store/migrate"
+	"go.uber.org/zap"
+)
+
+// openStore opens a store from the configuration file and returns it along with its description.
+func openStore(ctx context.Context, path string) (store.Store, string, error) {
+	cfg, err := config.FromFile(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read config %v: %w", path, err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	db, err := backend.Open(ctx, cfg)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return db, backend.Describe(cfg), nil
+}
+
+func main() {
+	var (
+		source      = flag.String("source", "config.json", "configuration file of the source store")
+		destination = flag.String("destination", "", "configuration file of the destination store")
+		batchSize   = flag.Int("batch", 500, "number of entities migrated in a single batch")
+		dryRun      = flag.Bool("dry-run", false, "read the source store without writing to the destination")
+		checkpoint  = flag.String("checkpoint", "boetea-migrate.json", "checkpoint file used to resume interrupted migrations of the same stores, empty to disable")
+		verifyOnly  = flag.Bool("verify", false, "only compare counts and checksums of both stores")
+	)
+
+	flag.Parse()
+
+	if *destination == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	zapLogger, err := zap.NewProduction()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	log := zapLogger.Sugar()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM, os.Interrupt)
+	defer cancel()
+
+	src, srcName, err := openStore(ctx, *source)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer src.Close(context.Background())
+
+	dst, dstName, err := openStore(ctx, *destination)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer dst.Close(context.Background())
+
+	m, err := migrate.New(src, dst, migrate.Options{
+		BatchSize:   *batchSize,
+		DryRun:      *dryRun,
+		Checkpoint:  *checkpoint,
+		Source:      srcName,
+		Destination: dstName,
+	}, log)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if !*verifyOnly {
+		stats, err := m.Run(ctx)
+		if err != nil {
+			log.With("error", err, "stats", stats).Fatal("migration failed, rerun to resume from the last checkpoint")
+		}
+
+		log.With(
+			"guilds", stats.Guilds,
+			"users", stats.Users,
+			"artworks", stats.Artworks,
+			"bookmarks", stats.Bookmarks,
+			"posts", stats.Posts,
+			"dry_run", *dryRun,
+		).Info("migration finished")
+
+		if *dryRun {
+			return
+		}
+	}
+
+	results, err := m.Verify(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ok := true
+	for _, res := range results {
+		log := log.With(
+			"entity", res.Name,
+			"source_count", res.SourceCount,
+			"destination_count", res.DestCount,
+			"source_checksum", res.SourceChecksum,
+			"destination_checksum", res.DestChecksum,
+		)
+
+		if res.OK() {
+			log.Info("verified")
+			continue
+		}
+
+		ok = false
+		log.Error("verification failed")
+	}
+
+	if !ok {
+		os.Exit(1)
+	}
+}
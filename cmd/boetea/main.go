@@ -3,44 +3,49 @@ package main
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
+	"github.com/VTGare/boe-tea-go/api"
+	"github.com/VTGare/boe-tea-go/artworks/artstation"
 	"github.com/VTGare/boe-tea-go/artworks/bluesky"
 	"github.com/VTGare/boe-tea-go/artworks/deviant"
+	"github.com/VTGare/boe-tea-go/artworks/fanbox"
+	"github.com/VTGare/boe-tea-go/artworks/mastodon"
+	"github.com/VTGare/boe-tea-go/artworks/nhentai"
 	"github.com/VTGare/boe-tea-go/artworks/pixiv"
+	"github.com/VTGare/boe-tea-go/artworks/tumblr"
 	"github.com/VTGare/boe-tea-go/artworks/twitter"
 	"github.com/VTGare/boe-tea-go/bot"
 	"github.com/VTGare/boe-tea-go/commands"
 	"github.com/VTGare/boe-tea-go/handlers"
+	"github.com/VTGare/boe-tea-go/internal/bus"
+	"github.com/VTGare/boe-tea-go/internal/cache"
 	"github.com/VTGare/boe-tea-go/internal/config"
+	"github.com/VTGare/boe-tea-go/internal/imageproxy"
 	"github.com/VTGare/boe-tea-go/internal/logger"
 	"github.com/VTGare/boe-tea-go/repost"
 	"github.com/VTGare/boe-tea-go/store"
-	"github.com/VTGare/boe-tea-go/store/mongo"
+	"github.com/VTGare/boe-tea-go/store/backend"
 	"github.com/VTGare/gumi"
 
 	"github.com/getsentry/sentry-go"
-	cache "github.com/patrickmn/go-cache"
 	"go.uber.org/zap"
 )
 
-func initStore(ctx context.Context, mongoURI, database string) (store.Store, error) {
+func initStore(ctx context.Context, cfg *config.Config, c cache.Backend) (store.Store, error) {
 	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
 	defer cancel()
 
-	mongo, err := mongo.New(ctx, mongoURI, database)
+	db, err := backend.Open(ctx, cfg)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := mongo.Init(ctx); err != nil {
-		return nil, err
-	}
-
-	store := store.NewStatefulStore(mongo, cache.New(30*time.Minute, 1*time.Hour))
+	store := store.NewStatefulStore(db, c, 30*time.Minute)
 	return store, nil
 }
 
@@ -73,7 +78,36 @@ func main() {
 	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM, os.Interrupt)
 	defer cancel()
 
-	store, err := initStore(ctx, cfg.Mongo.URI, cfg.Mongo.Database)
+	// Processes running different shards handle events of each other's messages, so they must share state.
+	if cfg.Shards.Distributed() {
+		if cfg.Cache == nil || cfg.Cache.Type != "redis" || cfg.Repost.Type != "redis" {
+			log.Fatal("cache and repost types must be redis if shards are distributed between processes")
+		}
+	}
+
+	var sharedCache cache.Backend
+	switch {
+	case cfg.Cache != nil && cfg.Cache.Type == "redis":
+		sharedCache, err = cache.NewRedis(cfg.Cache.RedisURI, 60*time.Minute)
+		if err != nil {
+			log.Fatal(err)
+		}
+	default:
+		sharedCache = cache.NewMemory(60*time.Minute, 90*time.Minute)
+	}
+
+	var msgBus bus.Bus
+	switch {
+	case cfg.Shards.Distributed():
+		msgBus, err = bus.NewRedis(cfg.Shards.RedisURI)
+		if err != nil {
+			log.Fatal(err)
+		}
+	default:
+		msgBus = bus.NewMemory()
+	}
+
+	store, err := initStore(ctx, cfg, sharedCache)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -89,18 +123,35 @@ func main() {
 		repostDetector = repost.NewMemory()
 	}
 
-	b, err := bot.New(cfg, store, log, repostDetector)
+	b, err := bot.New(cfg, store, log, repostDetector, sharedCache, msgBus)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	b.AddProvider(twitter.New())
-	b.AddProvider(deviant.New())
-	b.AddProvider(bluesky.New())
+	b.AddProvider("twitter", twitter.New())
+	b.AddProvider("deviant", deviant.New())
+	b.AddProvider("bluesky", bluesky.New())
+	b.AddProvider("fanbox", fanbox.New())
+	b.AddProvider("artstation", artstation.New())
+	b.AddProvider("tumblr", tumblr.New(cfg.Tumblr.Key()))
+	b.AddProvider("nhentai", nhentai.New(b.NHentai, cfg.NHentai.Host()))
+
+	var signer *imageproxy.Signer
+	if cfg.Pixiv.Proxy.Enabled() {
+		signer = imageproxy.NewSigner(cfg.Pixiv.Proxy.Secret)
+	}
+
+	b.AddProvider("pixiv", pixiv.New(cfg.Pixiv.ProxyHost, signer))
 
-	if err := pixiv.LoadAuth(cfg.Pixiv.AuthToken, cfg.Pixiv.RefreshToken); err == nil {
+	// Mastodon matches URLs of any host, so it goes last to let other providers claim their URLs first.
+	b.AddProvider("mastodon", mastodon.New())
+
+	// Pixiv stays disabled until an owner re-authenticates it with bt!providers.
+	if err := b.PixivTokens.Restore(cfg.Pixiv.AuthToken, cfg.Pixiv.RefreshToken); err != nil {
+		log.With("error", err).Warn("failed to log into Pixiv")
+		b.Providers.Disable("pixiv", err)
+	} else {
 		log.Info("Successfully logged into Pixiv.")
-		b.AddProvider(pixiv.New(cfg.Pixiv.ProxyHost))
 	}
 
 	b.AddRouter(&gumi.Router{
@@ -119,7 +170,40 @@ func main() {
 	handlers.RegisterHandlers(b)
 	commands.RegisterCommands(b)
 
+	if cfg.API != nil && cfg.API.Address != "" {
+		srv := api.New(store, api.DiscordAuthenticator(), b.ShardManager, cfg.API, log)
+		if signer != nil {
+			proxy, err := newPixivProxy(cfg.Pixiv.Proxy, signer, log)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			srv.Handle("GET /pixiv/", http.StripPrefix("/pixiv", proxy))
+		}
+
+		go func() {
+			if err := srv.ListenAndServe(ctx); err != nil {
+				log.With("error", err).Error("api server stopped")
+			}
+		}()
+	} else if signer != nil {
+		log.Warn("pixiv proxy is enabled, but the api server isn't. Pixiv images won't load.")
+	}
+
 	if err := b.Start(ctx); err != nil {
 		log.Fatal(err)
 	}
 }
+
+func newPixivProxy(cfg *config.PixivProxy, signer *imageproxy.Signer, log *zap.SugaredLogger) (*imageproxy.Proxy, error) {
+	var cache *imageproxy.Cache
+	if cfg.CacheDir != "" {
+		var err error
+		cache, err = imageproxy.NewCache(cfg.CacheDir, cfg.CacheSize())
+		if err != nil {
+			return nil, fmt.Errorf("failed to create pixiv proxy cache: %w", err)
+		}
+	}
+
+	return imageproxy.New("https://i.pximg.net", "https://www.pixiv.net/", signer, cache, log), nil
+}
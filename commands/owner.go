@@ -1,12 +1,18 @@
 package commands
 
 import (
+	"errors"
+	"fmt"
 	"strings"
 
+	"github.com/VTGare/boe-tea-go/artworks"
+	"github.com/VTGare/boe-tea-go/artworks/pixiv"
 	"github.com/VTGare/boe-tea-go/bot"
 	"github.com/VTGare/boe-tea-go/internal/dgoutils"
+	"github.com/VTGare/boe-tea-go/messages"
 	"github.com/VTGare/embeds"
 	"github.com/VTGare/gumi"
+	"github.com/bwmarrin/discordgo"
 )
 
 func ownerGroup(b *bot.Bot) {
@@ -21,6 +27,16 @@ func ownerGroup(b *bot.Bot) {
 		AuthorOnly:  true,
 		Exec:        reply(b),
 	})
+
+	b.Router.RegisterCmd(&gumi.Command{
+		Name:        "providers",
+		Group:       group,
+		Description: "Owner's command to view health of artwork providers, disable or re-enable them, or re-authenticate Pixiv",
+		Usage:       "bt!providers [enable|disable <provider>] [reauth pixiv [refresh token]]",
+		Example:     "bt!providers disable twitter",
+		AuthorOnly:  true,
+		Exec:        providers(b),
+	})
 }
 
 func reply(b *bot.Bot) func(*gumi.Ctx) error {
@@ -31,7 +47,11 @@ func reply(b *bot.Bot) func(*gumi.Ctx) error {
 
 		userID := dgoutils.Trimmer(gctx, 0)
 
-		s := b.ShardManager.SessionForDM()
+		s, err := b.ShardManager.SessionForDM()
+		if err != nil {
+			return err
+		}
+
 		ch, err := s.UserChannelCreate(userID)
 		if err != nil {
 			return err
@@ -60,3 +80,86 @@ func reply(b *bot.Bot) func(*gumi.Ctx) error {
 		return gctx.ReplyEmbed(eb.SuccessTemplate("Reply has been sent.").Finalize())
 	}
 }
+
+func providers(b *bot.Bot) func(*gumi.Ctx) error {
+	return func(gctx *gumi.Ctx) error {
+		if gctx.Args.Len() == 0 {
+			return gctx.ReplyEmbed(providersEmbed(b.Providers.Status()))
+		}
+
+		if err := dgoutils.ValidateArgs(gctx, 2); err != nil {
+			return err
+		}
+
+		var (
+			action  = strings.ToLower(gctx.Args.Get(0).Raw)
+			name    = strings.ToLower(gctx.Args.Get(1).Raw)
+			success string
+			err     error
+		)
+
+		switch action {
+		case "enable":
+			success = fmt.Sprintf("Provider `%v` has been enabled.", name)
+			err = b.Providers.Enable(name)
+		case "disable":
+			success = fmt.Sprintf("Provider `%v` has been disabled.", name)
+			err = b.Providers.Disable(name, nil)
+		case "reauth":
+			if name != "pixiv" {
+				return messages.ErrIncorrectCmd(gctx.Command)
+			}
+
+			// Without a new refresh token, log in again with the current or the configured one.
+			tokens := b.PixivTokens.Tokens()
+			if arg := gctx.Args.Get(2).Raw; arg != "" {
+				tokens = pixiv.Tokens{RefreshToken: arg}
+
+				// Don't leave the token in the channel.
+				gctx.Session.ChannelMessageDelete(gctx.Event.ChannelID, gctx.Event.ID)
+			} else if tokens.RefreshToken == "" {
+				tokens = pixiv.Tokens{AuthToken: b.Config.Pixiv.AuthToken, RefreshToken: b.Config.Pixiv.RefreshToken}
+			}
+
+			if err := b.PixivTokens.Login(tokens.AuthToken, tokens.RefreshToken); err != nil {
+				b.Providers.Disable(name, err)
+				return fmt.Errorf("failed to log into pixiv: %w", err)
+			}
+
+			success = "Successfully logged into Pixiv."
+			err = b.Providers.Enable(name)
+		default:
+			return messages.ErrIncorrectCmd(gctx.Command)
+		}
+
+		if errors.Is(err, artworks.ErrUnknownProvider) {
+			return messages.ErrUnknownProvider(name, b.Providers.Names())
+		}
+
+		eb := embeds.NewBuilder()
+		return gctx.ReplyEmbed(eb.SuccessTemplate(success).Finalize())
+	}
+}
+
+func providersEmbed(statuses []artworks.ProviderStatus) *discordgo.MessageEmbed {
+	eb := embeds.NewBuilder()
+	eb.Title("Artwork providers")
+
+	for _, status := range statuses {
+		lines := []string{fmt.Sprintf("**Status:** %v", status.Status)}
+		if !status.LastSuccess.IsZero() {
+			lines = append(lines, "**Last success:** "+messages.RelativeTimestamp(status.LastSuccess))
+		}
+
+		if status.LastError != nil {
+			lines = append(lines, fmt.Sprintf(
+				"**Last error:** %v `%v`",
+				messages.RelativeTimestamp(status.LastErrorAt), status.LastError,
+			))
+		}
+
+		eb.AddField(status.Name, strings.Join(lines, "\n"))
+	}
+
+	return eb.Finalize()
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -18,7 +19,6 @@ import (
 	"github.com/VTGare/gumi"
 	"github.com/bwmarrin/discordgo"
 	"github.com/julien040/go-ternary"
-	"go.mongodb.org/mongo-driver/mongo"
 )
 
 func settingsGroup(b *bot.Bot) {
@@ -78,6 +78,25 @@ func settingsGroup(b *bot.Bot) {
 		RateLimiter: gumi.NewRateLimiter(5 * time.Second),
 		Exec:        removeChannel(b),
 	})
+
+	b.Router.RegisterCmd(&gumi.Command{
+		Name:        "servergroups",
+		Group:       group,
+		Aliases:     []string{"sg", "servergroup"},
+		Description: "Lists or edits server crosspost groups. Artworks posted in a parent channel are crossposted to its children for everyone.",
+		Usage:       "bt!servergroups [new/delete/push/remove] <group name> [channels]",
+		Example:     "bt!servergroups push archive #art-archive",
+		Flags: map[string]string{
+			"new":    "`<group name> <parent channel>`. Creates a new group.",
+			"delete": "`<group name>`. Deletes a group.",
+			"push":   "`<group name> [channels]`. Adds channels to a group. Channels of other servers require Manage Server permission there.",
+			"remove": "`<group name> [channels]`. Removes channels from a group.",
+		},
+		GuildOnly:   true,
+		Permissions: discordgo.PermissionAdministrator | discordgo.PermissionManageServer,
+		RateLimiter: gumi.NewRateLimiter(5 * time.Second),
+		Exec:        serverGroups(b),
+	})
 }
 
 func set(b *bot.Bot) func(*gumi.Ctx) error {
@@ -94,7 +113,7 @@ func set(b *bot.Bot) func(*gumi.Ctx) error {
 			guild, err := b.Store.Guild(ctx, gd.ID)
 			if err != nil {
 				switch {
-				case errors.Is(err, mongo.ErrNoDocuments):
+				case errors.Is(err, store.ErrNotFound):
 					return messages.ErrGuildNotFound(err, gctx.Event.GuildID)
 				default:
 					return err
@@ -109,9 +128,11 @@ func set(b *bot.Bot) func(*gumi.Ctx) error {
 			eb.AddField(
 				"General",
 				fmt.Sprintf(
-					"**%v**: %v | **%v**: %v",
+					"**%v**: %v | **%v**: %v\n**%v**: %v | **%v**: %v",
 					"Prefix", guild.Prefix,
 					"NSFW", messages.FormatBool(guild.NSFW),
+					"NSFW policy (nsfw.policy)", guild.NSFWPolicyIn(false),
+					"AI policy (ai.policy)", guild.AIPolicyOrDefault(),
 				),
 			)
 
@@ -128,6 +149,15 @@ func set(b *bot.Bot) func(*gumi.Ctx) error {
 				),
 			)
 
+			eb.AddField(
+				"Blacklist",
+				fmt.Sprintf(
+					"**%v**: %v | **%v**: %v",
+					"Tags (blacklist)", len(guild.Blacklist.Tags),
+					"Mode (blacklist.mode)", guild.Blacklist.ModeOrDefault(),
+				),
+			)
+
 			eb.AddField(
 				"Pixiv settings",
 				fmt.Sprintf(
@@ -162,6 +192,46 @@ func set(b *bot.Bot) func(*gumi.Ctx) error {
 				),
 			)
 
+			eb.AddField(
+				"Fanbox and Fantia settings",
+				fmt.Sprintf(
+					"**%v**: %v",
+					"Status (fanbox)", messages.FormatBool(guild.FanboxEnabled()),
+				),
+			)
+
+			eb.AddField(
+				"ArtStation settings",
+				fmt.Sprintf(
+					"**%v**: %v",
+					"Status (artstation)", messages.FormatBool(guild.ArtStationEnabled()),
+				),
+			)
+
+			eb.AddField(
+				"Mastodon settings",
+				fmt.Sprintf(
+					"**%v**: %v",
+					"Status (mastodon)", messages.FormatBool(guild.MastodonEnabled()),
+				),
+			)
+
+			eb.AddField(
+				"Tumblr settings",
+				fmt.Sprintf(
+					"**%v**: %v",
+					"Status (tumblr)", messages.FormatBool(guild.TumblrEnabled()),
+				),
+			)
+
+			eb.AddField(
+				"nhentai settings",
+				fmt.Sprintf(
+					"**%v**: %v",
+					"Status (nhentai)", messages.FormatBool(guild.NHentaiEnabled()),
+				),
+			)
+
 			channels := ternary.If(len(guild.ArtChannels) > 5,
 				[]string{"There are more than 5 art channels, use `bt!artchannels` command to see them."},
 				arrays.Map(guild.ArtChannels, func(s string) string {
@@ -260,6 +330,32 @@ func set(b *bot.Bot) func(*gumi.Ctx) error {
 
 				guild.NSFW = applySetting(guild.NSFW, enable).(bool)
 
+			case "nsfw.policy":
+				switch store.NSFWPolicy(newSetting.Raw) {
+				case store.NSFWPolicyAllow, store.NSFWPolicySpoiler, store.NSFWPolicyBlock:
+				default:
+					return messages.ErrUnknownNSFWPolicy(newSetting.Raw)
+				}
+
+				guild.NSFWPolicy = store.NSFWPolicy(applySetting(guild.NSFWPolicyIn(false), newSetting.Raw).(string))
+
+			case "ai.policy":
+				switch store.AIPolicy(newSetting.Raw) {
+				case store.AIPolicyLabel, store.AIPolicyHide, store.AIPolicyDelete:
+				default:
+					return messages.ErrUnknownAIPolicy(newSetting.Raw)
+				}
+
+				guild.AIPolicy = store.AIPolicy(applySetting(guild.AIPolicyOrDefault(), newSetting.Raw).(string))
+
+			case "blacklist.mode":
+				mode, err := parseBlacklistMode(newSetting.Raw)
+				if err != nil {
+					return err
+				}
+
+				guild.Blacklist.Mode = applySetting(guild.Blacklist.ModeOrDefault(), mode).(store.BlacklistMode)
+
 			case "crosspost":
 				enable, err := parseBool(newSetting.Raw)
 				if err != nil {
@@ -292,6 +388,51 @@ func set(b *bot.Bot) func(*gumi.Ctx) error {
 
 				guild.Bluesky = applySetting(guild.Bluesky, enable).(bool)
 
+			case "fanbox":
+				enable, err := parseBool(newSetting.Raw)
+				if err != nil {
+					return err
+				}
+
+				fanbox := applySetting(guild.FanboxEnabled(), enable).(bool)
+				guild.Fanbox = &fanbox
+
+			case "artstation":
+				enable, err := parseBool(newSetting.Raw)
+				if err != nil {
+					return err
+				}
+
+				artstation := applySetting(guild.ArtStationEnabled(), enable).(bool)
+				guild.ArtStation = &artstation
+
+			case "mastodon":
+				enable, err := parseBool(newSetting.Raw)
+				if err != nil {
+					return err
+				}
+
+				mastodon := applySetting(guild.MastodonEnabled(), enable).(bool)
+				guild.Mastodon = &mastodon
+
+			case "tumblr":
+				enable, err := parseBool(newSetting.Raw)
+				if err != nil {
+					return err
+				}
+
+				tumblr := applySetting(guild.TumblrEnabled(), enable).(bool)
+				guild.Tumblr = &tumblr
+
+			case "nhentai":
+				enable, err := parseBool(newSetting.Raw)
+				if err != nil {
+					return err
+				}
+
+				nhentai := applySetting(guild.NHentaiEnabled(), enable).(bool)
+				guild.NHentai = &nhentai
+
 			case "twitter":
 				enable, err := parseBool(newSetting.Raw)
 				if err != nil {
@@ -353,6 +494,8 @@ func set(b *bot.Bot) func(*gumi.Ctx) error {
 		switch {
 		case gctx.Args.Len() == 0:
 			return showSettings()
+		case gctx.Args.Get(0).Raw == "blacklist":
+			return guildBlacklist(b, gctx)
 		case gctx.Args.Len() >= 2:
 			return changeSetting()
 		default:
@@ -361,6 +504,91 @@ func set(b *bot.Bot) func(*gumi.Ctx) error {
 	}
 }
 
+func guildBlacklist(b *bot.Bot, gctx *gumi.Ctx) error {
+	ctx, cancel := context.WithTimeout(b.Context, 10*time.Second)
+	defer cancel()
+
+	guild, err := b.Store.Guild(ctx, gctx.Event.GuildID)
+	if err != nil {
+		return err
+	}
+
+	if action := gctx.Args.Get(1).Raw; action == "" || action == "list" {
+		return gctx.ReplyEmbed(blacklistEmbed(guild.Blacklist, "bt!set"))
+	}
+
+	perms, err := dgoutils.MemberHasPermission(
+		gctx.Session,
+		gctx.Event.GuildID,
+		gctx.Event.Author.ID,
+		discordgo.PermissionAdministrator|discordgo.PermissionManageServer,
+	)
+	if err != nil {
+		return err
+	}
+
+	if !perms {
+		return gctx.Router.OnNoPermissionsCallback(gctx)
+	}
+
+	success, err := editBlacklist(gctx, &guild.Blacklist)
+	if err != nil {
+		return err
+	}
+
+	if _, err := b.Store.UpdateGuild(ctx, guild); err != nil {
+		return err
+	}
+
+	eb := embeds.NewBuilder()
+	eb.SuccessTemplate(success)
+	return gctx.ReplyEmbed(eb.Finalize())
+}
+
+// editBlacklist applies `blacklist <add/remove> <tags...>` arguments to the blacklist and returns a success message.
+func editBlacklist(gctx *gumi.Ctx, blacklist *store.Blacklist) (string, error) {
+	if gctx.Args.Len() < 3 {
+		return "", messages.ErrIncorrectCmd(gctx.Command)
+	}
+
+	tags := make([]string, 0, gctx.Args.Len()-2)
+	for _, arg := range gctx.Args.Arguments[2:] {
+		tags = append(tags, arg.Raw)
+	}
+
+	switch gctx.Args.Get(1).Raw {
+	case "add":
+		return messages.BlacklistAddSuccess(blacklist.Add(tags...)), nil
+	case "remove":
+		return messages.BlacklistRemoveSuccess(blacklist.Remove(tags...)), nil
+	default:
+		return "", messages.ErrIncorrectCmd(gctx.Command)
+	}
+}
+
+func blacklistEmbed(blacklist store.Blacklist, command string) *discordgo.MessageEmbed {
+	eb := embeds.NewBuilder()
+	eb.Title(messages.BlacklistTitle())
+	eb.Footer(fmt.Sprintf("Mode: %v", blacklist.ModeOrDefault()), "")
+
+	if len(blacklist.Tags) == 0 {
+		eb.Description(messages.BlacklistEmpty(command))
+	} else {
+		eb.Description("`" + strings.Join(blacklist.Tags, "`, `") + "`")
+	}
+
+	return eb.Finalize()
+}
+
+func parseBlacklistMode(mode string) (store.BlacklistMode, error) {
+	switch store.BlacklistMode(mode) {
+	case store.BlacklistSuppress, store.BlacklistSpoiler:
+		return store.BlacklistMode(mode), nil
+	default:
+		return "", messages.ErrUnknownBlacklistMode(mode)
+	}
+}
+
 func artChannels(b *bot.Bot) func(*gumi.Ctx) error {
 	return func(gctx *gumi.Ctx) error {
 		ctx, cancel := context.WithTimeout(b.Context, 10*time.Second)
@@ -697,7 +925,7 @@ func removeChannel(b *bot.Bot) func(*gumi.Ctx) error {
 			channels,
 		)
 		if err != nil {
-			if errors.Is(err, mongo.ErrNoDocuments) {
+			if errors.Is(err, store.ErrNotFound) {
 				return messages.RemoveArtChannelFail(channels)
 			}
 
@@ -722,3 +950,173 @@ func parseBool(s string) (bool, error) {
 
 	return false, messages.ErrParseBool(s)
 }
+
+// serverGroups lists or edits crosspost groups managed by server admins.
+func serverGroups(b *bot.Bot) func(*gumi.Ctx) error {
+	return func(gctx *gumi.Ctx) error {
+		ctx, cancel := context.WithTimeout(b.Context, 15*time.Second)
+		defer cancel()
+
+		guild, err := b.Store.Guild(ctx, gctx.Event.GuildID)
+		if err != nil {
+			return messages.ErrGuildNotFound(err, gctx.Event.GuildID)
+		}
+
+		if gctx.Args.Len() == 0 {
+			eb := embeds.NewBuilder()
+			eb.Title(messages.ServerGroupsTitle())
+			if len(guild.Groups) == 0 {
+				eb.Description(messages.ServerGroupsEmpty())
+			}
+
+			for _, group := range guild.Groups {
+				children := "-"
+				if len(group.Children) != 0 {
+					children = messages.ListChannels(group.Children)
+				}
+
+				eb.AddField(group.Name, fmt.Sprintf(
+					"**Parent:** <#%v> | `%v`\n**Children:** %v",
+					group.Parent, group.Parent, children,
+				))
+			}
+
+			return gctx.ReplyEmbed(eb.Finalize())
+		}
+
+		if err := dgoutils.ValidateArgs(gctx, 2); err != nil {
+			return err
+		}
+
+		var (
+			action = gctx.Args.Get(0).Raw
+			name   = gctx.Args.Get(1).Raw
+		)
+
+		switch action {
+		case "new":
+			if err := dgoutils.ValidateArgs(gctx, 3); err != nil {
+				return err
+			}
+
+			ch, err := gctx.Session.Channel(dgoutils.Trimmer(gctx, 2))
+			if err != nil {
+				return messages.ErrChannelNotFound(err, dgoutils.Trimmer(gctx, 2))
+			}
+
+			if ch.GuildID != guild.ID {
+				return messages.ErrForeignChannel(ch.ID)
+			}
+
+			if ch.Type != discordgo.ChannelTypeGuildText {
+				return messages.ErrIncorrectCmd(gctx.Command)
+			}
+
+			_, err = b.Store.CreateGuildGroup(ctx, guild.ID, &store.Group{
+				Name:     name,
+				Parent:   ch.ID,
+				Children: []string{},
+			})
+			if err := handleStoreError(err, messages.ErrServerGroupExists(name, ch.ID)); err != nil {
+				return err
+			}
+
+			return successMessage(gctx, messages.ServerGroupCreateSuccess(name, ch.ID))
+		case "delete":
+			_, err := b.Store.DeleteGuildGroup(ctx, guild.ID, name)
+			if err := handleStoreError(err, messages.ErrServerGroupNotFound(name)); err != nil {
+				return err
+			}
+
+			return successMessage(gctx, messages.ServerGroupDeleteSuccess(name))
+		case "push":
+			if err := dgoutils.ValidateArgs(gctx, 3); err != nil {
+				return err
+			}
+
+			group, ok := guild.FindGroupByName(name)
+			if !ok {
+				return messages.ErrServerGroupNotFound(name)
+			}
+
+			var (
+				inserted = make([]string, 0, gctx.Args.Len()-2)
+				skipped  = make([]*messages.ChannelCheck, 0)
+			)
+
+			for _, arg := range gctx.Args.Arguments[2:] {
+				channelID := dgoutils.TrimmerRaw(arg.Raw)
+				ch, err := gctx.Session.Channel(channelID)
+				if err != nil {
+					return messages.ErrChannelNotFound(err, channelID)
+				}
+
+				if ch.ID == group.Parent || slices.Contains(group.Children, ch.ID) {
+					continue
+				}
+
+				// Admins of one server can't crosspost to servers they don't manage.
+				if ch.GuildID != guild.ID {
+					perms, err := dgoutils.ChannelPermissions(gctx.Session, ch, gctx.Event.Author.ID)
+					if err != nil || perms&(discordgo.PermissionAdministrator|discordgo.PermissionManageServer) == 0 {
+						return messages.ErrNotServerManager(ch.ID)
+					}
+				}
+
+				if failed := failedChecks(checkChannels(b, gctx, ch.ID)); len(failed) != 0 {
+					skipped = append(skipped, failed...)
+					continue
+				}
+
+				if _, err := b.Store.AddGuildGroupChannel(ctx, guild.ID, name, ch.ID); err != nil {
+					return handleStoreError(err, messages.ErrServerGroupNotFound(name))
+				}
+
+				inserted = append(inserted, ch.ID)
+			}
+
+			if len(inserted) == 0 {
+				if len(skipped) != 0 {
+					return messages.ErrUserChannelChecksFail(name, skipped)
+				}
+
+				return messages.ErrUserPushFail(name)
+			}
+
+			return successMessage(gctx,
+				messages.UserPushSuccess(name, inserted)+messages.UserSkippedChannels(skipped),
+			)
+		case "remove":
+			if err := dgoutils.ValidateArgs(gctx, 3); err != nil {
+				return err
+			}
+
+			group, ok := guild.FindGroupByName(name)
+			if !ok {
+				return messages.ErrServerGroupNotFound(name)
+			}
+
+			removed := make([]string, 0, gctx.Args.Len()-2)
+			for _, arg := range gctx.Args.Arguments[2:] {
+				channelID := dgoutils.TrimmerRaw(arg.Raw)
+				if !slices.Contains(group.Children, channelID) {
+					continue
+				}
+
+				if _, err := b.Store.DeleteGuildGroupChannel(ctx, guild.ID, name, channelID); err != nil {
+					return handleStoreError(err, messages.ErrServerGroupNotFound(name))
+				}
+
+				removed = append(removed, channelID)
+			}
+
+			if len(removed) == 0 {
+				return messages.ErrUserRemoveFail(name)
+			}
+
+			return successMessage(gctx, messages.UserRemoveSuccess(name, removed))
+		default:
+			return messages.ErrIncorrectCmd(gctx.Command)
+		}
+	}
+}
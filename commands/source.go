@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/url"
@@ -9,9 +10,7 @@ import (
 	"strings"
 	"time"
 
-	"golang.org/x/text/cases"
-	"golang.org/x/text/language"
-
+	nhartwork "github.com/VTGare/boe-tea-go/artworks/nhentai"
 	"github.com/VTGare/boe-tea-go/bot"
 	nh "github.com/VTGare/boe-tea-go/internal/apis/nhentai"
 	"github.com/VTGare/boe-tea-go/internal/dgoutils"
@@ -47,9 +46,9 @@ func sourceGroup(b *bot.Bot) {
 		Name:        "nhentai",
 		Group:       group,
 		Aliases:     []string{"nh"},
-		Description: "Displays more info about an nhentai doujin",
-		Usage:       "bt!nhentai <nuke code>",
-		Example:     "bt!nhentai 177013",
+		Description: "Displays more info about an nhentai doujin or reads it page by page",
+		Usage:       "bt!nhentai [read] <nuke code>",
+		Example:     "bt!nhentai read 177013",
 		NSFW:        true,
 		RateLimiter: gumi.NewRateLimiter(15 * time.Second),
 		Exec:        nhentai(b),
@@ -62,8 +61,18 @@ func nhentai(b *bot.Bot) func(*gumi.Ctx) error {
 			return err
 		}
 
-		id := gctx.Args.Get(0).Raw
-		hentai, err := b.NHentai.FindHentai(id)
+		read := strings.EqualFold(gctx.Args.Get(0).Raw, "read")
+		if read {
+			if err := dgoutils.ValidateArgs(gctx, 2); err != nil {
+				return err
+			}
+		}
+
+		id := gctx.Args.Get(ternary.If(read, 1, 0)).Raw
+		ctx, cancel := context.WithTimeout(b.Context, 30*time.Second)
+		defer cancel()
+
+		hentai, err := b.NHentai.FindHentai(ctx, id)
 		if err != nil {
 			switch {
 			case errors.Is(err, nh.ErrNotFound):
@@ -75,59 +84,18 @@ func nhentai(b *bot.Bot) func(*gumi.Ctx) error {
 			return err
 		}
 
-		eb := embeds.NewBuilder()
-
-		eb.Title(ternary.If(hentai.Titles != nil,
-			hentai.Titles.Pretty,
-			"No title",
-		))
-
-		eb.URL(hentai.URL)
-		eb.Image(hentai.Cover)
-		eb.Timestamp(hentai.UploadedAt)
-
-		tagsToString := func(tags []*nh.Tag) string {
-			ss := make([]string, 0, len(tags))
-			for _, tag := range tags {
-				ss = append(ss, tag.Name)
-			}
-
-			return strings.Join(ss, " • ")
-		}
-
-		tagsToNamedLinks := func(tags []*nh.Tag) string {
-			ss := make([]string, 0, len(tags))
-			for _, tag := range tags {
-				ss = append(ss, messages.NamedLink(tag.Name, tag.URL))
-			}
-
-			return strings.Join(ss, " • ")
-		}
-
-		eb.AddField("Pages", strconv.Itoa(hentai.Pages), true).
-			AddField("Favorites", strconv.Itoa(hentai.Favorites), true)
-
-		if artists := hentai.Artists(); len(artists) > 0 {
-			eb.AddField("Artists", tagsToNamedLinks(artists), true)
-		}
-
-		if characters := hentai.Characters(); len(characters) > 0 {
-			eb.AddField("Characters", tagsToNamedLinks(characters), true)
-		}
-
-		if padories := hentai.Parodies(); len(padories) > 0 {
-			eb.AddField("Parodies", tagsToNamedLinks(padories), true)
+		artwork := &nhartwork.Artwork{Gallery: hentai}
+		if read {
+			widget := dgoutils.NewWidget(gctx.Session, gctx.Event.Author.ID, artwork.PageEmbeds())
+			return widget.Start(gctx.Event.ChannelID)
 		}
 
-		if lang, ok := hentai.Language(); ok {
-			eb.AddField("Language", cases.Title(language.English).String(lang.String()), true)
-		}
-
-		if genres := hentai.Genres(); len(genres) > 0 {
-			eb.AddField("Tags", tagsToString(genres))
+		sends, err := artwork.MessageSends("", true)
+		if err != nil {
+			return err
 		}
 
-		return gctx.ReplyEmbed(eb.Finalize())
+		return gctx.ReplyEmbed(sends[0].Embeds[0])
 	}
 }
 
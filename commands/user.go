@@ -10,6 +10,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/VTGare/boe-tea-go/artworks"
 	"github.com/VTGare/boe-tea-go/bot"
 	"github.com/VTGare/boe-tea-go/commands/flags"
 	"github.com/VTGare/boe-tea-go/internal/arrays"
@@ -20,7 +21,6 @@ import (
 	"github.com/VTGare/gumi"
 	"github.com/bwmarrin/discordgo"
 	"github.com/julien040/go-ternary"
-	"go.mongodb.org/mongo-driver/mongo"
 )
 
 // userGroup registers user group commands.
@@ -31,9 +31,9 @@ func userGroup(b *bot.Bot) {
 		Name:        "groups",
 		Group:       group,
 		Aliases:     []string{"ls", "list"},
-		Description: "Shows all crosspost groups.",
-		Usage:       "bt!groups",
-		Example:     "bt!groups",
+		Description: "Shows all crosspost groups. Use `check` to check if Boe Tea can still crosspost to their channels.",
+		Usage:       "bt!groups [check]",
+		Example:     "bt!groups check",
 		RateLimiter: gumi.NewRateLimiter(10 * time.Second),
 		Exec:        groups(b),
 	})
@@ -121,6 +121,26 @@ func userGroup(b *bot.Bot) {
 		Exec:        copyGroup(b),
 	})
 
+	b.Router.RegisterCmd(&gumi.Command{
+		Name:        "filter",
+		Group:       group,
+		Aliases:     []string{"groupfilter"},
+		Description: "Shows or edits rules deciding which artworks are crossposted by a group.",
+		Usage:       "bt!filter <group name> [rule] [values]",
+		Example:     "bt!filter lewds exclude-tags guro",
+		Flags: map[string]string{
+			"providers":         "**Options:** provider names. Only crossposts artworks from listed providers, all providers if empty.",
+			"exclude-providers": "**Options:** provider names. Never crossposts artworks from listed providers.",
+			"content":           "**Options:** `[all, nsfw, sfw]`. Only crossposts NSFW or SFW artworks.",
+			"tags":              "**Options:** tags. Only crossposts artworks with any of the tags, all artworks if empty.",
+			"exclude-tags":      "**Options:** tags. Never crossposts artworks with any of the tags.",
+			"exclude-ai":        "**Options:** `[on, off]`. Never crossposts AI-generated artworks.",
+			"reset":             "Removes all rules.",
+		},
+		RateLimiter: gumi.NewRateLimiter(5 * time.Second),
+		Exec:        groupFilter(b),
+	})
+
 	b.Router.RegisterCmd(&gumi.Command{
 		Name:        "bookmarks",
 		Group:       group,
@@ -157,8 +177,10 @@ func userGroup(b *bot.Bot) {
 		Usage:       "bt!userset <setting name> <new setting>",
 		Example:     "bt!userset dm false",
 		Flags: map[string]string{
-			"dm":        "**Options:** `[on, off]`. Switches most direct messages from the bot.",
-			"crosspost": "**Options:** `[on, off]`. Switches crossposting in general.",
+			"dm":             "**Options:** `[on, off]`. Switches most direct messages from the bot.",
+			"crosspost":      "**Options:** `[on, off]`. Switches crossposting in general.",
+			"blacklist":      "**Usage:** `bt!userset blacklist <add/remove/list> [tags]`. Tags blacklisted from your crossposts.",
+			"blacklist.mode": "**Options:** `[suppress, spoiler]`. Doesn't crosspost blacklisted artworks or hides them behind spoilers.",
 		},
 		RateLimiter: gumi.NewRateLimiter(10 * time.Second),
 		Exec:        userSet(b),
@@ -183,6 +205,15 @@ func groups(b *bot.Bot) func(*gumi.Ctx) error {
 			return err
 		}
 
+		if gctx.Args.Len() != 0 {
+			switch gctx.Args.Get(0).Raw {
+			case "check":
+				return checkGroups(b, gctx, user)
+			default:
+				return messages.ErrIncorrectCmd(gctx.Command)
+			}
+		}
+
 		locale := messages.UserGroupsEmbed(gctx.Event.Author.Username)
 		eb := embeds.NewBuilder()
 
@@ -231,6 +262,27 @@ func groups(b *bot.Bot) func(*gumi.Ctx) error {
 	}
 }
 
+// checkGroups checks every channel of user's crosspost groups.
+func checkGroups(b *bot.Bot, gctx *gumi.Ctx, user *store.User) error {
+	if len(user.Groups) == 0 {
+		return messages.ErrUserChannelChecksNoGroups()
+	}
+
+	eb := embeds.NewBuilder()
+	eb.Title(messages.UserCheckGroupsTitle())
+
+	for _, group := range user.Groups {
+		checks := checkChannels(b, gctx, group.Children...)
+		if !group.IsPair {
+			checks = append(checkParents(b, gctx, group.Parent), checks...)
+		}
+
+		eb.AddField(group.Name, messages.ChannelChecks(checks))
+	}
+
+	return gctx.ReplyEmbed(eb.Finalize())
+}
+
 // newGroup creates a new crosspost group.
 func newGroup(b *bot.Bot) func(*gumi.Ctx) error {
 	return func(gctx *gumi.Ctx) error {
@@ -255,6 +307,10 @@ func newGroup(b *bot.Bot) func(*gumi.Ctx) error {
 			return messages.ErrNewGroup(name, parent)
 		}
 
+		if failed := failedChecks(checkParents(b, gctx, parent)); len(failed) != 0 {
+			return messages.ErrUserChannelChecksFail(name, failed)
+		}
+
 		ctx, cancel := context.WithTimeout(b.Context, 5*time.Second)
 		defer cancel()
 
@@ -313,6 +369,10 @@ func newPair(b *bot.Bot) func(*gumi.Ctx) error {
 			}
 		}
 
+		if failed := failedChecks(checkChannels(b, gctx, children...)); len(failed) != 0 {
+			return messages.ErrUserChannelChecksFail(name, failed)
+		}
+
 		sort.Strings(children)
 
 		ctx, cancel := context.WithTimeout(b.Context, 5*time.Second)
@@ -379,7 +439,11 @@ func push(b *bot.Bot) func(*gumi.Ctx) error {
 		ctx, cancel := context.WithTimeout(b.Context, 15*time.Second)
 		defer cancel()
 
-		inserted := make([]string, 0, gctx.Args.Len())
+		var (
+			inserted = make([]string, 0, gctx.Args.Len())
+			skipped  = make([]*messages.ChannelCheck, 0)
+		)
+
 		for _, arg := range gctx.Args.Arguments {
 			channelID := dgoutils.TrimmerRaw(arg.Raw)
 			ch, err := gctx.Session.Channel(channelID)
@@ -404,6 +468,11 @@ func push(b *bot.Bot) func(*gumi.Ctx) error {
 				continue
 			}
 
+			if failed := failedChecks(checkChannels(b, gctx, channelID)); len(failed) != 0 {
+				skipped = append(skipped, failed...)
+				continue
+			}
+
 			_, err = b.Store.AddCrosspostChannel(
 				ctx,
 				user.ID,
@@ -419,10 +488,16 @@ func push(b *bot.Bot) func(*gumi.Ctx) error {
 		}
 
 		if len(inserted) == 0 {
+			if len(skipped) != 0 {
+				return messages.ErrUserChannelChecksFail(name, skipped)
+			}
+
 			return messages.ErrUserPushFail(name)
 		}
 
-		return successMessage(gctx, messages.UserPushSuccess(name, inserted))
+		return successMessage(gctx,
+			messages.UserPushSuccess(name, inserted)+messages.UserSkippedChannels(skipped),
+		)
 	}
 }
 
@@ -584,11 +659,23 @@ func copyGroup(b *bot.Bot) func(*gumi.Ctx) error {
 			return messages.ErrUserChannelAlreadyParent(parent)
 		}
 
+		if failed := failedChecks(checkParents(b, gctx, parent)); len(failed) != 0 {
+			return messages.ErrUserChannelChecksFail(dest, failed)
+		}
+
+		children := arrays.Filter(group.Children, func(s string) bool {
+			return s != parent
+		})
+
+		// Children that can't be crossposted to anymore aren't copied.
+		skipped := failedChecks(checkChannels(b, gctx, children...))
 		newGroup := &store.Group{
 			Name:   dest,
 			Parent: parent,
-			Children: arrays.Filter(group.Children, func(s string) bool {
-				return s != parent
+			Children: arrays.Filter(children, func(s string) bool {
+				return !slices.ContainsFunc(skipped, func(check *messages.ChannelCheck) bool {
+					return check.ChannelID == s
+				})
 			}),
 		}
 
@@ -601,11 +688,124 @@ func copyGroup(b *bot.Bot) func(*gumi.Ctx) error {
 		}
 
 		return successMessage(gctx,
-			messages.UserCopyGroupSuccess(src, dest, newGroup.Children),
+			messages.UserCopyGroupSuccess(src, dest, newGroup.Children)+messages.UserSkippedChannels(skipped),
+		)
+	}
+}
+
+// groupFilter shows or edits the crosspost filter of a group.
+func groupFilter(b *bot.Bot) func(*gumi.Ctx) error {
+	return func(gctx *gumi.Ctx) error {
+		user, err := initCommand(b, gctx, 1)
+		if err != nil {
+			return err
+		}
+
+		name := gctx.Args.Get(0).Raw
+		group, ok := user.FindGroupByName(name)
+		if !ok {
+			return messages.ErrGroupExistFail(name)
+		}
+
+		if gctx.Args.Len() == 1 {
+			return gctx.ReplyEmbed(filterEmbed(name, group.Filter))
+		}
+
+		var (
+			filter = group.Filter
+			rule   = gctx.Args.Get(1).Raw
+			values = make([]string, 0, gctx.Args.Len()-2)
 		)
+
+		for _, arg := range gctx.Args.Arguments[2:] {
+			values = append(values, arg.Raw)
+		}
+
+		switch rule {
+		case "providers", "exclude-providers":
+			providers := arrays.Map(values, strings.ToLower)
+			for _, provider := range providers {
+				if !slices.Contains(artworks.Providers(), provider) {
+					return messages.ErrUnknownProvider(provider, artworks.Providers())
+				}
+			}
+
+			if rule == "providers" {
+				filter.Providers = providers
+			} else {
+				filter.ExcludeProviders = providers
+			}
+		case "content":
+			if len(values) != 1 {
+				return messages.ErrIncorrectCmd(gctx.Command)
+			}
+
+			switch content := strings.ToLower(values[0]); content {
+			case "all":
+				filter.Content = store.GroupContentAll
+			case string(store.GroupContentNSFW), string(store.GroupContentSFW):
+				filter.Content = store.GroupContent(content)
+			default:
+				return messages.ErrUnknownGroupContent(content)
+			}
+		case "tags":
+			filter.Tags = values
+		case "exclude-tags":
+			filter.ExcludeTags = values
+		case "exclude-ai":
+			if len(values) != 1 {
+				return messages.ErrIncorrectCmd(gctx.Command)
+			}
+
+			filter.ExcludeAI, err = parseBool(values[0])
+			if err != nil {
+				return err
+			}
+		case "reset":
+			filter = store.GroupFilter{}
+		default:
+			return messages.ErrIncorrectCmd(gctx.Command)
+		}
+
+		ctx, cancel := context.WithTimeout(b.Context, 5*time.Second)
+		defer cancel()
+
+		_, err = b.Store.EditCrosspostFilter(ctx, user.ID, name, filter)
+		if err := handleStoreError(err, messages.ErrGroupExistFail(name)); err != nil {
+			return err
+		}
+
+		return gctx.ReplyEmbed(filterEmbed(name, filter))
 	}
 }
 
+func filterEmbed(name string, filter store.GroupFilter) *discordgo.MessageEmbed {
+	locale := messages.UserFilterEmbed(name)
+	list := func(values []string, empty string) string {
+		if len(values) == 0 {
+			return empty
+		}
+
+		return "`" + strings.Join(values, "`, `") + "`"
+	}
+
+	content := string(filter.Content)
+	if filter.Content == store.GroupContentAll {
+		content = locale.Any
+	}
+
+	eb := embeds.NewBuilder()
+	eb.Title(locale.Title).Description(locale.Description)
+	eb.AddField(locale.Providers, list(filter.Providers, locale.Any), true)
+	eb.AddField(locale.ExcludeProviders, list(filter.ExcludeProviders, locale.None), true)
+	eb.AddField(locale.Content, content, true)
+	eb.AddField(locale.Tags, list(filter.Tags, locale.Any), true)
+	eb.AddField(locale.ExcludeTags, list(filter.ExcludeTags, locale.None), true)
+	eb.AddField(locale.ExcludeAI, messages.FormatBool(filter.ExcludeAI), true)
+
+	return eb.Finalize()
+}
+
 func bookmarks(b *bot.Bot) func(*gumi.Ctx) error {
 	return func(gctx *gumi.Ctx) error {
 		ctx, cancel := context.WithTimeout(b.Context, 5*time.Second)
@@ -620,15 +820,22 @@ func bookmarks(b *bot.Bot) func(*gumi.Ctx) error {
 			filter       = store.ArtworkFilter{}
 		)
 
-		ch, err := gctx.Session.Channel(gctx.Event.ChannelID)
+		nsfw, err := dgoutils.IsNSFWChannel(gctx.Session, gctx.Event.ChannelID)
 		if err != nil {
 			return err
 		}
 
-		if ch.NSFW || ch.Type == discordgo.ChannelTypeDM {
+		if nsfw {
 			mode = store.BookmarkFilterAll
 		}
 
+		guild, err := b.Store.Guild(ctx, gctx.Event.GuildID)
+		if err != nil {
+			return err
+		}
+
+		policy := guild.NSFWPolicyIn(nsfw)
+
 		flagsMap, err := flags.FromArgs(args, flags.FlagTypeOrder, flags.FlagTypeMode)
 		if err != nil {
 			return err
@@ -643,6 +850,11 @@ func bookmarks(b *bot.Bot) func(*gumi.Ctx) error {
 			}
 		}
 
+		// Servers blocking NSFW artworks in SFW channels don't let users list them with flags either.
+		if policy == store.NSFWPolicyBlock {
+			mode = store.BookmarkFilterSafe
+		}
+
 		bookmarks, err := b.Store.ListBookmarks(ctx, gctx.Event.Author.ID, mode, order)
 		if err != nil {
 			return err
@@ -679,14 +891,7 @@ func bookmarks(b *bot.Bot) func(*gumi.Ctx) error {
 				break
 			}
 
-			page := artworkToEmbed(artwork, artwork.Images[0], ind, len(bookmarks))
-			page.Fields = append(page.Fields, &discordgo.MessageEmbedField{
-				Name:   "NSFW",
-				Value:  strconv.FormatBool(bookmark.NSFW),
-				Inline: true,
-			})
-
-			pages[ind] = page
+			pages[ind] = bookmarkEmbed(artwork, bookmark, policy, ind, len(bookmarks))
 		}
 
 		wg := dgoutils.NewWidget(gctx.Session, gctx.Event.Author.ID, pages)
@@ -716,14 +921,7 @@ func bookmarks(b *bot.Bot) func(*gumi.Ctx) error {
 				return err
 			}
 
-			page := artworkToEmbed(artwork, artwork.Images[0], i, len(bookmarks))
-			page.Fields = append(page.Fields, &discordgo.MessageEmbedField{
-				Name:   "NSFW",
-				Value:  strconv.FormatBool(bookmarks[i].NSFW),
-				Inline: true,
-			})
-
-			wg.Pages[i] = page
+			wg.Pages[i] = bookmarkEmbed(artwork, bookmarks[i], policy, i, len(bookmarks))
 			return nil
 		})
 
@@ -731,11 +929,33 @@ func bookmarks(b *bot.Bot) func(*gumi.Ctx) error {
 	}
 }
 
+// bookmarkEmbed shows a bookmarked artwork. Images of NSFW bookmarks are spoilered links if the channel's policy requires it.
+func bookmarkEmbed(artwork *store.Artwork, bookmark *store.Bookmark, policy store.NSFWPolicy, ind, length int) *discordgo.MessageEmbed {
+	page := artworkToEmbed(artwork, artwork.Images[0], ind, length)
+	page.Fields = append(page.Fields, &discordgo.MessageEmbedField{
+		Name:   "NSFW",
+		Value:  strconv.FormatBool(bookmark.NSFW),
+		Inline: true,
+	})
+
+	if bookmark.NSFW && policy == store.NSFWPolicySpoiler {
+		page.Image = nil
+		page.Fields = append(page.Fields, &discordgo.MessageEmbedField{
+			Name:  "⚠️ NSFW",
+			Value: messages.NSFWSpoiler() + "\n||" + artwork.Images[0] + "||",
+		})
+	}
+
+	return page
+}
+
 func userSet(b *bot.Bot) func(*gumi.Ctx) error {
 	return func(gctx *gumi.Ctx) error {
 		switch {
 		case gctx.Args.Len() == 0:
 			return showUserProfile(b, gctx)
+		case gctx.Args.Get(0).Raw == "blacklist":
+			return userBlacklist(b, gctx)
 		case gctx.Args.Len() >= 2:
 			return changeUserSettings(b, gctx)
 		default:
@@ -766,9 +986,10 @@ func showUserProfile(b *bot.Bot, gctx *gumi.Ctx) error {
 	eb.AddField(
 		locale.Settings,
 		fmt.Sprintf(
-			"**%v:** %v | **%v:** %v",
+			"**%v:** %v | **%v:** %v\n**%v:** %v",
 			locale.Crosspost, messages.FormatBool(user.Crosspost),
 			locale.DM, messages.FormatBool(user.DM),
+			locale.Blacklist, len(user.Blacklist.Tags),
 		),
 	)
 
@@ -829,6 +1050,16 @@ func changeUserSettings(b *bot.Bot, gctx *gumi.Ctx) error {
 		newSettingEmbed = new
 		user.Ignore = new
 
+	case "blacklist.mode":
+		mode, err := parseBlacklistMode(newSetting.Raw)
+		if err != nil {
+			return err
+		}
+
+		oldSettingEmbed = user.Blacklist.ModeOrDefault()
+		newSettingEmbed = mode
+		user.Blacklist.Mode = mode
+
 	default:
 		return messages.ErrUnknownUserSetting(settingName.Raw)
 	}
@@ -847,6 +1078,33 @@ func changeUserSettings(b *bot.Bot, gctx *gumi.Ctx) error {
 	return gctx.ReplyEmbed(eb.Finalize())
 }
 
+func userBlacklist(b *bot.Bot, gctx *gumi.Ctx) error {
+	ctx, cancel := context.WithTimeout(b.Context, 15*time.Second)
+	defer cancel()
+
+	user, err := b.Store.User(ctx, gctx.Event.Author.ID)
+	if err != nil {
+		return err
+	}
+
+	if action := gctx.Args.Get(1).Raw; action == "" || action == "list" {
+		return gctx.ReplyEmbed(blacklistEmbed(user.Blacklist, "bt!userset"))
+	}
+
+	success, err := editBlacklist(gctx, &user.Blacklist)
+	if err != nil {
+		return err
+	}
+
+	if _, err := b.Store.UpdateUser(ctx, user); err != nil {
+		return err
+	}
+
+	eb := embeds.NewBuilder()
+	eb.SuccessTemplate(success)
+	return gctx.ReplyEmbed(eb.Finalize())
+}
+
 func unfav(b *bot.Bot) func(*gumi.Ctx) error {
 	return func(gctx *gumi.Ctx) error {
 		if gctx.Args.Len() == 0 {
@@ -953,7 +1211,7 @@ func handleStoreError(err error, message ...error) error {
 	}
 
 	switch {
-	case errors.Is(err, mongo.ErrNoDocuments):
+	case errors.Is(err, store.ErrNotFound):
 		if message != nil {
 			return message[0]
 		}
@@ -970,3 +1228,97 @@ func successMessage(gctx *gumi.Ctx, message string) error {
 	eb.SuccessTemplate(message)
 	return gctx.ReplyEmbed(eb.Finalize())
 }
+
+// crosspostPermissions are required to send crossposted embeds to a channel.
+const crosspostPermissions = discordgo.PermissionViewChannel | discordgo.PermissionSendMessages | discordgo.PermissionEmbedLinks
+
+// parentPermissions are required to see artworks posted in a parent channel and embed them.
+const parentPermissions = discordgo.PermissionViewChannel | discordgo.PermissionSendMessages
+
+// checkChannels checks whether Boe Tea can crosspost to channels.
+func checkChannels(b *bot.Bot, gctx *gumi.Ctx, channelIDs ...string) []*messages.ChannelCheck {
+	return runChecks(b, gctx, checkDestination, channelIDs)
+}
+
+// checkParents checks whether Boe Tea can crosspost artworks posted in channels.
+func checkParents(b *bot.Bot, gctx *gumi.Ctx, channelIDs ...string) []*messages.ChannelCheck {
+	return runChecks(b, gctx, checkParent, channelIDs)
+}
+
+type channelCheck func(ctx context.Context, b *bot.Bot, s *discordgo.Session, channelID string) string
+
+func runChecks(b *bot.Bot, gctx *gumi.Ctx, check channelCheck, channelIDs []string) []*messages.ChannelCheck {
+	ctx, cancel := context.WithTimeout(b.Context, 15*time.Second)
+	defer cancel()
+
+	checks := make([]*messages.ChannelCheck, 0, len(channelIDs))
+	for _, channelID := range channelIDs {
+		checks = append(checks, &messages.ChannelCheck{
+			ChannelID: channelID,
+			Problem:   check(ctx, b, gctx.Session, channelID),
+		})
+	}
+
+	return checks
+}
+
+// checkDestination returns a reason Boe Tea can't crosspost to a channel or an empty string.
+func checkDestination(ctx context.Context, b *bot.Bot, s *discordgo.Session, channelID string) string {
+	guild, problem := checkArtChannel(ctx, b, s, channelID, crosspostPermissions)
+	if problem != "" {
+		return problem
+	}
+
+	// Guilds opt out of receiving crossposts only, their channels can still be parents.
+	if !guild.Crosspost {
+		return messages.ChannelCheckCrosspostDisabled()
+	}
+
+	return ""
+}
+
+// checkParent returns a reason Boe Tea can't crosspost artworks posted in a channel or an empty string.
+func checkParent(ctx context.Context, b *bot.Bot, s *discordgo.Session, channelID string) string {
+	_, problem := checkArtChannel(ctx, b, s, channelID, parentPermissions)
+	return problem
+}
+
+// checkArtChannel returns a reason a channel isn't a text art channel Boe Tea has permissions in, or an empty string.
+func checkArtChannel(ctx context.Context, b *bot.Bot, s *discordgo.Session, channelID string, required int64) (*store.Guild, string) {
+	ch, err := s.Channel(channelID)
+	if err != nil {
+		return nil, messages.ChannelCheckNotFound()
+	}
+
+	if ch.Type != discordgo.ChannelTypeGuildText {
+		return nil, messages.ChannelCheckNotText()
+	}
+
+	perms, err := dgoutils.ChannelPermissions(s, ch, s.State.User.ID)
+	if err != nil {
+		return nil, messages.ChannelCheckNotFound()
+	}
+
+	if perms&required != required {
+		return nil, messages.ChannelCheckNoPermissions()
+	}
+
+	guild, err := b.Store.Guild(ctx, ch.GuildID)
+	if err != nil {
+		// Guilds are created on join, a missing guild means Boe Tea isn't there anymore.
+		return nil, messages.ChannelCheckNotFound()
+	}
+
+	if len(guild.ArtChannels) != 0 && !slices.Contains(guild.ArtChannels, channelID) {
+		return nil, messages.ChannelCheckNotArtChannel()
+	}
+
+	return guild, ""
+}
+
+// failedChecks returns checks of channels Boe Tea can't crosspost to.
+func failedChecks(checks []*messages.ChannelCheck) []*messages.ChannelCheck {
+	return arrays.Filter(checks, func(check *messages.ChannelCheck) bool {
+		return check.Problem != ""
+	})
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/VTGare/boe-tea-go/artworks"
 	"github.com/VTGare/boe-tea-go/artworks/twitter"
 	"github.com/VTGare/boe-tea-go/bot"
+	"github.com/VTGare/boe-tea-go/internal/apis/nhentai"
 	"github.com/VTGare/boe-tea-go/internal/cache"
 	"github.com/VTGare/boe-tea-go/internal/dgoutils"
 	"github.com/VTGare/boe-tea-go/messages"
@@ -23,7 +24,6 @@ import (
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/julien040/go-ternary"
-	"go.mongodb.org/mongo-driver/mongo"
 	"mvdan.cc/xurls/v2"
 )
 
@@ -36,6 +36,7 @@ func RegisterHandlers(b *bot.Bot) {
 	b.AddHandler(OnReactionAdd(b))
 	b.AddHandler(OnReactionRemove(b))
 	b.AddHandler(OnMessageRemove(b))
+	b.AddHandler(OnMessageUpdate(b))
 }
 
 // PrefixResolver returns an array of guild's prefixes and bot mentions.
@@ -101,7 +102,7 @@ func OnGuildCreate(b *bot.Bot) func(*discordgo.Session, *discordgo.GuildCreate)
 		defer cancel()
 
 		_, err := b.Store.Guild(ctx, g.ID)
-		if errors.Is(err, mongo.ErrNoDocuments) {
+		if errors.Is(err, store.ErrNotFound) {
 			b.Log.With("guild", g.Name, "guild_id", g.ID).Info("invited to a new server")
 			_, err := b.Store.CreateGuild(ctx, g.ID)
 			if err != nil {
@@ -132,7 +133,9 @@ func OnGuildDelete(b *bot.Bot) func(*discordgo.Session, *discordgo.GuildDelete)
 // on that server due to Discord removing all reactions of banned users.
 func OnGuildBanAdd(b *bot.Bot) func(*discordgo.Session, *discordgo.GuildBanAdd) {
 	return func(s *discordgo.Session, gb *discordgo.GuildBanAdd) {
-		b.BannedUsers.Set(gb.User.ID, struct{}{})
+		if err := b.BannedUsers.Add(b.Context, gb.User.ID); err != nil {
+			b.Log.With("error", err, "user_id", gb.User.ID).Warn("failed to cache a banned user")
+		}
 	}
 }
 
@@ -167,6 +170,7 @@ func OnMessageRemove(b *bot.Bot) func(*discordgo.Session, *discordgo.MessageDele
 	return func(s *discordgo.Session, m *discordgo.MessageDelete) {
 		log := b.Log.With("channel_id", m.ChannelID, "parent_id", m.ID)
 		msg, ok := b.EmbedCache.Get(
+			b.Context,
 			m.ChannelID, m.ID,
 		)
 
@@ -175,6 +179,7 @@ func OnMessageRemove(b *bot.Bot) func(*discordgo.Session, *discordgo.MessageDele
 		}
 
 		b.EmbedCache.Remove(
+			b.Context,
 			m.ChannelID, m.ID,
 		)
 
@@ -192,6 +197,7 @@ func OnMessageRemove(b *bot.Bot) func(*discordgo.Session, *discordgo.MessageDele
 				log.With("user_id", msg.AuthorID, "message_id", child.MessageID).Info("removing a child message")
 
 				b.EmbedCache.Remove(
+					b.Context,
 					child.ChannelID, child.MessageID,
 				)
 
@@ -203,6 +209,55 @@ func OnMessageRemove(b *bot.Bot) func(*discordgo.Session, *discordgo.MessageDele
 	}
 }
 
+// OnMessageUpdate keeps embeds in sync with edited messages. Embeds of removed links are deleted
+// and added links are posted. Updates without EditedTimestamp are link previews loaded by Discord.
+func OnMessageUpdate(b *bot.Bot) func(*discordgo.Session, *discordgo.MessageUpdate) {
+	return func(s *discordgo.Session, m *discordgo.MessageUpdate) {
+		if m.Message == nil || m.Author == nil || m.Author.Bot || m.GuildID == "" || m.EditedTimestamp == nil {
+			return
+		}
+
+		// Embeds of older messages are forgotten, reposting all their links would only spam the channel.
+		if time.Since(m.Timestamp) > b.Config.Embeds.Retention() {
+			return
+		}
+
+		log := b.Log.With("guild_id", m.GuildID, "channel_id", m.ChannelID, "message_id", m.ID)
+
+		ctx, cancel := context.WithTimeout(b.Context, 30*time.Second)
+		defer cancel()
+
+		guild, err := b.Store.Guild(ctx, m.GuildID)
+		if err != nil {
+			log.With("error", err).Warn("failed to find guild")
+			return
+		}
+
+		if !(len(guild.ArtChannels) == 0 || slices.Contains(guild.ArtChannels, m.ChannelID)) {
+			return
+		}
+
+		event := &discordgo.MessageCreate{Message: m.Message}
+		for _, prefix := range PrefixResolver(b)(s, event) {
+			if strings.HasPrefix(m.Content, prefix) {
+				return
+			}
+		}
+
+		gctx := &gumi.Ctx{
+			Session: s,
+			Event:   event,
+			Router:  b.Router,
+		}
+
+		urls := xurls.Strict().FindAllString(m.Content, -1)
+		p := post.New(b, gctx, post.SkipModeNone, urls...)
+		if err := p.Update(ctx); err != nil {
+			log.With("error", err).Warn("failed to update embeds of an edited message")
+		}
+	}
+}
+
 func OnReactionAdd(b *bot.Bot) func(*discordgo.Session, *discordgo.MessageReactionAdd) {
 	return func(s *discordgo.Session, r *discordgo.MessageReactionAdd) {
 		// Do nothing for bot's own reactions
@@ -221,7 +276,7 @@ func OnReactionAdd(b *bot.Bot) func(*discordgo.Session, *discordgo.MessageReacti
 		defer cancel()
 
 		deleteEmbed := func() error {
-			msg, ok := b.EmbedCache.Get(r.ChannelID, r.MessageID)
+			msg, ok := b.EmbedCache.Get(ctx, r.ChannelID, r.MessageID)
 			if !ok {
 				return nil
 			}
@@ -231,7 +286,7 @@ func OnReactionAdd(b *bot.Bot) func(*discordgo.Session, *discordgo.MessageReacti
 			}
 
 			log.Infof("deleting a message from reaction event")
-			b.EmbedCache.Remove(r.ChannelID, r.MessageID)
+			b.EmbedCache.Remove(ctx, r.ChannelID, r.MessageID)
 
 			err := s.ChannelMessageDelete(r.ChannelID, r.MessageID)
 			if err != nil {
@@ -252,7 +307,7 @@ func OnReactionAdd(b *bot.Bot) func(*discordgo.Session, *discordgo.MessageReacti
 					"user_id", r.UserID,
 				).Infof("removing a child message")
 
-				b.EmbedCache.Remove(child.ChannelID, child.MessageID)
+				b.EmbedCache.Remove(ctx, child.ChannelID, child.MessageID)
 
 				if _, ok := childrenIDs[child.ChannelID]; !ok {
 					childrenIDs[child.ChannelID] = make([]string, 0)
@@ -319,9 +374,8 @@ func OnReactionAdd(b *bot.Bot) func(*discordgo.Session, *discordgo.MessageReacti
 			}
 
 			if len(sent) > 0 {
-				b.EmbedCache.Set(r.UserID, r.ChannelID, r.MessageID, true, sent...)
-				for _, msg := range sent {
-					b.EmbedCache.Set(r.UserID, msg.ChannelID, msg.MessageID, false)
+				if err := b.EmbedCache.Set(ctx, r.UserID, r.ChannelID, r.MessageID, sent...); err != nil {
+					log.With("error", err).Warn("failed to cache sent messages")
 				}
 			}
 
@@ -356,9 +410,9 @@ func OnReactionAdd(b *bot.Bot) func(*discordgo.Session, *discordgo.MessageReacti
 
 			var artwork artworks.Artwork
 			for _, url := range urls {
-				for _, provider := range b.ArtworkProviders {
+				for _, provider := range b.Providers.Providers() {
 					if id, ok := provider.Match(url); ok {
-						artwork, err = provider.Find(id)
+						artwork, err = provider.Find(ctx, id)
 						if err != nil {
 							return fmt.Errorf("failed to find an artwork: %w", err)
 						}
@@ -423,7 +477,11 @@ func OnReactionAdd(b *bot.Bot) func(*discordgo.Session, *discordgo.MessageReacti
 				return nil
 			}
 
-			dmSession := b.ShardManager.SessionForDM()
+			dmSession, err := b.ShardManager.SessionForDM()
+			if err != nil {
+				return err
+			}
+
 			ch, err := dmSession.UserChannelCreate(user.ID)
 			if err != nil {
 				return fmt.Errorf("failed to create private channel: %w", err)
@@ -482,7 +540,7 @@ func OnReactionRemove(b *bot.Bot) func(*discordgo.Session, *discordgo.MessageRea
 
 		// Do nothing if user was banned recently. Discord removes all reactions
 		// of banned users on the server which in turn removes all bookmarks.
-		if _, ok := b.BannedUsers.Get(r.UserID); ok {
+		if b.BannedUsers.Has(ctx, r.UserID) {
 			return
 		}
 
@@ -519,9 +577,9 @@ func OnReactionRemove(b *bot.Bot) func(*discordgo.Session, *discordgo.MessageRea
 
 		var artwork artworks.Artwork
 		for _, url := range urls {
-			for _, provider := range b.ArtworkProviders {
+			for _, provider := range b.Providers.Providers() {
 				if id, ok := provider.Match(url); ok {
-					artwork, err = provider.Find(id)
+					artwork, err = provider.Find(ctx, id)
 					if err != nil {
 						log.With("error", err, "artwork_id", id).Error("failed to find an artwork")
 						return
@@ -542,7 +600,7 @@ func OnReactionRemove(b *bot.Bot) func(*discordgo.Session, *discordgo.MessageRea
 
 		artworkDB, err := b.Store.Artwork(ctx, 0, artwork.URL())
 		if err != nil {
-			if !errors.Is(err, mongo.ErrNoDocuments) {
+			if !errors.Is(err, store.ErrArtworkNotFound) {
 				log.With("error", err).Error("failed to find an artwork")
 			}
 
@@ -570,7 +628,12 @@ func OnReactionRemove(b *bot.Bot) func(*discordgo.Session, *discordgo.MessageRea
 			return
 		}
 
-		dmSession := b.ShardManager.SessionForDM()
+		dmSession, err := b.ShardManager.SessionForDM()
+		if err != nil {
+			log.With("error", err, "user_id", user.ID).Error("failed to get a session")
+			return
+		}
+
 		ch, err := dmSession.UserChannelCreate(user.ID)
 		if err != nil {
 			log.With("error", err, "user_id", user.ID).Error("failed to create private channel")
@@ -655,13 +718,7 @@ func onArtworkError(b *bot.Bot, gctx *gumi.Ctx, err *artworks.Error) *embeds.Bui
 	eb.Title("❎ Failed to embed artwork")
 
 	switch {
-	// Common errors
-	case errors.Is(err, artworks.ErrArtworkNotFound):
-		eb.Description("Artwork has been removed or is invalid.")
-	case errors.Is(err, artworks.ErrRateLimited):
-		eb.Description("Boe Tea was rate limited. Please try again later.")
-
-	// Twitter errors
+	// Twitter errors match ErrArtworkNotFound, so they go first.
 	case errors.Is(err, twitter.ErrTweetNotFound):
 		if gctx.Command == nil {
 			return nil
@@ -670,6 +727,14 @@ func onArtworkError(b *bot.Bot, gctx *gumi.Ctx, err *artworks.Error) *embeds.Bui
 		eb.Description("Tweet not found or is NSFW. NSFW tweets can't be embedded due to API changes.")
 	case errors.Is(err, twitter.ErrPrivateAccount):
 		eb.Description("Unable to view this tweet because this account owner limits who can view their tweets.")
+	case errors.Is(err, nhentai.ErrCloudflareProtection):
+		eb.Description("Failed to bypass nhentai's Cloudflare protection. Please try again later.")
+
+	// Common errors
+	case errors.Is(err, artworks.ErrArtworkNotFound):
+		eb.Description("Artwork has been removed or is invalid.")
+	case errors.Is(err, artworks.ErrRateLimited):
+		eb.Description("Boe Tea was rate limited. Please try again later.")
 
 	default:
 		onDefaultError(b, gctx, err)
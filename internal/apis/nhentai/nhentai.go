@@ -1,16 +1,29 @@
 package nhentai
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
 	"time"
+
+	"github.com/VTGare/boe-tea-go/internal/cache"
+	"github.com/julien040/go-ternary"
+)
+
+const (
+	// DefaultHost is the host of nhentai API if no mirror is set.
+	DefaultHost = "nhentai.net"
+	// staleTTL is how long found galleries are kept to be returned when Cloudflare blocks requests.
+	staleTTL = 7 * 24 * time.Hour
 )
 
 type API struct {
 	client *http.Client
+	host   string
+	cache  cache.Backend
 }
 
 type nhentaiResult struct {
@@ -27,12 +40,36 @@ type nhentaiResult struct {
 	} `json:"tags,omitempty"`
 	Pages     any `json:"num_pages,omitempty"`
 	Favorites any `json:"num_favorites,omitempty"`
+	Images    struct {
+		Pages []nhentaiImage `json:"pages,omitempty"`
+		Cover nhentaiImage   `json:"cover,omitempty"`
+	} `json:"images,omitempty"`
+}
+
+type nhentaiImage struct {
+	// Type is the first letter of the image format, e.g. "j" for JPEG.
+	Type string `json:"t,omitempty"`
+}
+
+// ext returns a file extension of the image. Unknown formats are assumed to be JPEG.
+func (i nhentaiImage) ext() string {
+	switch i.Type {
+	case "p":
+		return "png"
+	case "g":
+		return "gif"
+	case "w":
+		return "webp"
+	}
+
+	return "jpg"
 }
 
 type Hentai struct {
 	ID         int
 	URL        string
 	Cover      string
+	PageURLs   []string
 	MediaID    string
 	Titles     *Titles
 	Tags       []*Tag
@@ -70,19 +107,47 @@ var (
 	ErrCloudflareProtection = errors.New("failed to bypass cloudflare")
 )
 
-func New() (*API, error) {
-	client := &http.Client{}
+// New creates an nhentai API client. Galleries are requested from the mirror host if it's not empty.
+// Found galleries are cached in the backend and returned instead of Cloudflare errors.
+func New(mirror string, backend cache.Backend) (*API, error) {
+	client := &http.Client{
+		Timeout: 15 * time.Second,
+	}
+
 	return &API{
 		client: client,
+		host:   ternary.If(mirror != "", mirror, DefaultHost),
+		cache:  backend,
 	}, nil
 }
 
-func (n *API) FindHentai(id string) (*Hentai, error) {
-	res, err := n.get(id)
+// FindHentai finds a gallery by its ID. A cached gallery is returned if Cloudflare blocks the request.
+func (n *API) FindHentai(ctx context.Context, id string) (*Hentai, error) {
+	key := "nhentai:" + id
+
+	res, err := n.get(ctx, id)
 	if err != nil {
+		if errors.Is(err, ErrCloudflareProtection) && n.cache != nil {
+			if hentai, err := cache.GetJSON[Hentai](ctx, n.cache, key); err == nil {
+				return hentai, nil
+			}
+		}
+
 		return nil, err
 	}
 
+	hentai := newHentai(n.host, res)
+	if n.cache != nil {
+		// Failing to cache the gallery only matters once Cloudflare blocks it.
+		_ = cache.SetJSON(ctx, n.cache, key, hentai, staleTTL)
+	}
+
+	return hentai, nil
+}
+
+// newHentai converts an API result. Images are served from the subdomains of the API host, e.g. i.nhentai.net.
+func newHentai(host string, res *nhentaiResult) *Hentai {
+
 	tags := make([]*Tag, 0, len(res.Tags))
 	for _, tag := range res.Tags {
 		prettyTag := &Tag{
@@ -109,17 +174,23 @@ func (n *API) FindHentai(id string) (*Hentai, error) {
 		tags = append(tags, prettyTag)
 	}
 
+	pages := make([]string, 0, len(res.Images.Pages))
+	for ind, page := range res.Images.Pages {
+		pages = append(pages, fmt.Sprintf("https://i.%v/galleries/%v/%v.%v", host, res.MediaID, ind+1, page.ext()))
+	}
+
 	return &Hentai{
 		Titles:     res.Titles,
 		MediaID:    res.MediaID,
 		URL:        fmt.Sprintf("https://nhentai.net/g/%v", res.ID),
 		Tags:       tags,
-		Cover:      fmt.Sprintf("https://t.nhentai.net/galleries/%v/cover.jpg", res.MediaID),
+		Cover:      fmt.Sprintf("https://t.%v/galleries/%v/cover.%v", host, res.MediaID, res.Images.Cover.ext()),
+		PageURLs:   pages,
 		ID:         interfaceToInt(res.ID),
 		Pages:      interfaceToInt(res.Pages),
 		Favorites:  interfaceToInt(res.Favorites),
 		UploadedAt: time.Unix(res.UploadDate, 0),
-	}, nil
+	}
 }
 
 func (h *Hentai) Genres() []*Tag {
@@ -180,8 +251,8 @@ func (t *Tag) String() string {
 	return t.Name
 }
 
-func (n *API) get(id string) (*nhentaiResult, error) {
-	req, err := http.NewRequest("GET", "https://nhentai.net/api/gallery/"+id, nil)
+func (n *API) get(ctx context.Context, id string) (*nhentaiResult, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("https://%v/api/gallery/%v", n.host, id), nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create a request to nhentai: %w", err)
 	}
@@ -202,7 +273,7 @@ func (n *API) get(id string) (*nhentaiResult, error) {
 
 		return &res, nil
 
-	case 503:
+	case 403, 503:
 		return nil, ErrCloudflareProtection
 	case 404:
 		return nil, ErrNotFound
@@ -1,7 +1,16 @@
 package nhentai
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/VTGare/boe-tea-go/internal/cache"
 )
 
 func TestNHentaiAPI_FindHentai(t *testing.T) {
@@ -32,3 +41,88 @@ func TestNHentaiAPI_FindHentai(t *testing.T) {
 	// 	},
 	// }
 }
+
+const gallery = `{
+	"id": 177013,
+	"media_id": "987560",
+	"title": {"pretty": "Metamorphosis"},
+	"num_pages": 2,
+	"images": {"pages": [{"t": "j"}, {"t": "p"}], "cover": {"t": "w"}}
+}`
+
+func newTestAPI(t *testing.T, handler http.HandlerFunc) *API {
+	srv := httptest.NewTLSServer(handler)
+	t.Cleanup(srv.Close)
+
+	return &API{
+		client: srv.Client(),
+		host:   strings.TrimPrefix(srv.URL, "https://"),
+		cache:  cache.NewMemory(time.Minute, time.Minute),
+	}
+}
+
+func TestNHentaiAPI_FindHentaiPages(t *testing.T) {
+	api := newTestAPI(t, func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte(gallery))
+	})
+
+	hentai, err := api.FindHentai(context.Background(), "177013")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"https://i." + api.host + "/galleries/987560/1.jpg",
+		"https://i." + api.host + "/galleries/987560/2.png",
+	}
+
+	if strings.Join(hentai.PageURLs, " ") != strings.Join(want, " ") {
+		t.Errorf("PageURLs = %v, want %v", hentai.PageURLs, want)
+	}
+
+	if hentai.Cover != "https://t."+api.host+"/galleries/987560/cover.webp" {
+		t.Errorf("Cover = %v", hentai.Cover)
+	}
+}
+
+func TestNHentaiAPI_FindHentaiCloudflare(t *testing.T) {
+	var blocked atomic.Bool
+	api := newTestAPI(t, func(w http.ResponseWriter, _ *http.Request) {
+		if blocked.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		w.Write([]byte(gallery))
+	})
+
+	if _, err := api.FindHentai(context.Background(), "177013"); err != nil {
+		t.Fatal(err)
+	}
+
+	blocked.Store(true)
+	hentai, err := api.FindHentai(context.Background(), "177013")
+	if err != nil {
+		t.Fatalf("expected a cached gallery, got %v", err)
+	}
+
+	if hentai.ID != 177013 {
+		t.Errorf("ID = %v, want 177013", hentai.ID)
+	}
+
+	_, err = api.FindHentai(context.Background(), "1")
+	if !errors.Is(err, ErrCloudflareProtection) {
+		t.Errorf("expected ErrCloudflareProtection for an uncached gallery, got %v", err)
+	}
+}
+
+func TestNHentaiAPI_FindHentaiNotFound(t *testing.T) {
+	api := newTestAPI(t, func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	_, err := api.FindHentai(context.Background(), "1")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,21 @@
+// Package bus implements request-reply messaging between Boe Tea processes.
+package bus
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNoHandler is returned if no process handles a subject.
+var ErrNoHandler = errors.New("no handler for subject")
+
+// HandlerFunc handles a request and returns a reply.
+type HandlerFunc func(ctx context.Context, data []byte) ([]byte, error)
+
+// Bus delivers requests to a process that handles the subject and waits for a reply.
+// A subject should be handled by a single process, otherwise any of them may reply.
+type Bus interface {
+	Request(ctx context.Context, subject string, data []byte) ([]byte, error)
+	Handle(subject string, handler HandlerFunc) error
+	Close() error
+}
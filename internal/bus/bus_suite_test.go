@@ -0,0 +1,80 @@
+package bus_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/VTGare/boe-tea-go/internal/bus"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestBus(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Bus Suite")
+}
+
+func busSpecs(newBus func() bus.Bus) {
+	var (
+		ctx context.Context
+		b   bus.Bus
+	)
+
+	BeforeEach(func() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+		DeferCleanup(cancel)
+
+		b = newBus()
+	})
+
+	It("should reply to requests", func() {
+		Expect(b.Handle("echo", func(_ context.Context, data []byte) ([]byte, error) {
+			return append([]byte("echo: "), data...), nil
+		})).To(Succeed())
+
+		res, err := b.Request(ctx, "echo", []byte("boe"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(res)).To(Equal("echo: boe"))
+	})
+
+	It("should return handler errors", func() {
+		Expect(b.Handle("fail", func(context.Context, []byte) ([]byte, error) {
+			return nil, errors.New("failed to send a message")
+		})).To(Succeed())
+
+		_, err := b.Request(ctx, "fail", nil)
+		Expect(err).To(MatchError("failed to send a message"))
+	})
+
+	It("should return ErrNoHandler if nobody handles a subject", func() {
+		_, err := b.Request(ctx, "missing", nil)
+		Expect(err).To(MatchError(bus.ErrNoHandler))
+	})
+}
+
+var _ = Describe("Memory bus", func() {
+	busSpecs(bus.NewMemory)
+})
+
+// Set BOETEA_TEST_REDIS_ADDR to run Redis tests, e.g. localhost:6379
+var _ = Describe("Redis bus", func() {
+	addr := os.Getenv("BOETEA_TEST_REDIS_ADDR")
+
+	BeforeEach(func() {
+		if addr == "" {
+			Skip("BOETEA_TEST_REDIS_ADDR is not set")
+		}
+	})
+
+	busSpecs(func() bus.Bus {
+		b, err := bus.NewRedis(addr)
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(b.Close)
+
+		return b
+	})
+})
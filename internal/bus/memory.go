@@ -0,0 +1,40 @@
+package bus
+
+import (
+	"context"
+	"sync"
+)
+
+type memoryBus struct {
+	mu       sync.RWMutex
+	handlers map[string]HandlerFunc
+}
+
+// NewMemory creates an in-process bus. It's used when all shards run in a single process.
+func NewMemory() Bus {
+	return &memoryBus{handlers: make(map[string]HandlerFunc)}
+}
+
+func (m *memoryBus) Request(ctx context.Context, subject string, data []byte) ([]byte, error) {
+	m.mu.RLock()
+	handler, ok := m.handlers[subject]
+	m.mu.RUnlock()
+
+	if !ok {
+		return nil, ErrNoHandler
+	}
+
+	return handler(ctx, data)
+}
+
+func (m *memoryBus) Handle(subject string, handler HandlerFunc) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.handlers[subject] = handler
+	return nil
+}
+
+func (m *memoryBus) Close() error {
+	return nil
+}
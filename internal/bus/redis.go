@@ -0,0 +1,151 @@
+package bus
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sync"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const redisPrefix = "boetea:bus:"
+
+type redisBus struct {
+	client *redis.Client
+
+	mu   sync.Mutex
+	subs []*redis.PubSub
+}
+
+type request struct {
+	ReplyTo string `json:"reply_to"`
+	Data    []byte `json:"data"`
+}
+
+type reply struct {
+	Data  []byte `json:"data"`
+	Error string `json:"error,omitempty"`
+}
+
+// NewRedis creates a bus on top of Redis Pub/Sub. Every request is published to the subject's channel
+// and the reply is published to a unique channel the requester subscribes to beforehand.
+func NewRedis(addr string) (Bus, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:       addr,
+		MaxRetries: 5,
+	})
+
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		return nil, err
+	}
+
+	return &redisBus{client: client}, nil
+}
+
+func (r *redisBus) Request(ctx context.Context, subject string, data []byte) ([]byte, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+
+	replyTo := redisPrefix + "reply:" + hex.EncodeToString(id)
+	sub := r.client.Subscribe(ctx, replyTo)
+	defer sub.Close()
+
+	// Wait for subscription confirmation, otherwise the reply may be published before we listen to it.
+	if _, err := sub.Receive(ctx); err != nil {
+		return nil, err
+	}
+
+	payload, err := json.Marshal(request{ReplyTo: replyTo, Data: data})
+	if err != nil {
+		return nil, err
+	}
+
+	receivers, err := r.client.Publish(ctx, redisPrefix+subject, payload).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	if receivers == 0 {
+		return nil, ErrNoHandler
+	}
+
+	select {
+	case msg, ok := <-sub.Channel():
+		if !ok {
+			return nil, errors.New("reply subscription closed")
+		}
+
+		var rep reply
+		if err := json.Unmarshal([]byte(msg.Payload), &rep); err != nil {
+			return nil, err
+		}
+
+		if rep.Error != "" {
+			return nil, errors.New(rep.Error)
+		}
+
+		return rep.Data, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (r *redisBus) Handle(subject string, handler HandlerFunc) error {
+	ctx := context.Background()
+
+	sub := r.client.Subscribe(ctx, redisPrefix+subject)
+	if _, err := sub.Receive(ctx); err != nil {
+		sub.Close()
+		return err
+	}
+
+	r.mu.Lock()
+	r.subs = append(r.subs, sub)
+	r.mu.Unlock()
+
+	go func() {
+		for msg := range sub.Channel() {
+			go r.handle(ctx, handler, msg.Payload)
+		}
+	}()
+
+	return nil
+}
+
+func (r *redisBus) handle(ctx context.Context, handler HandlerFunc, payload string) {
+	var req request
+	if err := json.Unmarshal([]byte(payload), &req); err != nil {
+		return
+	}
+
+	var rep reply
+	data, err := handler(ctx, req.Data)
+	if err != nil {
+		rep.Error = err.Error()
+	} else {
+		rep.Data = data
+	}
+
+	res, err := json.Marshal(rep)
+	if err != nil {
+		return
+	}
+
+	r.client.Publish(ctx, req.ReplyTo, res)
+}
+
+func (r *redisBus) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, sub := range r.subs {
+		sub.Close()
+	}
+
+	return r.client.Close()
+}
@@ -0,0 +1,46 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Backend is a cache of encoded values. In-memory backend is local to the process, Redis backend is shared
+// by all Boe Tea instances, so deleting a key on one instance invalidates it for every other instance.
+type Backend interface {
+	// Get returns ErrMiss if the key doesn't exist or has expired.
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Set stores the value for ttl. Zero ttl uses backend's default expiration.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+	Close() error
+}
+
+var ErrMiss = errors.New("cache miss")
+
+// GetJSON decodes a JSON value stored under the key.
+func GetJSON[T any](ctx context.Context, b Backend, key string) (*T, error) {
+	data, err := b.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+
+	return &v, nil
+}
+
+// SetJSON stores a JSON encoded value under the key.
+func SetJSON(ctx context.Context, b Backend, key string, v any, ttl time.Duration) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	return b.Set(ctx, key, data, ttl)
+}
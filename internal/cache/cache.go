@@ -1,11 +1,9 @@
 package cache
 
 import (
-	"fmt"
+	"context"
 	"sync"
 	"time"
-
-	"github.com/ReneKroon/ttlcache"
 )
 
 // Cache represents a thread-safe map
@@ -61,11 +59,8 @@ func (c *Cache) Len() int {
 	return len(c.cache)
 }
 
-type EmbedCache struct {
-	cache *ttlcache.Cache
-}
-
-// MessageInfo is a message/channel ID pair.
+// MessageInfo is a message/channel ID pair. ArtworkID is the ID the artwork's link was matched with,
+// the same ID reposts are keyed by.
 type MessageInfo struct {
 	MessageID string
 	ChannelID string
@@ -83,52 +78,24 @@ type CachedPost struct {
 	Children []*MessageInfo
 }
 
-func (ec *EmbedCache) makeKey(channelID, messageID string) string {
-	return fmt.Sprintf(
-		"channel:%v:message:%v",
-		channelID,
-		messageID,
-	)
-}
-
-func (ec *EmbedCache) Get(channelID, messageID string) (*CachedPost, bool) {
-	key := ec.makeKey(
-		channelID, messageID,
-	)
-
-	if embed, ok := ec.cache.Get(key); ok {
-		if embed, ok := embed.(*CachedPost); ok {
-			return embed, true
-		}
-	}
-
-	return nil, false
+// BannedUsers stores recently banned users in a cache backend.
+type BannedUsers struct {
+	backend Backend
 }
 
-func (ec *EmbedCache) Set(userID, channelID, messageID string, isParent bool, children ...*MessageInfo) {
-	key := ec.makeKey(
-		channelID, messageID,
-	)
+const bannedTTL = 15 * time.Second
 
-	ec.cache.Set(key, &CachedPost{
-		AuthorID: userID,
-		IsParent: isParent,
-		Children: children,
-	})
+// NewBannedUsers creates a new banned users cache.
+func NewBannedUsers(backend Backend) *BannedUsers {
+	return &BannedUsers{backend}
 }
 
-func (ec *EmbedCache) Remove(channelID, messageID string) bool {
-	key := ec.makeKey(
-		channelID, messageID,
-	)
-
-	return ec.cache.Remove(key)
+func (bu *BannedUsers) Add(ctx context.Context, userID string) error {
+	return bu.backend.Set(ctx, "banned:"+userID, []byte{1}, bannedTTL)
 }
 
-// NewEmbedCache creates a new embed cache for storing IDs of embeds users posted.
-func NewEmbedCache() *EmbedCache {
-	cache := ttlcache.NewCache()
-	cache.SetTTL(15 * time.Minute)
-
-	return &EmbedCache{cache}
+// Has reports whether a user was banned recently. Backend errors are treated as misses.
+func (bu *BannedUsers) Has(ctx context.Context, userID string) bool {
+	_, err := bu.backend.Get(ctx, "banned:"+userID)
+	return err == nil
 }
@@ -0,0 +1,142 @@
+package cache_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/VTGare/boe-tea-go/internal/cache"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestCache(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Cache Suite")
+}
+
+type value struct {
+	Name string `json:"name"`
+}
+
+func backendSpecs(newBackend func() cache.Backend) {
+	var (
+		ctx = context.Background()
+		b   cache.Backend
+	)
+
+	BeforeEach(func() {
+		b = newBackend()
+	})
+
+	It("should return ErrMiss for missing keys", func() {
+		_, err := b.Get(ctx, "missing")
+		Expect(err).To(MatchError(cache.ErrMiss))
+	})
+
+	It("should set, get and delete JSON values", func() {
+		Expect(cache.SetJSON(ctx, b, "key", value{"boe"}, 0)).To(Succeed())
+
+		v, err := cache.GetJSON[value](ctx, b, "key")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(v.Name).To(Equal("boe"))
+
+		Expect(b.Delete(ctx, "key")).To(Succeed())
+
+		_, err = b.Get(ctx, "key")
+		Expect(err).To(MatchError(cache.ErrMiss))
+	})
+
+	It("should expire values", func() {
+		Expect(b.Set(ctx, "key", []byte("1"), time.Second)).To(Succeed())
+		Eventually(func() error {
+			_, err := b.Get(ctx, "key")
+			return err
+		}).WithTimeout(3 * time.Second).Should(MatchError(cache.ErrMiss))
+	})
+}
+
+var _ = Describe("Memory backend", func() {
+	backendSpecs(func() cache.Backend {
+		return cache.NewMemory(time.Minute, time.Minute)
+	})
+})
+
+// Set BOETEA_TEST_REDIS_ADDR to run Redis tests, e.g. localhost:6379
+var _ = Describe("Redis backend", func() {
+	addr := os.Getenv("BOETEA_TEST_REDIS_ADDR")
+
+	BeforeEach(func() {
+		if addr == "" {
+			Skip("BOETEA_TEST_REDIS_ADDR is not set")
+		}
+	})
+
+	backendSpecs(func() cache.Backend {
+		b, err := cache.NewRedis(addr, time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(b.Close)
+
+		return b
+	})
+
+	It("should share invalidations between instances", func() {
+		ctx := context.Background()
+
+		first, err := cache.NewRedis(addr, time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(first.Close)
+
+		second, err := cache.NewRedis(addr, time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(second.Close)
+
+		Expect(first.Set(ctx, "guilds:1", []byte("{}"), 0)).To(Succeed())
+		Expect(second.Delete(ctx, "guilds:1")).To(Succeed())
+
+		_, err = first.Get(ctx, "guilds:1")
+		Expect(err).To(MatchError(cache.ErrMiss))
+	})
+})
+
+var _ = Describe("LRU", func() {
+	It("should evict the least recently used value", func() {
+		lru := cache.NewLRU[int](2)
+		lru.Set("a", 1, time.Minute)
+		lru.Set("b", 2, time.Minute)
+
+		_, ok := lru.Get("a")
+		Expect(ok).To(BeTrue())
+
+		lru.Set("c", 3, time.Minute)
+		Expect(lru.Len()).To(Equal(2))
+
+		_, ok = lru.Get("b")
+		Expect(ok).To(BeFalse())
+
+		v, ok := lru.Get("a")
+		Expect(ok).To(BeTrue())
+		Expect(v).To(Equal(1))
+	})
+
+	It("should replace values", func() {
+		lru := cache.NewLRU[int](2)
+		lru.Set("a", 1, time.Minute)
+		lru.Set("a", 2, time.Minute)
+
+		v, ok := lru.Get("a")
+		Expect(ok).To(BeTrue())
+		Expect(v).To(Equal(2))
+		Expect(lru.Len()).To(Equal(1))
+	})
+
+	It("should expire values", func() {
+		lru := cache.NewLRU[int](2)
+		lru.Set("a", 1, -time.Second)
+
+		_, ok := lru.Get("a")
+		Expect(ok).To(BeFalse())
+		Expect(lru.Len()).To(BeZero())
+	})
+})
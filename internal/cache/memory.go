@@ -0,0 +1,47 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	goCache "github.com/patrickmn/go-cache"
+)
+
+type memoryBackend struct {
+	cache *goCache.Cache
+}
+
+// NewMemory creates a process-local cache backend.
+func NewMemory(defaultTTL, cleanupInterval time.Duration) Backend {
+	return &memoryBackend{goCache.New(defaultTTL, cleanupInterval)}
+}
+
+func (m *memoryBackend) Get(_ context.Context, key string) ([]byte, error) {
+	v, ok := m.cache.Get(key)
+	if !ok {
+		return nil, ErrMiss
+	}
+
+	return v.([]byte), nil
+}
+
+func (m *memoryBackend) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	if ttl == 0 {
+		ttl = goCache.DefaultExpiration
+	}
+
+	m.cache.Set(key, value, ttl)
+	return nil
+}
+
+func (m *memoryBackend) Delete(_ context.Context, keys ...string) error {
+	for _, key := range keys {
+		m.cache.Delete(key)
+	}
+
+	return nil
+}
+
+func (m *memoryBackend) Close() error {
+	return nil
+}
@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const redisPrefix = "boetea:cache:"
+
+type redisBackend struct {
+	client     *redis.Client
+	defaultTTL time.Duration
+}
+
+// NewRedis creates a cache backend shared by all instances connected to the same Redis server.
+func NewRedis(addr string, defaultTTL time.Duration) (Backend, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:       addr,
+		MaxRetries: 5,
+	})
+
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		return nil, err
+	}
+
+	return &redisBackend{client, defaultTTL}, nil
+}
+
+func (r *redisBackend) Get(ctx context.Context, key string) ([]byte, error) {
+	data, err := r.client.Get(ctx, redisPrefix+key).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrMiss
+		}
+
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (r *redisBackend) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if ttl == 0 {
+		ttl = r.defaultTTL
+	}
+
+	return r.client.Set(ctx, redisPrefix+key, value, ttl).Err()
+}
+
+func (r *redisBackend) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	prefixed := make([]string, 0, len(keys))
+	for _, key := range keys {
+		prefixed = append(prefixed, redisPrefix+key)
+	}
+
+	return r.client.Del(ctx, prefixed...).Err()
+}
+
+func (r *redisBackend) Close() error {
+	return r.client.Close()
+}
@@ -0,0 +1,37 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/VTGare/boe-tea-go/internal/config"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestConfig(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Config Suite")
+}
+
+var _ = DescribeTable("Shard IDs",
+	func(ids string, expected []int, fails bool) {
+		shards := &config.Shards{IDs: ids}
+
+		res, err := shards.ShardIDs()
+		if fails {
+			Expect(err).To(HaveOccurred())
+			return
+		}
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res).To(Equal(expected))
+	},
+	Entry("empty", "", []int{}, false),
+	Entry("single", "3", []int{3}, false),
+	Entry("list", "0, 2,4", []int{0, 2, 4}, false),
+	Entry("range", "4-7", []int{4, 5, 6, 7}, false),
+	Entry("mixed", "0-1,8", []int{0, 1, 8}, false),
+	Entry("reversed range", "7-4", nil, true),
+	Entry("negative", "-1", nil, true),
+	Entry("not a number", "a", nil, true),
+)
@@ -3,6 +3,7 @@ package dgoutils
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -86,6 +87,103 @@ func MemberHasPermission(s *discordgo.Session, guildID string, userID string, pe
 	return false, nil
 }
 
+// ChannelPermissions returns permissions of a member in a channel. Guilds of shards running on other processes
+// aren't in session's state, their permissions are computed from REST responses.
+func ChannelPermissions(s *discordgo.Session, ch *discordgo.Channel, userID string) (int64, error) {
+	if perms, err := s.State.UserChannelPermissions(userID, ch.ID); err == nil {
+		return perms, nil
+	}
+
+	guild, err := s.Guild(ch.GuildID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get guild: %w", err)
+	}
+
+	member, err := s.GuildMember(ch.GuildID, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get member: %w", err)
+	}
+
+	return MemberChannelPermissions(guild, ch, member), nil
+}
+
+// MemberChannelPermissions computes permissions of a member in a channel following Discord's algorithm:
+// base permissions of @everyone and member's roles, then channel overwrites of @everyone, roles and the member.
+// See https://discord.com/developers/docs/topics/permissions#permission-overwrites
+func MemberChannelPermissions(guild *discordgo.Guild, ch *discordgo.Channel, member *discordgo.Member) int64 {
+	if guild.OwnerID == member.User.ID {
+		return discordgo.PermissionAll
+	}
+
+	var perms int64
+	for _, role := range guild.Roles {
+		if role.ID == guild.ID || slices.Contains(member.Roles, role.ID) {
+			perms |= role.Permissions
+		}
+	}
+
+	if perms&discordgo.PermissionAdministrator != 0 {
+		return discordgo.PermissionAll
+	}
+
+	var allow, deny int64
+	for _, overwrite := range ch.PermissionOverwrites {
+		switch {
+		case overwrite.Type == discordgo.PermissionOverwriteTypeRole && overwrite.ID == guild.ID:
+			perms &= ^overwrite.Deny
+			perms |= overwrite.Allow
+		case overwrite.Type == discordgo.PermissionOverwriteTypeRole && slices.Contains(member.Roles, overwrite.ID):
+			allow |= overwrite.Allow
+			deny |= overwrite.Deny
+		}
+	}
+
+	perms &= ^deny
+	perms |= allow
+
+	for _, overwrite := range ch.PermissionOverwrites {
+		if overwrite.Type == discordgo.PermissionOverwriteTypeMember && overwrite.ID == member.User.ID {
+			perms &= ^overwrite.Deny
+			perms |= overwrite.Allow
+		}
+	}
+
+	return perms
+}
+
+// IsNSFWChannel reports whether a channel is age-restricted. Threads inherit the flag of their parent channel,
+// direct messages have no restrictions.
+func IsNSFWChannel(s *discordgo.Session, channelID string) (bool, error) {
+	ch, err := channel(s, channelID)
+	if err != nil {
+		return false, err
+	}
+
+	if ch.Type == discordgo.ChannelTypeDM || ch.Type == discordgo.ChannelTypeGroupDM {
+		return true, nil
+	}
+
+	if ch.IsThread() {
+		parent, err := channel(s, ch.ParentID)
+		if err != nil {
+			return false, err
+		}
+
+		return parent.NSFW, nil
+	}
+
+	return ch.NSFW, nil
+}
+
+// channel returns a channel from session's state or requests it.
+func channel(s *discordgo.Session, channelID string) (*discordgo.Channel, error) {
+	if ch, err := s.State.Channel(channelID); err == nil {
+		return ch, nil
+	}
+
+	return s.Channel(channelID)
+}
+
 type Range struct {
 	Low  int
 	High int
@@ -2,6 +2,8 @@ package dgoutils
 
 import (
 	"testing"
+
+	"github.com/bwmarrin/discordgo"
 )
 
 func TestRange_Array(t *testing.T) {
@@ -62,3 +64,77 @@ func TestNewRange(t *testing.T) {
 	// 	},
 	// }
 }
+
+func TestMemberChannelPermissions(t *testing.T) {
+	const send = discordgo.PermissionViewChannel | discordgo.PermissionSendMessages
+
+	guild := &discordgo.Guild{
+		ID:      "guild",
+		OwnerID: "owner",
+		Roles: []*discordgo.Role{
+			{ID: "guild", Permissions: send},
+			{ID: "admin", Permissions: discordgo.PermissionAdministrator},
+			{ID: "muted"},
+		},
+	}
+
+	member := func(id string, roles ...string) *discordgo.Member {
+		return &discordgo.Member{User: &discordgo.User{ID: id}, Roles: roles}
+	}
+
+	tests := []struct {
+		name       string
+		member     *discordgo.Member
+		overwrites []*discordgo.PermissionOverwrite
+		want       int64
+	}{
+		{
+			name:   "everyone",
+			member: member("bot"),
+			want:   send,
+		},
+		{
+			name:   "owner",
+			member: member("owner"),
+			overwrites: []*discordgo.PermissionOverwrite{
+				{ID: "guild", Type: discordgo.PermissionOverwriteTypeRole, Deny: send},
+			},
+			want: discordgo.PermissionAll,
+		},
+		{
+			name:   "administrator ignores overwrites",
+			member: member("bot", "admin"),
+			overwrites: []*discordgo.PermissionOverwrite{
+				{ID: "bot", Type: discordgo.PermissionOverwriteTypeMember, Deny: send},
+			},
+			want: discordgo.PermissionAll,
+		},
+		{
+			name:   "role overwrite denies",
+			member: member("bot", "muted"),
+			overwrites: []*discordgo.PermissionOverwrite{
+				{ID: "muted", Type: discordgo.PermissionOverwriteTypeRole, Deny: discordgo.PermissionSendMessages},
+			},
+			want: discordgo.PermissionViewChannel,
+		},
+		{
+			name:   "member overwrite wins over roles",
+			member: member("bot", "muted"),
+			overwrites: []*discordgo.PermissionOverwrite{
+				{ID: "guild", Type: discordgo.PermissionOverwriteTypeRole, Deny: send},
+				{ID: "muted", Type: discordgo.PermissionOverwriteTypeRole, Deny: send},
+				{ID: "bot", Type: discordgo.PermissionOverwriteTypeMember, Allow: send},
+			},
+			want: send,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ch := &discordgo.Channel{ID: "channel", GuildID: guild.ID, PermissionOverwrites: tt.overwrites}
+			if got := MemberChannelPermissions(guild, ch, tt.member); got != tt.want {
+				t.Errorf("MemberChannelPermissions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package imageproxy
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// errTooLarge is returned by the cache for images that don't fit into it.
+var errTooLarge = errors.New("image is larger than the cache")
+
+// Cache is a size-bounded disk cache of images. The least recently used images are removed first.
+type Cache struct {
+	dir     string
+	maxSize int64
+
+	mu      sync.Mutex
+	size    int64
+	lru     *list.List
+	entries map[string]*list.Element
+}
+
+type cacheEntry struct {
+	name        string
+	size        int64
+	contentType string
+}
+
+// NewCache creates a cache in dir that keeps up to maxSize bytes. Images cached by a previous run are kept.
+func NewCache(dir string, maxSize int64) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	c := &Cache{
+		dir:     dir,
+		maxSize: maxSize,
+		lru:     list.New(),
+		entries: make(map[string]*list.Element),
+	}
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]os.FileInfo, 0, len(files))
+	for _, file := range files {
+		if file.IsDir() || strings.HasPrefix(file.Name(), ".") {
+			continue
+		}
+
+		info, err := file.Info()
+		if err != nil {
+			continue
+		}
+
+		infos = append(infos, info)
+	}
+
+	// Restore the recency from modification times, the most recent image goes first.
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].ModTime().After(infos[j].ModTime())
+	})
+
+	for _, info := range infos {
+		entry := &cacheEntry{
+			name:        info.Name(),
+			size:        info.Size(),
+			contentType: mime.TypeByExtension(filepath.Ext(info.Name())),
+		}
+
+		c.entries[entry.name] = c.lru.PushBack(entry)
+		c.size += entry.size
+	}
+
+	c.mu.Lock()
+	c.evict()
+	c.mu.Unlock()
+
+	return c, nil
+}
+
+// Open opens a cached image by its upstream path. The file stays readable even if it's evicted while open.
+func (c *Cache) Open(upstreamPath string) (*os.File, string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[cacheName(upstreamPath)]
+	if !ok {
+		return nil, "", false
+	}
+
+	entry := elem.Value.(*cacheEntry)
+	file, err := os.Open(filepath.Join(c.dir, entry.name))
+	if err != nil {
+		c.remove(elem)
+		return nil, "", false
+	}
+
+	c.lru.MoveToFront(elem)
+	return file, entry.contentType, true
+}
+
+// Put caches an image read from r.
+func (c *Cache) Put(upstreamPath, contentType string, r io.Reader) error {
+	tmp, err := os.CreateTemp(c.dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	// Read one byte over the limit to tell images that don't fit from the ones that fill the cache exactly.
+	size, err := io.Copy(tmp, io.LimitReader(r, c.maxSize+1))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if size > c.maxSize {
+		return errTooLarge
+	}
+
+	name := cacheName(upstreamPath)
+	if err := os.Rename(tmp.Name(), filepath.Join(c.dir, name)); err != nil {
+		return fmt.Errorf("rename: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[name]; ok {
+		c.size -= elem.Value.(*cacheEntry).size
+		c.lru.Remove(elem)
+	}
+
+	c.entries[name] = c.lru.PushFront(&cacheEntry{
+		name:        name,
+		size:        size,
+		contentType: contentType,
+	})
+	c.size += size
+	c.evict()
+
+	return nil
+}
+
+// evict removes the least recently used images until the cache fits. It must be called with the lock held.
+func (c *Cache) evict() {
+	for c.size > c.maxSize {
+		elem := c.lru.Back()
+		if elem == nil {
+			return
+		}
+
+		c.remove(elem)
+	}
+}
+
+func (c *Cache) remove(elem *list.Element) {
+	entry := elem.Value.(*cacheEntry)
+
+	c.lru.Remove(elem)
+	delete(c.entries, entry.name)
+	c.size -= entry.size
+
+	os.Remove(filepath.Join(c.dir, entry.name))
+}
+
+// cacheName returns a file name for an upstream path. The extension is kept to restore the content type.
+func cacheName(upstreamPath string) string {
+	sum := sha256.Sum256([]byte(upstreamPath))
+	return hex.EncodeToString(sum[:]) + path.Ext(upstreamPath)
+}
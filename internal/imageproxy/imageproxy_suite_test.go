@@ -0,0 +1,137 @@
+package imageproxy_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/VTGare/boe-tea-go/internal/imageproxy"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+)
+
+func TestImageProxy(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Image Proxy Suite")
+}
+
+const image = "0123456789abcdef"
+
+var _ = Describe("Proxy", func() {
+	var (
+		upstream *httptest.Server
+		requests atomic.Int64
+		signer   = imageproxy.NewSigner("secret")
+		path     = "/img-original/img/2024/01/01/00/00/00/123_p0.png"
+	)
+
+	BeforeEach(func() {
+		requests.Store(0)
+		upstream = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			if r.Header.Get("Referer") != "https://www.pixiv.net/" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+
+			w.Header().Set("Content-Type", "image/png")
+			http.ServeContent(w, r, "", time.Time{}, strings.NewReader(image))
+		}))
+	})
+
+	AfterEach(func() {
+		upstream.Close()
+	})
+
+	get := func(proxy http.Handler, url, rangeHeader string) *http.Response {
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+		if rangeHeader != "" {
+			req.Header.Set("Range", rangeHeader)
+		}
+
+		rec := httptest.NewRecorder()
+		proxy.ServeHTTP(rec, req)
+		return rec.Result()
+	}
+
+	body := func(resp *http.Response) string {
+		data, err := io.ReadAll(resp.Body)
+		Expect(err).NotTo(HaveOccurred())
+		return string(data)
+	}
+
+	It("should reject unsigned paths", func() {
+		proxy := imageproxy.New(upstream.URL, "https://www.pixiv.net/", signer, nil, zap.NewNop().Sugar())
+
+		resp := get(proxy, path+"?sig=invalid", "")
+		Expect(resp.StatusCode).To(Equal(http.StatusForbidden))
+		Expect(requests.Load()).To(BeZero())
+	})
+
+	It("should pass through the image and range requests", func() {
+		proxy := imageproxy.New(upstream.URL, "https://www.pixiv.net/", signer, nil, zap.NewNop().Sugar())
+
+		resp := get(proxy, path+"?sig="+signer.Sign(path), "")
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(resp.Header.Get("Content-Type")).To(Equal("image/png"))
+		Expect(body(resp)).To(Equal(image))
+
+		resp = get(proxy, path+"?sig="+signer.Sign(path), "bytes=2-5")
+		Expect(resp.StatusCode).To(Equal(http.StatusPartialContent))
+		Expect(body(resp)).To(Equal("2345"))
+	})
+
+	It("should serve cached images", func() {
+		cache, err := imageproxy.NewCache(GinkgoT().TempDir(), 1<<20)
+		Expect(err).NotTo(HaveOccurred())
+
+		proxy := imageproxy.New(upstream.URL, "https://www.pixiv.net/", signer, cache, zap.NewNop().Sugar())
+
+		resp := get(proxy, path+"?sig="+signer.Sign(path), "")
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(body(resp)).To(Equal(image))
+
+		resp = get(proxy, path+"?sig="+signer.Sign(path), "bytes=10-")
+		Expect(resp.StatusCode).To(Equal(http.StatusPartialContent))
+		Expect(resp.Header.Get("Content-Type")).To(Equal("image/png"))
+		Expect(body(resp)).To(Equal("abcdef"))
+
+		Expect(requests.Load()).To(BeEquivalentTo(1))
+	})
+})
+
+var _ = Describe("Cache", func() {
+	It("should evict the least recently used images", func() {
+		cache, err := imageproxy.NewCache(GinkgoT().TempDir(), 10)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(cache.Put("/a.png", "image/png", strings.NewReader("aaaa"))).To(Succeed())
+		Expect(cache.Put("/b.png", "image/png", strings.NewReader("bbbb"))).To(Succeed())
+
+		file, _, ok := cache.Open("/a.png")
+		Expect(ok).To(BeTrue())
+		file.Close()
+
+		Expect(cache.Put("/c.png", "image/png", strings.NewReader("cccc"))).To(Succeed())
+
+		_, _, ok = cache.Open("/b.png")
+		Expect(ok).To(BeFalse())
+
+		file, contentType, ok := cache.Open("/a.png")
+		Expect(ok).To(BeTrue())
+		Expect(contentType).To(Equal("image/png"))
+		file.Close()
+	})
+
+	It("should reject images larger than the cache", func() {
+		cache, err := imageproxy.NewCache(GinkgoT().TempDir(), 2)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(cache.Put("/a.png", "image/png", strings.NewReader("aaaa"))).NotTo(Succeed())
+	})
+})
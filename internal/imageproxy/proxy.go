@@ -0,0 +1,155 @@
+// Package imageproxy serves images from hosts that require headers Discord doesn't send, e.g. Pixiv's Referer check.
+package imageproxy
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// passthroughHeaders are upstream response headers forwarded to the client.
+var passthroughHeaders = []string{
+	"Content-Type",
+	"Content-Length",
+	"Content-Range",
+	"Accept-Ranges",
+	"Last-Modified",
+	"ETag",
+}
+
+// Proxy is an HTTP handler that serves images from the upstream host. Request paths are upstream paths
+// signed with a "sig" query parameter, the handler is expected to be mounted with http.StripPrefix.
+type Proxy struct {
+	upstream string
+	referer  string
+	signer   *Signer
+	cache    *Cache
+	client   *http.Client
+	log      *zap.SugaredLogger
+}
+
+// New creates a proxy of the upstream host, e.g. "https://i.pximg.net". Referer is sent with every upstream request.
+// A nil cache fetches every image from the upstream.
+func New(upstream, referer string, signer *Signer, cache *Cache, log *zap.SugaredLogger) *Proxy {
+	return &Proxy{
+		upstream: strings.TrimSuffix(upstream, "/"),
+		referer:  referer,
+		signer:   signer,
+		cache:    cache,
+		client:   &http.Client{Timeout: time.Minute},
+		log:      log,
+	}
+}
+
+// ServeHTTP implements http.Handler.
+func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	upstreamPath := r.URL.Path
+	if !p.signer.Verify(upstreamPath, r.URL.Query().Get("sig")) {
+		http.Error(w, "invalid signature", http.StatusForbidden)
+		return
+	}
+
+	if p.cache == nil {
+		p.stream(w, r, upstreamPath)
+		return
+	}
+
+	if file, contentType, ok := p.cache.Open(upstreamPath); ok {
+		p.serveCached(w, r, file, contentType)
+		return
+	}
+
+	resp, err := p.fetch(r, upstreamPath, "")
+	if err != nil {
+		p.log.With("error", err, "path", upstreamPath).Warn("failed to fetch an image")
+		http.Error(w, "bad gateway", http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+
+	// Errors and images that don't fit into the cache are passed through as is.
+	if resp.StatusCode != http.StatusOK || resp.ContentLength > p.cache.maxSize {
+		p.copyResponse(w, resp)
+		return
+	}
+
+	err = p.cache.Put(upstreamPath, resp.Header.Get("Content-Type"), resp.Body)
+	switch {
+	case errors.Is(err, errTooLarge):
+		p.stream(w, r, upstreamPath)
+		return
+	case err != nil:
+		p.log.With("error", err, "path", upstreamPath).Warn("failed to cache an image")
+		http.Error(w, "bad gateway", http.StatusBadGateway)
+		return
+	}
+
+	file, contentType, ok := p.cache.Open(upstreamPath)
+	if !ok {
+		p.stream(w, r, upstreamPath)
+		return
+	}
+
+	p.serveCached(w, r, file, contentType)
+}
+
+// serveCached serves a cached image. http.ServeContent handles range and conditional requests.
+func (p *Proxy) serveCached(w http.ResponseWriter, r *http.Request, file io.ReadSeekCloser, contentType string) {
+	defer file.Close()
+
+	if contentType != "" {
+		w.Header().Set("Content-Type", contentType)
+	}
+
+	// Upstream images never change, a new version gets a new path.
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	http.ServeContent(w, r, "", time.Time{}, file)
+}
+
+// stream passes the request through to the upstream, including the Range header.
+func (p *Proxy) stream(w http.ResponseWriter, r *http.Request, upstreamPath string) {
+	resp, err := p.fetch(r, upstreamPath, r.Header.Get("Range"))
+	if err != nil {
+		p.log.With("error", err, "path", upstreamPath).Warn("failed to fetch an image")
+		http.Error(w, "bad gateway", http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+
+	p.copyResponse(w, resp)
+}
+
+func (p *Proxy) fetch(r *http.Request, upstreamPath, rangeHeader string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(r.Context(), http.MethodGet, p.upstream+upstreamPath, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Referer", p.referer)
+	if rangeHeader != "" {
+		req.Header.Set("Range", rangeHeader)
+	}
+
+	return p.client.Do(req)
+}
+
+func (p *Proxy) copyResponse(w http.ResponseWriter, resp *http.Response) {
+	for _, header := range passthroughHeaders {
+		if value := resp.Header.Get(header); value != "" {
+			w.Header().Set(header, value)
+		}
+	}
+
+	if resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusPartialContent {
+		w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	}
+
+	w.WriteHeader(resp.StatusCode)
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		p.log.With("error", err).Debug("failed to copy an image")
+	}
+}
@@ -0,0 +1,29 @@
+package imageproxy
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// Signer signs proxied paths, so the proxy only fetches images Boe Tea linked to and can't be used as an open proxy.
+type Signer struct {
+	key []byte
+}
+
+func NewSigner(secret string) *Signer {
+	return &Signer{key: []byte(secret)}
+}
+
+// Sign returns a signature of the path.
+func (s *Signer) Sign(path string) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(path))
+
+	return hex.EncodeToString(mac.Sum(nil)[:16])
+}
+
+// Verify reports whether sig is a valid signature of the path.
+func (s *Signer) Verify(path, sig string) bool {
+	return hmac.Equal([]byte(s.Sign(path)), []byte(sig))
+}
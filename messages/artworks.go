@@ -28,3 +28,19 @@ func ErrLimitTooHigh(limit int64) error {
 		),
 	)
 }
+
+func NSFWSpoiler() string {
+	return "This artwork is NSFW. Images are hidden behind spoilers."
+}
+
+func NSFWBlocked() string {
+	return "NSFW artworks aren't allowed in this channel. Post them in an age-restricted channel instead."
+}
+
+func BlacklistSpoiler() string {
+	return "This artwork has blacklisted tags. Images are hidden behind spoilers."
+}
+
+func AIDeleted() string {
+	return "AI-generated artworks aren't allowed in this server. Your message has been removed."
+}
@@ -98,6 +98,7 @@ type UserProfile struct {
 	Stats     string
 	Groups    string
 	Bookmarks string
+	Blacklist string
 }
 
 type UserGroups struct {
@@ -109,6 +110,19 @@ type UserGroups struct {
 	Children    string
 }
 
+type UserFilter struct {
+	Title            string
+	Description      string
+	Providers        string
+	ExcludeProviders string
+	Content          string
+	Tags             string
+	ExcludeTags      string
+	ExcludeAI        string
+	Any              string
+	None             string
+}
+
 var embeds = map[Language]map[EmbedType]any{
 	English: {
 		artworkSearchWarning: &BaseEmbed{
@@ -99,3 +99,10 @@ func FormatDuration(d time.Duration) string {
 func RelativeTimestamp(t time.Time) string {
 	return fmt.Sprintf("<t:%v:R>", t.Unix())
 }
+
+func PixivTokensFailed(err error) string {
+	return fmt.Sprintf(
+		"Pixiv tokens couldn't be refreshed or saved: `%v`\nIf the refresh token was revoked, use `bt!providers reauth pixiv <refresh token>` to log in again.",
+		err,
+	)
+}
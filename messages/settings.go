@@ -32,6 +32,14 @@ func ErrExpirationOutOfRange(value string) error {
 	return newUserError(msg)
 }
 
+func ErrUnknownNSFWPolicy(policy string) error {
+	return newUserError(fmt.Sprintf("Unknown NSFW policy: `%v`. Use one of the following policies: `[allow, spoiler, block-in-sfw-channels]`", policy))
+}
+
+func ErrUnknownAIPolicy(policy string) error {
+	return newUserError(fmt.Sprintf("Unknown AI policy: `%v`. Use one of the following policies: `[label, hide, delete]`", policy))
+}
+
 func ErrUnknownRepostOption(option string) error {
 	return newUserError(fmt.Sprintf("Unknown option: `%v`. Use one of the following options: `[enabled, disabled, strict]`", option))
 }
@@ -82,3 +90,60 @@ func RemoveArtChannelFail(channels []string) error {
 		),
 	)
 }
+
+func ServerGroupsTitle() string {
+	return "Server crosspost groups"
+}
+
+func ServerGroupsEmpty() string {
+	return "This server doesn't have any crosspost groups. Create one using `bt!servergroups new <name> <parent channel>` command."
+}
+
+func ErrServerGroupExists(name, parent string) error {
+	return newUserError(fmt.Sprintf(
+		"Couldn't create a server group `%v`. A group with this name or parent channel <#%v> already exists.",
+		name, parent,
+	))
+}
+
+func ErrServerGroupNotFound(name string) error {
+	return newUserError(fmt.Sprintf("Couldn't find server group `%v`.", name))
+}
+
+func ErrNotServerManager(id string) error {
+	return newUserError(fmt.Sprintf(
+		"Couldn't add <#%v> | `%v`. You need Manage Server permission in the channel's server.",
+		id, id,
+	))
+}
+
+func ServerGroupCreateSuccess(name, parent string) string {
+	return fmt.Sprintf(
+		"Created a server group `%v` with parent channel <#%v> | `%v`. Every artwork posted there is crossposted for everyone.",
+		name, parent, parent,
+	)
+}
+
+func ServerGroupDeleteSuccess(name string) string {
+	return fmt.Sprintf("Removed a server group `%v`", name)
+}
+
+func BlacklistTitle() string {
+	return "Tag blacklist"
+}
+
+func BlacklistEmpty(command string) string {
+	return fmt.Sprintf("The blacklist is empty. Add tags using `%v blacklist add <tags>` command.", command)
+}
+
+func BlacklistAddSuccess(added int) string {
+	return fmt.Sprintf("Added %v tag(s) to the blacklist.", added)
+}
+
+func BlacklistRemoveSuccess(removed int) string {
+	return fmt.Sprintf("Removed %v tag(s) from the blacklist.", removed)
+}
+
+func ErrUnknownBlacklistMode(mode string) error {
+	return newUserError(fmt.Sprintf("Unknown blacklist mode: `%v`. Use one of the following modes: `[suppress, spoiler]`", mode))
+}
@@ -2,6 +2,7 @@ package messages
 
 import (
 	"fmt"
+	"strings"
 )
 
 func UserGroupsEmbed(username string) *UserGroups {
@@ -24,6 +25,7 @@ func UserProfileEmbed(username string) *UserProfile {
 		Stats:     "Stats",
 		Groups:    "Groups",
 		Bookmarks: "Bookmarks",
+		Blacklist: "Blacklisted tags",
 	}
 }
 
@@ -134,3 +136,97 @@ func ErrUserUnbookmarkFail(query any, err error) error {
 		err,
 	)
 }
+
+// ChannelCheck is a result of a crosspost channel check. Empty Problem means Boe Tea can crosspost to the channel.
+type ChannelCheck struct {
+	ChannelID string
+	Problem   string
+}
+
+func ChannelCheckNotFound() string {
+	return "channel not found or Boe Tea has no access to it"
+}
+
+func ChannelCheckNotText() string {
+	return "not a text channel"
+}
+
+func ChannelCheckNoPermissions() string {
+	return "Boe Tea lacks View Channel, Send Messages or Embed Links permission"
+}
+
+func ChannelCheckCrosspostDisabled() string {
+	return "server disabled crossposting"
+}
+
+func ChannelCheckNotArtChannel() string {
+	return "not an art channel"
+}
+
+// ChannelChecks formats channel checks as a list with a status of every channel.
+func ChannelChecks(checks []*ChannelCheck) string {
+	lines := make([]string, 0, len(checks))
+	for _, check := range checks {
+		if check.Problem == "" {
+			lines = append(lines, fmt.Sprintf("✅ <#%v> | `%v`: ok", check.ChannelID, check.ChannelID))
+			continue
+		}
+
+		lines = append(lines, fmt.Sprintf("❌ <#%v> | `%v`: %v", check.ChannelID, check.ChannelID, check.Problem))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func UserCheckGroupsTitle() string {
+	return "Crosspost channels check"
+}
+
+func ErrUserChannelChecksNoGroups() error {
+	return newUserError("You don't have any crosspost groups. To add a new group use `bt!newgroup` or `bt!newpair` command.")
+}
+
+func ErrUserChannelChecksFail(name string, checks []*ChannelCheck) error {
+	return newUserError(fmt.Sprintf(
+		"Boe Tea can't crosspost to some channels of `%v`:\n%v",
+		name, ChannelChecks(checks),
+	))
+}
+
+// UserSkippedChannels lists channels that were not added to a group because they failed a check.
+func UserSkippedChannels(checks []*ChannelCheck) string {
+	if len(checks) == 0 {
+		return ""
+	}
+
+	return "\n\nSkipped the following channels:\n" + ChannelChecks(checks)
+}
+
+func UserFilterEmbed(name string) *UserFilter {
+	return &UserFilter{
+		Title:            fmt.Sprintf("Crosspost filter of `%v`", name),
+		Description:      "Only artworks matching every rule are crossposted. Use `bt!help filter` to learn how to edit rules.",
+		Providers:        "Providers",
+		ExcludeProviders: "Excluded providers",
+		Content:          "Content",
+		Tags:             "Tags",
+		ExcludeTags:      "Excluded tags",
+		ExcludeAI:        "Exclude AI-generated",
+		Any:              "any",
+		None:             "none",
+	}
+}
+
+func ErrUnknownProvider(provider string, providers []string) error {
+	return newUserError(fmt.Sprintf(
+		"Unknown provider `%v`. Supported providers: %v",
+		provider, "`"+strings.Join(providers, "`, `")+"`",
+	))
+}
+
+func ErrUnknownGroupContent(content string) error {
+	return newUserError(fmt.Sprintf(
+		"Unknown content type `%v`. Supported types: `all`, `nsfw`, `sfw`",
+		content,
+	))
+}
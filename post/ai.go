@@ -0,0 +1,76 @@
+package post
+
+import (
+	"github.com/VTGare/boe-tea-go/artworks"
+	"github.com/VTGare/boe-tea-go/internal/dgoutils"
+	"github.com/VTGare/boe-tea-go/messages"
+	"github.com/VTGare/boe-tea-go/store"
+	"github.com/VTGare/embeds"
+	"github.com/bwmarrin/discordgo"
+)
+
+func containsAI(arts []artworks.Artwork) bool {
+	for _, artwork := range arts {
+		if artwork.AIGenerated() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func excludeAI(arts []artworks.Artwork) []artworks.Artwork {
+	filtered := make([]artworks.Artwork, 0, len(arts))
+	for _, artwork := range arts {
+		if !artwork.AIGenerated() {
+			filtered = append(filtered, artwork)
+		}
+	}
+
+	return filtered
+}
+
+// deleteAIMessage removes the original message with AI-generated artworks and tells the user why.
+// If the message can't be removed, false is returned and AI-generated artworks are only hidden.
+func (p *Post) deleteAIMessage(guild *store.Guild) bool {
+	var (
+		channelID = p.Ctx.Event.ChannelID
+		messageID = p.Ctx.Event.ID
+		log       = p.Bot.Log.With(
+			"guild_id", guild.ID,
+			"channel_id", channelID,
+			"message_id", messageID,
+		)
+	)
+
+	perm, err := dgoutils.MemberHasPermission(
+		p.Ctx.Session,
+		guild.ID,
+		p.Ctx.Session.State.User.ID,
+		discordgo.PermissionAdministrator|discordgo.PermissionManageMessages,
+	)
+	if err != nil {
+		log.With("error", err).Warn("failed to check delete message perms")
+	}
+
+	if !perm {
+		return false
+	}
+
+	if err := p.Ctx.Session.ChannelMessageDelete(channelID, messageID); err != nil {
+		log.With("error", err).Warn("failed to delete a message with ai-generated artworks")
+		return false
+	}
+
+	eb := embeds.NewBuilder()
+	eb.FailureTemplate(messages.AIDeleted())
+
+	msg, err := p.Ctx.Session.ChannelMessageSendEmbed(channelID, eb.Finalize())
+	if err != nil {
+		log.With("error", err).Warn("failed to send ai notice")
+		return true
+	}
+
+	dgoutils.ExpireMessage(p.Bot, p.Ctx.Session, msg)
+	return true
+}
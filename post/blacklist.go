@@ -0,0 +1,67 @@
+package post
+
+import (
+	"github.com/VTGare/boe-tea-go/artworks"
+	"github.com/VTGare/boe-tea-go/messages"
+	"github.com/VTGare/boe-tea-go/store"
+	"github.com/bwmarrin/discordgo"
+)
+
+// blacklistMode checks artwork's tags against the guild's blacklist and the blacklist of the user crossposting it.
+// If both blacklists match, suppressing the artwork takes precedence. Returns false if the artwork isn't blacklisted.
+func (p *Post) blacklistMode(guild *store.Guild, artwork artworks.Artwork) (store.BlacklistMode, bool) {
+	var (
+		tags        = artwork.Tags()
+		mode        store.BlacklistMode
+		blacklisted bool
+	)
+
+	for _, blacklist := range []store.Blacklist{guild.Blacklist, p.Blacklist} {
+		if !blacklist.Match(tags) {
+			continue
+		}
+
+		mode, blacklisted = blacklist.ModeOrDefault(), true
+		if mode == store.BlacklistSuppress {
+			break
+		}
+	}
+
+	return mode, blacklisted
+}
+
+// excludeBlacklisted removes artworks suppressed by blacklists.
+func (p *Post) excludeBlacklisted(guild *store.Guild, arts []artworks.Artwork) []artworks.Artwork {
+	filtered := make([]artworks.Artwork, 0, len(arts))
+	for _, artwork := range arts {
+		if mode, ok := p.blacklistMode(guild, artwork); !ok || mode != store.BlacklistSuppress {
+			filtered = append(filtered, artwork)
+		}
+	}
+
+	return filtered
+}
+
+// spoilerField returns the embed field explaining why images of the artwork are hidden behind spoilers.
+// Returns nil if the artwork isn't spoilered.
+func (p *Post) spoilerField(guild *store.Guild, artwork artworks.Artwork, policy store.NSFWPolicy) *discordgo.MessageEmbedField {
+	if mode, ok := p.blacklistMode(guild, artwork); ok && mode == store.BlacklistSpoiler {
+		return &discordgo.MessageEmbedField{Name: "⚠️ Blacklisted", Value: messages.BlacklistSpoiler()}
+	}
+
+	if policy == store.NSFWPolicySpoiler && artwork.IsNSFW() {
+		return &discordgo.MessageEmbedField{Name: "⚠️ NSFW", Value: messages.NSFWSpoiler()}
+	}
+
+	return nil
+}
+
+func (p *Post) anySpoilered(guild *store.Guild, arts []artworks.Artwork, policy store.NSFWPolicy) bool {
+	for _, artwork := range arts {
+		if p.spoilerField(guild, artwork, policy) != nil {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,277 @@
+package post
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/VTGare/boe-tea-go/bot"
+	"github.com/VTGare/boe-tea-go/internal/cache"
+	"github.com/VTGare/boe-tea-go/store"
+	"github.com/VTGare/embeds"
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// deliveryWorkers is a number of crosspost destinations delivered concurrently.
+	deliveryWorkers = 5
+	// deliveryAttempts is a number of attempts of every request to a destination.
+	deliveryAttempts = 3
+	// deliveryBackoff is a delay before the second attempt, it's doubled after every attempt.
+	deliveryBackoff = time.Second
+	// maxRetryAfter is the longest rate limit a delivery waits for. Longer rate limits fail the delivery.
+	maxRetryAfter = 10 * time.Second
+)
+
+// deliveryFailure is a crosspost destination that failed after all attempts.
+// Permanently failing destinations are removed from the crosspost group.
+type deliveryFailure struct {
+	channelID string
+	err       error
+	permanent bool
+	disabled  bool
+}
+
+// isPermanent reports whether retrying a request won't help, e.g. the channel was deleted or Boe Tea lost access to it.
+func isPermanent(err error) bool {
+	var restErr *discordgo.RESTError
+	if !errors.As(err, &restErr) || restErr.Message == nil {
+		return false
+	}
+
+	switch restErr.Message.Code {
+	case discordgo.ErrCodeUnknownChannel, discordgo.ErrCodeUnknownGuild, discordgo.ErrCodeMissingAccess:
+		return true
+	}
+
+	return false
+}
+
+// retryAfter returns how long Discord asked to wait before the next request.
+func retryAfter(err error) (time.Duration, bool) {
+	var rateLimitErr *discordgo.RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		return rateLimitErr.RetryAfter, true
+	}
+
+	var restErr *discordgo.RESTError
+	if errors.As(err, &restErr) && restErr.Response != nil && restErr.Response.StatusCode == http.StatusTooManyRequests {
+		seconds, err := strconv.ParseFloat(restErr.Response.Header.Get("Retry-After"), 64)
+		if err != nil {
+			return deliveryBackoff, true
+		}
+
+		return time.Duration(seconds * float64(time.Second)), true
+	}
+
+	return 0, false
+}
+
+// retry calls fn until it succeeds, fails permanently or runs out of attempts. Attempts are delayed
+// with exponential backoff, rate limited attempts wait for as long as Discord asks. Messages that
+// may have been sent by another process aren't retried to avoid duplicates.
+func retry[T any](ctx context.Context, fn func() (T, error)) (T, error) {
+	var (
+		res     T
+		err     error
+		backoff = deliveryBackoff
+	)
+
+	for attempt := 1; ; attempt++ {
+		res, err = fn()
+		if err == nil || isPermanent(err) || errors.Is(err, bot.ErrUnknownDelivery) || attempt == deliveryAttempts {
+			return res, err
+		}
+
+		delay := backoff
+		if after, ok := retryAfter(err); ok {
+			if after > maxRetryAfter {
+				return res, err
+			}
+
+			delay = after
+		}
+
+		select {
+		case <-time.After(delay):
+			backoff *= 2
+		case <-ctx.Done():
+			return res, errors.Join(err, ctx.Err())
+		}
+	}
+}
+
+// deliverAll delivers artworks to channels by a limited number of workers. Returns all sent messages
+// and channels that failed after all attempts.
+func (p *Post) deliverAll(ctx context.Context, channels []string, deliver func(channelID string) ([]*cache.MessageInfo, error)) ([]*cache.MessageInfo, []*deliveryFailure) {
+	type delivery struct {
+		sent    []*cache.MessageInfo
+		failure *deliveryFailure
+	}
+
+	var (
+		wg         = sync.WaitGroup{}
+		queue      = make(chan string)
+		deliveries = make(chan delivery, len(channels))
+	)
+
+	for range min(deliveryWorkers, len(channels)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for channelID := range queue {
+				sent, err := deliver(channelID)
+				if err != nil {
+					deliveries <- delivery{sent: sent, failure: &deliveryFailure{channelID: channelID, err: err, permanent: isPermanent(err)}}
+					continue
+				}
+
+				deliveries <- delivery{sent: sent}
+			}
+		}()
+	}
+
+	go func() {
+		for _, channelID := range channels {
+			queue <- channelID
+		}
+
+		close(queue)
+		wg.Wait()
+		close(deliveries)
+	}()
+
+	var (
+		sent     = make([]*cache.MessageInfo, 0)
+		failures = make([]*deliveryFailure, 0)
+	)
+
+	for d := range deliveries {
+		sent = append(sent, d.sent...)
+		if d.failure != nil {
+			failures = append(failures, d.failure)
+		}
+	}
+
+	return sent, failures
+}
+
+// deliver crossposts artworks of a user to a single channel.
+func (p *Post) deliver(ctx context.Context, userID string, channelID string) ([]*cache.MessageInfo, error) {
+	ch, err := retry(ctx, func() (*discordgo.Channel, error) {
+		return p.Ctx.Session.Channel(channelID)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get channel: %w", err)
+	}
+
+	if _, err := p.Ctx.Session.GuildMember(ch.GuildID, userID); err != nil {
+		return nil, errMemberLeft
+	}
+
+	return p.deliverChannel(ctx, ch)
+}
+
+// deliverChannel crossposts artworks to a channel if its guild allows crossposting to it.
+func (p *Post) deliverChannel(ctx context.Context, ch *discordgo.Channel) ([]*cache.MessageInfo, error) {
+	guild, err := p.Bot.Store.Guild(ctx, ch.GuildID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find guild: %w", err)
+	}
+
+	if !guild.Crosspost {
+		return nil, nil
+	}
+
+	if len(guild.ArtChannels) != 0 && !slices.Contains(guild.ArtChannels, ch.ID) {
+		return nil, nil
+	}
+
+	// Destinations are delivered concurrently, each of them gets its own copy of the post.
+	// The original post may be passed here too and must stay out of crosspost mode.
+	crosspost := *p
+	crosspost.CrosspostMode = true
+
+	res, err := crosspost.fetch(ctx, guild, ch.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch artworks: %w", err)
+	}
+
+	return crosspost.sendMessages(ctx, guild, ch.ID, res)
+}
+
+var errMemberLeft = errors.New("member left the server")
+
+// notifyFailures sends the user a direct message with failed crosspost destinations.
+func (p *Post) notifyFailures(user *store.User, group *store.Group, failures []*deliveryFailure) {
+	if !user.DM || len(failures) == 0 {
+		return
+	}
+
+	log := p.Bot.Log.With("user_id", user.ID, "group", group.Name)
+
+	dmSession, err := p.Bot.ShardManager.SessionForDM()
+	if err != nil {
+		log.With("error", err).Warn("failed to get a session")
+		return
+	}
+
+	ch, err := dmSession.UserChannelCreate(user.ID)
+	if err != nil {
+		log.With("error", err).Warn("failed to create private channel")
+		return
+	}
+
+	var sb strings.Builder
+	for _, failure := range failures {
+		sb.WriteString(fmt.Sprintf("<#%v> | `%v`: %v", failure.channelID, failure.channelID, failureReason(failure.err)))
+		if failure.disabled {
+			sb.WriteString(". Removed from the group")
+		}
+
+		sb.WriteString("\n")
+	}
+
+	eb := embeds.NewBuilder()
+	eb.Title("❎ Failed to crosspost to some channels").
+		Description(fmt.Sprintf(
+			"Boe Tea couldn't crosspost to the following channels of group `%v`.\n\n%v\nIf you dislike direct messages, disable them by running `bt!userset dm off` command",
+			group.Name, sb.String(),
+		))
+
+	if _, err := dmSession.ChannelMessageSendEmbed(ch.ID, eb.Finalize()); err != nil {
+		log.With("error", err).Warn("failed to send crosspost failures")
+	}
+}
+
+// failureReason returns a human-readable failure reason.
+func failureReason(err error) string {
+	var restErr *discordgo.RESTError
+	if errors.As(err, &restErr) && restErr.Message != nil {
+		switch restErr.Message.Code {
+		case discordgo.ErrCodeUnknownChannel:
+			return "channel doesn't exist anymore"
+		case discordgo.ErrCodeUnknownGuild:
+			return "server doesn't exist anymore"
+		case discordgo.ErrCodeMissingAccess:
+			return "Boe Tea has no access to the channel"
+		case discordgo.ErrCodeMissingPermissions:
+			return "Boe Tea has no permissions to send messages"
+		}
+
+		return restErr.Message.Message
+	}
+
+	if _, ok := retryAfter(err); ok {
+		return "rate limited by Discord"
+	}
+
+	return "Discord didn't respond, try again later"
+}
@@ -0,0 +1,44 @@
+package post
+
+import (
+	"context"
+
+	"github.com/VTGare/boe-tea-go/artworks"
+	"github.com/VTGare/boe-tea-go/store"
+)
+
+// filterURLs returns URLs of artworks passing the group filter. Artworks that failed to be found are kept,
+// so destinations report the error the same way as unfiltered groups.
+func (p *Post) filterURLs(ctx context.Context, filter store.GroupFilter) []string {
+	urls := make([]string, 0, len(p.Urls))
+	for _, url := range p.Urls {
+		if p.matchFilter(ctx, filter, url) {
+			urls = append(urls, url)
+		}
+	}
+
+	return urls
+}
+
+func (p *Post) matchFilter(ctx context.Context, filter store.GroupFilter, url string) bool {
+	for _, provider := range p.Bot.Providers.Providers() {
+		id, ok := provider.Match(url)
+		if !ok {
+			continue
+		}
+
+		artwork, err := p.findArtwork(ctx, provider, id)
+		if err != nil || artwork == nil {
+			return true
+		}
+
+		return matchArtwork(filter, artwork)
+	}
+
+	return true
+}
+
+// matchArtwork checks an artwork against the filter.
+func matchArtwork(filter store.GroupFilter, artwork artworks.Artwork) bool {
+	return filter.Match(artworks.ProviderName(artwork), artwork.IsNSFW(), artwork.AIGenerated(), artwork.Tags())
+}
@@ -0,0 +1,134 @@
+package post
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"time"
+
+	"github.com/VTGare/boe-tea-go/artworks"
+	"github.com/VTGare/boe-tea-go/internal/dgoutils"
+	"github.com/VTGare/boe-tea-go/messages"
+	"github.com/VTGare/boe-tea-go/store"
+	"github.com/VTGare/embeds"
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// maxSpoilerSize is the largest image re-uploaded as a spoiler attachment, Discord's upload limit
+	// of guilds without boosts. Larger images are only linked.
+	maxSpoilerSize = 10 << 20
+	// downloadTimeout bounds downloading an image, including reading the body.
+	downloadTimeout = 15 * time.Second
+)
+
+// imageClient downloads images to re-upload them. Image URLs come from artworks users posted,
+// so it refuses to connect to private addresses.
+var imageClient = newImageClient()
+
+func newImageClient() *http.Client {
+	client := artworks.NewPublicHTTPClient()
+	client.Timeout = downloadTimeout
+
+	return client
+}
+
+// nsfwPolicy returns the NSFW policy of a channel. Channels that can't be retrieved are treated as SFW.
+func (p *Post) nsfwPolicy(guild *store.Guild, channelID string) store.NSFWPolicy {
+	// Don't look the channel up if the guild allows NSFW artworks everywhere.
+	if guild.NSFWPolicyIn(false) == store.NSFWPolicyAllow {
+		return store.NSFWPolicyAllow
+	}
+
+	return guild.NSFWPolicyIn(p.nsfwChannel(channelID))
+}
+
+// nsfwChannel reports whether the channel is NSFW. Channels that can't be retrieved are treated as SFW.
+func (p *Post) nsfwChannel(channelID string) bool {
+	nsfw, err := dgoutils.IsNSFWChannel(p.Ctx.Session, channelID)
+	if err != nil {
+		p.Bot.Log.With("error", err, "channel_id", channelID).Warn("failed to check if channel is nsfw")
+	}
+
+	return nsfw
+}
+
+// spoilerMessage hides images of a message behind spoiler attachments and adds the field explaining why.
+// Images that fail to download are removed from the embed, the artwork is still reachable by its URL.
+func spoilerMessage(ctx context.Context, msg *discordgo.MessageSend, field *discordgo.MessageEmbedField) {
+	for _, file := range msg.Files {
+		file.Name = "SPOILER_" + file.Name
+	}
+
+	for _, embed := range msg.Embeds {
+		if embed.Image != nil {
+			if file, err := downloadImage(ctx, embed.Image.URL); err == nil {
+				file.Name = "SPOILER_" + file.Name
+				msg.Files = append(msg.Files, file)
+			}
+
+			embed.Image = nil
+		}
+
+		embed.Thumbnail = nil
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  field.Name,
+			Value: field.Value,
+		})
+	}
+}
+
+func downloadImage(ctx context.Context, imageURL string) (*discordgo.File, error) {
+	uri, err := url.Parse(imageURL)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, imageURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := imageClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response status: %v", resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSpoilerSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) > maxSpoilerSize {
+		return nil, fmt.Errorf("image is larger than %v bytes", maxSpoilerSize)
+	}
+
+	return &discordgo.File{
+		Name:        path.Base(uri.Path),
+		ContentType: resp.Header.Get("Content-Type"),
+		Reader:      bytes.NewReader(data),
+	}, nil
+}
+
+// notifyNSFWBlocked tells the user why their artworks weren't posted.
+func (p *Post) notifyNSFWBlocked(channelID string) {
+	eb := embeds.NewBuilder()
+	eb.FailureTemplate(messages.NSFWBlocked())
+
+	msg, err := p.Ctx.Session.ChannelMessageSendEmbed(channelID, eb.Finalize())
+	if err != nil {
+		p.Bot.Log.With("error", err, "channel_id", channelID).Warn("failed to send nsfw notice")
+		return
+	}
+
+	dgoutils.ExpireMessage(p.Bot, p.Ctx.Session, msg)
+}
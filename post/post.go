@@ -7,7 +7,6 @@ import (
 	"reflect"
 	"slices"
 	"sort"
-	"strconv"
 	"strings"
 	"sync"
 
@@ -43,9 +42,11 @@ type Post struct {
 	SkipMode       SkipMode
 	CrosspostMode  bool
 	ExcludeChannel bool
+	Blacklist      store.Blacklist
 }
 
 type fetchResult struct {
+	id      string
 	artwork artworks.Artwork
 	repost  *repost.Repost
 	index   int
@@ -56,6 +57,9 @@ type fetchResults struct {
 	artworks []artworks.Artwork
 	reposts  []*repost.Repost
 	matched  int
+	// ids are IDs the artworks were matched with. Reposts are keyed by them,
+	// while IDs of found artworks may differ, e.g. resolved short links.
+	ids map[artworks.Artwork]string
 }
 
 func New(bot *bot.Bot, gctx *gumi.Ctx, skip SkipMode, urls ...string) *Post {
@@ -71,28 +75,48 @@ func New(bot *bot.Bot, gctx *gumi.Ctx, skip SkipMode, urls ...string) *Post {
 }
 
 func (p *Post) Send(ctx context.Context) error {
+	sent, err := p.send(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(sent) < 1 {
+		return nil
+	}
+
+	p.cacheSent(ctx, sent)
+	return nil
+}
+
+// send posts artworks to the channel and crossposts them. Returns all sent messages.
+func (p *Post) send(ctx context.Context) ([]*cache.MessageInfo, error) {
 	guild, err := p.Bot.Store.Guild(ctx, p.Ctx.Event.GuildID)
 	if err != nil {
-		return fmt.Errorf("failed to get a guild: %w", err)
+		return nil, fmt.Errorf("failed to get a guild: %w", err)
 	}
 
 	user, err := p.Bot.Store.User(ctx, p.Ctx.Event.Author.ID)
 	if err != nil {
-		return fmt.Errorf("failed to get a user: %w", err)
+		return nil, fmt.Errorf("failed to get a user: %w", err)
 	}
 
 	if user.Ignore && p.Ctx.Command == nil {
-		return nil
+		return nil, nil
 	}
 
 	results, err := p.fetch(ctx, guild, p.Ctx.Event.ChannelID)
 	if err != nil {
-		return fmt.Errorf("failed to fetch artworks: %w", err)
+		return nil, fmt.Errorf("failed to fetch artworks: %w", err)
+	}
+
+	// The message is gone, there's nothing left to reply to or crosspost.
+	if guild.AIPolicyOrDefault() == store.AIPolicyDelete && containsAI(results.artworks) && p.deleteAIMessage(guild) {
+		return nil, nil
 	}
 
-	sent, err := p.sendMessages(guild, p.Ctx.Event.ChannelID, results.artworks)
+	sent, err := p.sendMessages(ctx, guild, p.Ctx.Event.ChannelID, results)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	allSent := make([]*cache.MessageInfo, 0)
@@ -100,55 +124,65 @@ func (p *Post) Send(ctx context.Context) error {
 
 	p.handleReposts(guild, results.reposts, results.matched)
 
+	// Channels crossposted to by user's group are skipped by the guild's group to avoid duplicates.
+	crossposted := make([]string, 0)
 	if group, ok := user.FindGroup(p.Ctx.Event.ChannelID); user.Crosspost && ok {
-		// If channels were successfully excluded, crosspost to trimmed channels.
-		// Otherwise, don't crosspost at all.
-		if p.ExcludeChannel {
-			excludedChannels := make(map[string]struct{})
-			for _, arg := range strings.Fields(p.Ctx.Args.Raw) {
-				id := dgoutils.TrimmerRaw(arg)
-				excludedChannels[id] = struct{}{}
-			}
-
-			filtered := arrays.Filter(group.Children, func(s string) bool {
-				_, ok := excludedChannels[s]
-				return !ok
-			})
+		group.Children = p.excludeChannels(group.Children)
 
-			if len(group.Children) > len(filtered) {
-				group.Children = filtered
-			}
+		sent, err = p.Crosspost(ctx, user.ID, group)
+		if err != nil {
+			return nil, err
 		}
+		allSent = append(allSent, sent...)
+		crossposted = group.Children
+	}
 
-		sent, err = p.Crosspost(ctx, user.ID, group)
+	if group, ok := guild.FindGroup(p.Ctx.Event.ChannelID); ok {
+		group.Children = arrays.Filter(p.excludeChannels(group.Children), func(s string) bool {
+			return !slices.Contains(crossposted, s)
+		})
+
+		sent, err = p.CrosspostGuild(ctx, guild.ID, group)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		allSent = append(allSent, sent...)
 	}
 
-	if len(allSent) < 1 {
-		return nil
+	return allSent, nil
+}
+
+// excludeChannels removes channels listed in command arguments if the post excludes channels.
+// If no channels were excluded, all channels are returned.
+func (p *Post) excludeChannels(channels []string) []string {
+	if !p.ExcludeChannel {
+		return channels
 	}
 
-	p.Bot.EmbedCache.Set(
+	excludedChannels := make(map[string]struct{})
+	for _, arg := range strings.Fields(p.Ctx.Args.Raw) {
+		id := dgoutils.TrimmerRaw(arg)
+		excludedChannels[id] = struct{}{}
+	}
+
+	return arrays.Filter(channels, func(s string) bool {
+		_, ok := excludedChannels[s]
+		return !ok
+	})
+}
+
+// cacheSent remembers the original message and all messages sent for it, so they can be removed later.
+func (p *Post) cacheSent(ctx context.Context, allSent []*cache.MessageInfo) {
+	err := p.Bot.EmbedCache.Set(
+		ctx,
 		p.Ctx.Event.Author.ID,
 		p.Ctx.Event.ChannelID,
 		p.Ctx.Event.ID,
-		true,
 		allSent...,
 	)
-
-	for _, msg := range allSent {
-		p.Bot.EmbedCache.Set(
-			p.Ctx.Event.Author.ID,
-			msg.ChannelID,
-			msg.MessageID,
-			false,
-		)
+	if err != nil {
+		p.Bot.Log.With("error", err).Warn("failed to cache sent messages")
 	}
-
-	return nil
 }
 
 func (p *Post) Crosspost(ctx context.Context, userID string, group *store.Group) ([]*cache.MessageInfo, error) {
@@ -165,74 +199,142 @@ func (p *Post) Crosspost(ctx context.Context, userID string, group *store.Group)
 		group.Children = arrays.Remove(group.Children, p.Ctx.Event.Message.ChannelID)
 	}
 
-	var (
-		wg      = sync.WaitGroup{}
-		msgChan = make(chan []*cache.MessageInfo, len(group.Children))
-	)
+	// User's blacklist only applies to their crossposts.
+	crosspost := *p
+	crosspost.Blacklist = user.Blacklist
+
+	p, ok := crosspost.filtered(ctx, group)
+	if !ok {
+		return []*cache.MessageInfo{}, nil
+	}
+
+	sent, failures := p.deliverAll(ctx, group.Children, func(channelID string) ([]*cache.MessageInfo, error) {
+		return p.deliver(ctx, userID, channelID)
+	})
 
-	wg.Add(len(group.Children))
-	for _, channelID := range group.Children {
+	reported := make([]*deliveryFailure, 0, len(failures))
+	for _, failure := range failures {
 		log := p.Bot.Log.With(
 			"user_id", userID,
 			"group", group,
-			"channel_id", channelID,
+			"channel_id", failure.channelID,
 		)
 
-		go func(channelID string) {
-			defer wg.Done()
-			ch, err := p.Ctx.Session.Channel(channelID)
-			if err != nil {
-				log.With("error", err).Info("failed to crosspost")
-				return
+		if errors.Is(failure.err, errMemberLeft) {
+			log.Debug("member left the server, removing crosspost channel")
+			if _, err := p.Bot.Store.DeleteCrosspostChannel(ctx, userID, group.Name, failure.channelID); err != nil {
+				log.With("error", err).Error("failed to remove a channel from user's group")
 			}
 
-			if _, err := p.Ctx.Session.GuildMember(ch.GuildID, userID); err != nil {
-				log.Debug("member left the server, removing crosspost channel")
-				if _, err := p.Bot.Store.DeleteCrosspostChannel(ctx, userID, group.Name, channelID); err != nil {
-					log.With("error", err).Error("failed to remove a channel from user's group")
-				}
+			continue
+		}
 
-				return
+		log.With("error", failure.err, "permanent", failure.permanent).Warn("failed to crosspost")
+		if failure.permanent {
+			if _, err := p.Bot.Store.DeleteCrosspostChannel(ctx, userID, group.Name, failure.channelID); err != nil {
+				log.With("error", err).Error("failed to remove a channel from user's group")
+			} else {
+				failure.disabled = true
 			}
+		}
 
-			guild, err := p.Bot.Store.Guild(ctx, ch.GuildID)
-			if err != nil {
-				log.With("error", err).Info("failed to find guild")
-				return
-			}
+		reported = append(reported, failure)
+	}
 
-			if guild.Crosspost {
-				if len(guild.ArtChannels) == 0 || slices.Contains(guild.ArtChannels, ch.ID) {
-					p.CrosspostMode = true
-					res, err := p.fetch(ctx, guild, channelID)
-					if err != nil {
-						log.With("error", err).Error("failed to fetch artworks")
-						return
-					}
+	p.notifyFailures(user, group, reported)
+	return sent, nil
+}
 
-					sent, err := p.sendMessages(guild, channelID, res.artworks)
-					if err != nil {
-						log.With("error", err).Error("failed to send messages")
-						return
-					}
+// CrosspostGuild crossposts artworks to children of a group managed by server admins.
+// Failures are only logged, permanently failing channels are removed from the group.
+func (p *Post) CrosspostGuild(ctx context.Context, guildID string, group *store.Group) ([]*cache.MessageInfo, error) {
+	p, ok := p.filtered(ctx, group)
+	if !ok {
+		return []*cache.MessageInfo{}, nil
+	}
 
-					msgChan <- sent
-				}
+	sent, failures := p.deliverAll(ctx, group.Children, func(channelID string) ([]*cache.MessageInfo, error) {
+		ch, err := retry(ctx, func() (*discordgo.Channel, error) {
+			return p.Ctx.Session.Channel(channelID)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get channel: %w", err)
+		}
+
+		return p.deliverChannel(ctx, ch)
+	})
+
+	for _, failure := range failures {
+		log := p.Bot.Log.With(
+			"guild_id", guildID,
+			"group", group,
+			"channel_id", failure.channelID,
+			"error", failure.err,
+		)
+
+		log.With("permanent", failure.permanent).Warn("failed to crosspost")
+		if failure.permanent {
+			if _, err := p.Bot.Store.DeleteGuildGroupChannel(ctx, guildID, group.Name, failure.channelID); err != nil {
+				log.With("error", err).Error("failed to remove a channel from guild's group")
 			}
-		}(channelID)
+		}
 	}
 
-	go func() {
-		wg.Wait()
-		close(msgChan)
-	}()
+	return sent, nil
+}
 
-	sent := make([]*cache.MessageInfo, 0)
-	for msg := range msgChan {
-		sent = append(sent, msg...)
+// filtered returns a copy of the post with artworks passing the group filter. Filters are evaluated once
+// for the group, every destination fetches only matching artworks. Returns false if nothing passed the filter.
+func (p *Post) filtered(ctx context.Context, group *store.Group) (*Post, bool) {
+	if group.Filter.Empty() {
+		return p, true
 	}
 
-	return sent, nil
+	filtered := *p
+	filtered.Urls = p.filterURLs(ctx, group.Filter)
+	return &filtered, len(filtered.Urls) != 0
+}
+
+// findArtwork returns a cached artwork or finds it using the provider and caches it.
+func (p *Post) findArtwork(ctx context.Context, provider artworks.Provider, id string) (artworks.Artwork, error) {
+	var (
+		log = p.Bot.Log.With("provider", reflect.TypeOf(provider), "artwork_id", id)
+		key = fmt.Sprintf("artworks:%T:%v", provider, id)
+	)
+
+	data, err := p.Bot.ArtworkCache.Get(ctx, key)
+	if err == nil {
+		artwork, err := artworks.UnmarshalEnvelope(data)
+		if err == nil {
+			return artwork, nil
+		}
+
+		log.With("error", err).Warn("failed to decode a cached artwork")
+	} else if !errors.Is(err, cache.ErrMiss) {
+		log.With("error", err).Warn("failed to get a cached artwork")
+	}
+
+	artwork, err := provider.Find(ctx, id)
+	// Requests cancelled by the caller say nothing about the provider's health.
+	if ctx.Err() == nil {
+		p.Bot.Providers.Report(provider, err)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err = artworks.MarshalEnvelope(artwork)
+	if err != nil {
+		log.With("error", err).Warn("failed to encode an artwork")
+		return artwork, nil
+	}
+
+	if err := p.Bot.ArtworkCache.Set(ctx, key, data, 0); err != nil {
+		log.With("error", err).Warn("failed to cache an artwork")
+	}
+
+	return artwork, nil
 }
 
 func (p *Post) fetch(ctx context.Context, guild *store.Guild, channelID string) (fetchResults, error) {
@@ -248,12 +350,17 @@ func (p *Post) fetch(ctx context.Context, guild *store.Guild, channelID string)
 
 	var wg sync.WaitGroup
 	for index, url := range p.Urls {
-		for _, provider := range p.Bot.ArtworkProviders {
+		for _, provider := range p.Bot.Providers.Providers() {
 			id, ok := provider.Match(url)
 			if !ok {
 				continue
 			}
 
+			// Explicit sources are never embedded in SFW channels, regardless of the NSFW policy.
+			if artworks.NSFWOnly(provider) && !p.nsfwChannel(channelID) {
+				break
+			}
+
 			// If this artwork ID was matched before, skip it.
 			if _, ok := matched[id]; ok {
 				break
@@ -277,6 +384,7 @@ func (p *Post) fetch(ctx context.Context, guild *store.Guild, channelID string)
 
 				log.Debug("matched a url")
 
+				var created bool
 				if guild.Repost != store.GuildRepostDisabled {
 					rep, err := p.Bot.RepostDetector.Find(ctx, channelID, id)
 					if err != nil && !errors.Is(err, repost.ErrNotFound) {
@@ -303,6 +411,8 @@ func (p *Post) fetch(ctx context.Context, guild *store.Guild, channelID string)
 						if err != nil {
 							log.With("error", err).Error("error creating a repost")
 						}
+
+						created = err == nil
 					}
 				}
 
@@ -313,22 +423,23 @@ func (p *Post) fetch(ctx context.Context, guild *store.Guild, channelID string)
 				// - The function is called from a command
 				// - Crossposting a Twitter artwork. Bypasses Guild settings by design.
 				if provider.Enabled(guild) || p.Ctx.Command != nil || (p.CrosspostMode && isTwitter) {
-					var (
-						artwork artworks.Artwork
-						key     = fmt.Sprintf("%T:%v", provider, id)
-					)
-
-					if i, ok := p.Bot.ArtworkCache.Get(key); ok {
-						artwork = i.(artworks.Artwork)
-					} else {
-						var err error
-						artwork, err = provider.Find(id)
-						if err != nil {
-							results <- fetchResult{err: err}
-							return
+					artwork, err := p.findArtwork(ctx, provider, id)
+					// The URL is of another site, it's neither an artwork nor a repost.
+					if errors.Is(err, artworks.ErrUnsupportedURL) {
+						log.Debug("ignored an unsupported url")
+						if created {
+							err := p.Bot.RepostDetector.Delete(ctx, channelID, id)
+							if err != nil && !errors.Is(err, repost.ErrNotFound) {
+								log.With("error", err).Error("failed to delete a repost")
+							}
 						}
 
-						p.Bot.ArtworkCache.Set(key, artwork, 0)
+						return
+					}
+
+					if err != nil {
+						results <- fetchResult{err: err}
+						return
 					}
 
 					// Only add reactions to the original message for Twitter links.
@@ -340,10 +451,10 @@ func (p *Post) fetch(ctx context.Context, guild *store.Guild, channelID string)
 					}
 
 					go func() {
-						p.Bot.Stats.IncrementArtwork(provider)
+						p.Bot.Stats.IncrementArtwork(p.Bot.Providers.Name(provider))
 					}()
 
-					results <- fetchResult{artwork: artwork, index: index}
+					results <- fetchResult{id: id, artwork: artwork, index: index}
 				}
 			}(ctx, index)
 
@@ -366,6 +477,7 @@ func (p *Post) fetch(ctx context.Context, guild *store.Guild, channelID string)
 	})
 
 	var (
+		ids      = make(map[artworks.Artwork]string)
 		artworks = make([]artworks.Artwork, 0)
 		reposts  = make([]*repost.Repost, 0)
 		errs     = make([]error, 0)
@@ -375,6 +487,7 @@ func (p *Post) fetch(ctx context.Context, guild *store.Guild, channelID string)
 		switch {
 		case res.artwork != nil:
 			artworks = append(artworks, res.artwork)
+			ids[res.artwork] = res.id
 		case res.repost != nil:
 			reposts = append(reposts, res.repost)
 		case res.err != nil:
@@ -386,6 +499,7 @@ func (p *Post) fetch(ctx context.Context, guild *store.Guild, channelID string)
 		artworks: artworks,
 		reposts:  reposts,
 		matched:  len(matched),
+		ids:      ids,
 	}, errors.Join(errs...)
 }
 
@@ -452,21 +566,56 @@ func (p *Post) handleReposts(guild *store.Guild, reposts []*repost.Repost, match
 	dgoutils.ExpireMessage(p.Bot, p.Ctx.Session, repostMessage)
 }
 
-func (p *Post) sendMessages(guild *store.Guild, channelID string, artworks []artworks.Artwork) ([]*cache.MessageInfo, error) {
+// sendMessages sends fetched artworks to the channel. Sent messages are tagged with IDs the artworks were matched with.
+func (p *Post) sendMessages(ctx context.Context, guild *store.Guild, channelID string, res fetchResults) ([]*cache.MessageInfo, error) {
+	artworks := res.artworks
 	sent := make([]*cache.MessageInfo, 0)
 	if len(artworks) == 0 {
 		return sent, nil
 	}
 
-	allMessages, err := p.generateMessages(guild, artworks)
+	if guild.HidesAI() {
+		artworks = excludeAI(artworks)
+	}
+
+	artworks = p.excludeBlacklisted(guild, artworks)
+	if len(artworks) == 0 {
+		return sent, nil
+	}
+
+	policy := p.nsfwPolicy(guild, channelID)
+	if policy == store.NSFWPolicyBlock {
+		allowed := artworks[:0:0]
+		for _, artwork := range artworks {
+			if !artwork.IsNSFW() {
+				allowed = append(allowed, artwork)
+			}
+		}
+
+		if len(allowed) < len(artworks) && !p.CrosspostMode {
+			p.notifyNSFWBlocked(channelID)
+		}
+
+		artworks = allowed
+		if len(artworks) == 0 {
+			return sent, nil
+		}
+	}
+
+	groups, err := p.generateMessages(ctx, guild, artworks, policy)
 	if err != nil {
 		return nil, err
 	}
 
-	if len(allMessages) == 0 {
+	if len(groups) == 0 {
 		return sent, nil
 	}
 
+	allMessages := make([][]*discordgo.MessageSend, 0, len(groups))
+	for _, group := range groups {
+		allMessages = append(allMessages, group.sends)
+	}
+
 	mediaCount := 0
 	for _, artwork := range artworks {
 		mediaCount += artwork.Len()
@@ -475,17 +624,20 @@ func (p *Post) sendMessages(guild *store.Guild, channelID string, artworks []art
 	// It only happens from commands so only first artwork should be affected.
 	allMessages[0] = p.skipArtworks(allMessages[0])
 	sendMessage := func(message *discordgo.MessageSend, artworkID string) error {
-		s := p.Ctx.Session
-		if p.CrosspostMode {
-			guildID, err := strconv.ParseInt(guild.ID, 10, 64)
-			if err != nil {
-				return fmt.Errorf("failed to parse guild id: %w", err)
-			}
+		var (
+			msg *discordgo.Message
+			err error
+		)
 
-			s = p.Bot.ShardManager.SessionForGuild(guildID)
+		// Crossposted guilds may belong to a shard running on another process.
+		// Crossposts are retried, the original channel is where the user is and can repost.
+		if p.CrosspostMode {
+			msg, err = retry(ctx, func() (*discordgo.Message, error) {
+				return p.Bot.SendComplex(ctx, guild.ID, channelID, message)
+			})
+		} else {
+			msg, err = p.Ctx.Session.ChannelMessageSendComplex(channelID, message)
 		}
-
-		msg, err := s.ChannelMessageSendComplex(channelID, message)
 		if err != nil {
 			return fmt.Errorf("failed to send message: %w", err)
 		}
@@ -494,10 +646,11 @@ func (p *Post) sendMessages(guild *store.Guild, channelID string, artworks []art
 
 		// If URL isn't set then it's an error embed.
 		// If media count equals 0, it's most likely a Tweet without images and can't be bookmarked.
+		// The message has been sent, failing to add reactions doesn't fail the delivery.
 		if guild.Reactions && len(message.Embeds) > 0 && message.Embeds[0].URL != "" && mediaCount != 0 {
 			err := p.addBookmarkReactions(msg)
 			if err != nil && !strings.Contains(err.Error(), "403") {
-				return fmt.Errorf("failed to add reactions: %w", err)
+				p.Bot.Log.With("error", err, "channel_id", channelID).Warn("failed to add reactions")
 			}
 		}
 
@@ -508,7 +661,13 @@ func (p *Post) sendMessages(guild *store.Guild, channelID string, artworks []art
 	if p.CrosspostMode {
 		first := allMessages[0][0]
 
-		first.Content = first.Embeds[0].URL + "\n" + first.Content
+		// Suppress the link preview, it would reveal spoilered images.
+		url := first.Embeds[0].URL
+		if p.anySpoilered(guild, artworks, policy) {
+			url = "<" + url + ">"
+		}
+
+		first.Content = url + "\n" + first.Content
 	}
 
 	log := p.Bot.Log.With(
@@ -517,20 +676,32 @@ func (p *Post) sendMessages(guild *store.Guild, channelID string, artworks []art
 		"crosspost", p.CrosspostMode,
 	)
 
+	// Crosspost failures are returned to report them to the user, failures in the original channel are only logged.
+	errs := make([]error, 0)
 	for i, messages := range allMessages {
 		for _, message := range messages {
-			err := sendMessage(message, artworks[i].ID())
+			err := sendMessage(message, res.ids[groups[i].artwork])
 			if err != nil {
-				log.With(err).Warn("failed to send artwork message")
+				log.With("error", err).Warn("failed to send artwork message")
+				if p.CrosspostMode {
+					errs = append(errs, err)
+				}
 			}
 		}
 	}
 
-	return sent, nil
+	return sent, errors.Join(errs...)
+}
+
+// messageGroup is messages of an artwork.
+type messageGroup struct {
+	artwork artworks.Artwork
+	sends   []*discordgo.MessageSend
 }
 
-func (p *Post) generateMessages(guild *store.Guild, artworks []artworks.Artwork) ([][]*discordgo.MessageSend, error) {
-	messageSends := make([][]*discordgo.MessageSend, 0, len(artworks))
+// generateMessages returns messages of every artwork. Artworks without messages are left out.
+func (p *Post) generateMessages(ctx context.Context, guild *store.Guild, artworks []artworks.Artwork, policy store.NSFWPolicy) ([]messageGroup, error) {
+	groups := make([]messageGroup, 0, len(artworks))
 	for _, artwork := range artworks {
 		if artwork != nil {
 			var quote string
@@ -567,13 +738,19 @@ func (p *Post) generateMessages(guild *store.Guild, artworks []artworks.Artwork)
 				}
 			}
 
+			if field := p.spoilerField(guild, artwork, policy); field != nil {
+				for _, msg := range sends {
+					spoilerMessage(ctx, msg, field)
+				}
+			}
+
 			if len(sends) > 0 {
-				messageSends = append(messageSends, sends)
+				groups = append(groups, messageGroup{artwork: artwork, sends: sends})
 			}
 		}
 	}
 
-	return messageSends, nil
+	return groups, nil
 }
 
 func (p *Post) skipArtworks(embeds []*discordgo.MessageSend) []*discordgo.MessageSend {
@@ -641,11 +818,10 @@ func (*Post) handleLimit(allMessages [][]*discordgo.MessageSend, limit int) [][]
 		return allMessages
 	}
 
-	filtered := make([][]*discordgo.MessageSend, 0, limit)
+	// Artworks without messages are kept to stay aligned with their message groups.
+	filtered := make([][]*discordgo.MessageSend, 0, len(allMessages))
 	for _, messages := range allMessages {
-		if len(messages) > 0 {
-			filtered = append(filtered, []*discordgo.MessageSend{messages[0]})
-		}
+		filtered = append(filtered, messages[:min(len(messages), 1)])
 	}
 
 	return filtered
@@ -1,12 +1,31 @@
 package post
 
 import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/VTGare/boe-tea-go/artworks"
 	"github.com/VTGare/boe-tea-go/artworks/pixiv"
 	"github.com/VTGare/boe-tea-go/artworks/twitter"
+	"github.com/VTGare/boe-tea-go/bot"
+	"github.com/VTGare/boe-tea-go/internal/bus"
+	"github.com/VTGare/boe-tea-go/internal/cache"
+	"github.com/VTGare/boe-tea-go/internal/config"
+	"github.com/VTGare/boe-tea-go/repost"
+	"github.com/VTGare/boe-tea-go/stats"
 	"github.com/VTGare/boe-tea-go/store"
+	"github.com/VTGare/boe-tea-go/store/memory"
 	"github.com/VTGare/gumi"
+	"github.com/servusdei2018/shards/v2"
+	"go.uber.org/zap"
 
 	"github.com/bwmarrin/discordgo"
 
@@ -23,7 +42,7 @@ var _ = Describe("Generate Messages Tests", func() {
 	var post Post
 
 	It("", func() {
-		post.generateMessages(nil, nil)
+		post.generateMessages(context.Background(), nil, nil, store.NSFWPolicyAllow)
 	})
 })
 
@@ -197,3 +216,441 @@ var _ = Describe("Skip Pages Tests", func() {
 		Expect(result).Should(HaveLen(0))
 	})
 })
+
+var _ = Describe("Crosspost delivery", func() {
+	var (
+		ctx        = context.Background()
+		rateLimit  = &discordgo.RateLimitError{RateLimit: &discordgo.RateLimit{TooManyRequests: &discordgo.TooManyRequests{RetryAfter: time.Millisecond}}}
+		missing    = &discordgo.RESTError{Message: &discordgo.APIErrorMessage{Code: discordgo.ErrCodeUnknownChannel}}
+		noPerms    = &discordgo.RESTError{Message: &discordgo.APIErrorMessage{Code: discordgo.ErrCodeMissingPermissions}}
+		longLimit  = &discordgo.RateLimitError{RateLimit: &discordgo.RateLimit{TooManyRequests: &discordgo.TooManyRequests{RetryAfter: time.Minute}}}
+		attemptsOf = func(errs ...error) (int, error) {
+			var attempts int
+			_, err := retry(ctx, func() (struct{}, error) {
+				attempts++
+				if attempts > len(errs) {
+					return struct{}{}, nil
+				}
+
+				return struct{}{}, errs[attempts-1]
+			})
+
+			return attempts, err
+		}
+	)
+
+	It("should retry after rate limits", func() {
+		attempts, err := attemptsOf(rateLimit, rateLimit)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(attempts).To(Equal(3))
+	})
+
+	It("should give up after all attempts", func() {
+		attempts, err := attemptsOf(rateLimit, rateLimit, rateLimit, rateLimit)
+		Expect(err).To(MatchError(rateLimit))
+		Expect(attempts).To(Equal(deliveryAttempts))
+	})
+
+	It("should not retry permanent failures", func() {
+		attempts, err := attemptsOf(missing)
+		Expect(err).To(MatchError(missing))
+		Expect(attempts).To(Equal(1))
+		Expect(isPermanent(fmt.Errorf("failed to send message: %w", err))).To(BeTrue())
+		Expect(isPermanent(noPerms)).To(BeFalse())
+	})
+
+	It("should not retry messages another process may have sent", func() {
+		unknown := fmt.Errorf("%w: %w", bot.ErrUnknownDelivery, context.DeadlineExceeded)
+
+		attempts, err := attemptsOf(unknown)
+		Expect(err).To(MatchError(bot.ErrUnknownDelivery))
+		Expect(attempts).To(Equal(1))
+	})
+
+	It("should not wait for long rate limits", func() {
+		attempts, err := attemptsOf(longLimit)
+		Expect(err).To(MatchError(longLimit))
+		Expect(attempts).To(Equal(1))
+	})
+})
+
+var _ = Describe("Crosspost filters", func() {
+	var (
+		nsfwArt = &pixiv.Artwork{NSFW: true, TagList: []string{"Original", "Landscape"}}
+		aiArt   = &twitter.Artwork{AI: true}
+	)
+
+	It("should pass everything with an empty filter", func() {
+		Expect(store.GroupFilter{}.Empty()).To(BeTrue())
+		Expect(matchArtwork(store.GroupFilter{}, nsfwArt)).To(BeTrue())
+		Expect(matchArtwork(store.GroupFilter{}, aiArt)).To(BeTrue())
+	})
+
+	It("should filter by provider", func() {
+		Expect(matchArtwork(store.GroupFilter{Providers: []string{"pixiv"}}, nsfwArt)).To(BeTrue())
+		Expect(matchArtwork(store.GroupFilter{Providers: []string{"pixiv"}}, aiArt)).To(BeFalse())
+		Expect(matchArtwork(store.GroupFilter{ExcludeProviders: []string{"pixiv"}}, nsfwArt)).To(BeFalse())
+	})
+
+	It("should filter by content", func() {
+		Expect(matchArtwork(store.GroupFilter{Content: store.GroupContentSFW}, nsfwArt)).To(BeFalse())
+		Expect(matchArtwork(store.GroupFilter{Content: store.GroupContentSFW}, aiArt)).To(BeTrue())
+		Expect(matchArtwork(store.GroupFilter{Content: store.GroupContentNSFW}, aiArt)).To(BeFalse())
+	})
+
+	It("should match tags case-insensitively", func() {
+		Expect(matchArtwork(store.GroupFilter{Tags: []string{"landscape"}}, nsfwArt)).To(BeTrue())
+		Expect(matchArtwork(store.GroupFilter{Tags: []string{"landscape"}}, aiArt)).To(BeFalse())
+		Expect(matchArtwork(store.GroupFilter{ExcludeTags: []string{"ORIGINAL"}}, nsfwArt)).To(BeFalse())
+	})
+
+	It("should exclude AI-generated artworks", func() {
+		Expect(matchArtwork(store.GroupFilter{ExcludeAI: true}, aiArt)).To(BeFalse())
+		Expect(matchArtwork(store.GroupFilter{ExcludeAI: true}, nsfwArt)).To(BeTrue())
+	})
+})
+
+var _ = Describe("NSFW policy", func() {
+	It("should allow NSFW artworks in NSFW channels and old guilds", func() {
+		guild := &store.Guild{NSFWPolicy: store.NSFWPolicyBlock}
+		Expect(guild.NSFWPolicyIn(true)).To(Equal(store.NSFWPolicyAllow))
+		Expect(guild.NSFWPolicyIn(false)).To(Equal(store.NSFWPolicyBlock))
+
+		Expect((&store.Guild{}).NSFWPolicyIn(false)).To(Equal(store.NSFWPolicyAllow))
+	})
+
+	// useImageServer lets images be downloaded from a local test server.
+	useImageServer := func(handler http.Handler) *httptest.Server {
+		srv := httptest.NewServer(handler)
+		DeferCleanup(srv.Close)
+
+		client := imageClient
+		imageClient = srv.Client()
+		DeferCleanup(func() { imageClient = client })
+
+		return srv
+	}
+
+	It("should move images to spoiler attachments", func() {
+		srv := useImageServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "image/png")
+			w.Write([]byte("png"))
+		}))
+
+		msg := &discordgo.MessageSend{
+			Embeds: []*discordgo.MessageEmbed{{Image: &discordgo.MessageEmbedImage{URL: srv.URL + "/img/1_p0.png?size=large"}}},
+			Files:  []*discordgo.File{{Name: "video.mp4"}},
+		}
+
+		spoilerMessage(context.Background(), msg, &discordgo.MessageEmbedField{Name: "⚠️ NSFW"})
+		Expect(msg.Embeds[0].Image).To(BeNil())
+		Expect(msg.Files).To(HaveLen(2))
+		Expect(msg.Files[0].Name).To(Equal("SPOILER_video.mp4"))
+		Expect(msg.Files[1].Name).To(Equal("SPOILER_1_p0.png"))
+	})
+
+	It("should drop images that failed to download", func() {
+		srv := useImageServer(http.NotFoundHandler())
+
+		msg := &discordgo.MessageSend{
+			Embeds: []*discordgo.MessageEmbed{{Image: &discordgo.MessageEmbedImage{URL: srv.URL + "/missing.png"}}},
+		}
+
+		spoilerMessage(context.Background(), msg, &discordgo.MessageEmbedField{Name: "⚠️ NSFW"})
+		Expect(msg.Embeds[0].Image).To(BeNil())
+		Expect(msg.Files).To(BeEmpty())
+	})
+
+	It("should not download images larger than the upload limit", func() {
+		srv := useImageServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Write(make([]byte, maxSpoilerSize+1))
+		}))
+
+		_, err := downloadImage(context.Background(), srv.URL+"/large.png")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should not download images from private addresses", func() {
+		srv := httptest.NewServer(http.NotFoundHandler())
+		defer srv.Close()
+
+		_, err := downloadImage(context.Background(), srv.URL+"/1.png")
+		Expect(err).To(MatchError(artworks.ErrPrivateAddress))
+	})
+})
+
+var _ = Describe("AI policy", func() {
+	var (
+		humanArt = &pixiv.Artwork{}
+		aiArt    = &pixiv.Artwork{AI: true}
+	)
+
+	It("should label AI-generated artworks in old guilds", func() {
+		Expect((&store.Guild{}).AIPolicyOrDefault()).To(Equal(store.AIPolicyLabel))
+		Expect((&store.Guild{}).HidesAI()).To(BeFalse())
+		Expect((&store.Guild{AIPolicy: store.AIPolicyHide}).HidesAI()).To(BeTrue())
+		Expect((&store.Guild{AIPolicy: store.AIPolicyDelete}).HidesAI()).To(BeTrue())
+	})
+
+	It("should exclude AI-generated artworks", func() {
+		arts := []artworks.Artwork{humanArt, aiArt}
+		Expect(containsAI(arts)).To(BeTrue())
+		Expect(excludeAI(arts)).To(Equal([]artworks.Artwork{humanArt}))
+		Expect(containsAI(excludeAI(arts))).To(BeFalse())
+	})
+})
+
+var _ = Describe("Tag blacklist", func() {
+	var (
+		gore      = &pixiv.Artwork{TagList: []string{"Original", "Guro"}}
+		landscape = &pixiv.Artwork{TagList: []string{"Landscape"}}
+	)
+
+	It("should add and remove tags case-insensitively", func() {
+		blacklist := store.Blacklist{}
+		Expect(blacklist.Add("guro", "GURO", "vore")).To(Equal(2))
+		Expect(blacklist.Match(gore.Tags())).To(BeTrue())
+		Expect(blacklist.Remove("Guro")).To(Equal(1))
+		Expect(blacklist.Tags).To(Equal([]string{"vore"}))
+		Expect(blacklist.ModeOrDefault()).To(Equal(store.BlacklistSuppress))
+	})
+
+	It("should suppress blacklisted artworks", func() {
+		p := &Post{}
+		guild := &store.Guild{Blacklist: store.Blacklist{Tags: []string{"guro"}}}
+
+		Expect(p.excludeBlacklisted(guild, []artworks.Artwork{gore, landscape})).To(Equal([]artworks.Artwork{landscape}))
+		Expect(p.spoilerField(guild, landscape, store.NSFWPolicyAllow)).To(BeNil())
+	})
+
+	It("should spoiler blacklisted artworks unless another blacklist suppresses them", func() {
+		p := &Post{}
+		guild := &store.Guild{Blacklist: store.Blacklist{Tags: []string{"guro"}, Mode: store.BlacklistSpoiler}}
+
+		Expect(p.excludeBlacklisted(guild, []artworks.Artwork{gore})).To(HaveLen(1))
+		Expect(p.spoilerField(guild, gore, store.NSFWPolicyAllow)).NotTo(BeNil())
+
+		p.Blacklist = store.Blacklist{Tags: []string{"original"}}
+		mode, ok := p.blacklistMode(guild, gore)
+		Expect(ok).To(BeTrue())
+		Expect(mode).To(Equal(store.BlacklistSuppress))
+	})
+
+	It("should use hashtags as tweet tags", func() {
+		tweet := &twitter.Artwork{Content: "New drawing! #Guro #art, https://t.co/abc #"}
+		Expect(tweet.Tags()).To(Equal([]string{"Guro", "art"}))
+	})
+})
+
+// fakeDiscord is a transport of a Discord session that answers every request successfully
+// and records sent and deleted messages. All channels belong to guild 1.
+type fakeDiscord struct {
+	mu      sync.Mutex
+	nextID  int
+	sent    []string
+	deleted []string
+}
+
+func (f *fakeDiscord) RoundTrip(req *http.Request) (*http.Response, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var (
+		parts = strings.Split(strings.TrimPrefix(req.URL.Path, "/api/v"+discordgo.APIVersion+"/"), "/")
+		body  = "{}"
+	)
+
+	switch {
+	case req.Method == http.MethodPost && len(parts) == 3 && parts[0] == "channels" && parts[2] == "messages":
+		f.nextID++
+		f.sent = append(f.sent, parts[1])
+		body = fmt.Sprintf(`{"id": "%v", "channel_id": "%v"}`, 1000+f.nextID, parts[1])
+	case req.Method == http.MethodDelete:
+		f.deleted = append(f.deleted, parts[len(parts)-1])
+	case req.Method == http.MethodGet && len(parts) == 2 && parts[0] == "channels":
+		body = fmt.Sprintf(`{"id": "%v", "guild_id": "1", "type": 0}`, parts[1])
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Status:     "200 OK",
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+func (f *fakeDiscord) Sent() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return slices.Clone(f.sent)
+}
+
+func (f *fakeDiscord) Deleted() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return slices.Clone(f.deleted)
+}
+
+// fakeProvider matches example.com links. Artwork IDs differ from match IDs, like resolved short links do.
+// IDs starting with "unsupported" are links of another site, IDs starting with "tweet" are tweets without media.
+type fakeProvider struct{}
+
+func (fakeProvider) Match(url string) (string, bool) {
+	return strings.CutPrefix(url, "https://example.com/")
+}
+
+func (fakeProvider) Find(_ context.Context, id string) (artworks.Artwork, error) {
+	if strings.HasPrefix(id, "unsupported") {
+		return nil, artworks.ErrUnsupportedURL
+	}
+
+	if strings.HasPrefix(id, "tweet") {
+		return &twitter.Artwork{
+			Source:    artworks.Source{SourceID: "artwork-" + id},
+			Content:   id,
+			Permalink: "https://example.com/" + id,
+		}, nil
+	}
+
+	return &pixiv.Artwork{
+		Source: artworks.Source{SourceID: "artwork-" + id, SourceURL: "https://example.com/" + id},
+		Title:  id,
+		Pages:  1,
+		Images: []*pixiv.Image{{Preview: "https://example.com/" + id + ".png"}},
+	}, nil
+}
+
+func (fakeProvider) Enabled(*store.Guild) bool {
+	return true
+}
+
+// newTestBot creates a bot of a single guild 1 talking to the fake Discord.
+func newTestBot(fake *fakeDiscord) (*bot.Bot, *discordgo.Session) {
+	ctx := context.Background()
+
+	session, err := discordgo.New("Bot token")
+	Expect(err).NotTo(HaveOccurred())
+	session.Client = &http.Client{Transport: fake}
+
+	db, err := memory.New("")
+	Expect(err).NotTo(HaveOccurred())
+
+	_, err = db.CreateGuild(ctx, "1")
+	Expect(err).NotTo(HaveOccurred())
+
+	providers := artworks.NewRegistry()
+	providers.Register("fake", fakeProvider{})
+
+	return &bot.Bot{
+		Log:            zap.NewNop().Sugar(),
+		Config:         &config.Config{},
+		Stats:          stats.New(&gumi.Router{}, providers.Names()),
+		Context:        ctx,
+		EmbedCache:     bot.NewEmbedCache(cache.NewMemory(time.Minute, time.Minute), db, time.Hour),
+		ArtworkCache:   cache.NewMemory(time.Minute, time.Minute),
+		Providers:      providers,
+		RepostDetector: repost.NewMemory(),
+		ShardManager:   &bot.ShardManager{ShardCount: 1, Shards: []*shards.Shard{{ID: 0, Session: session}}},
+		Bus:            bus.NewMemory(),
+		Store:          db,
+	}, session
+}
+
+func newTestCtx(session *discordgo.Session) *gumi.Ctx {
+	return &gumi.Ctx{
+		Session: session,
+		Event: &discordgo.MessageCreate{Message: &discordgo.Message{
+			ID:        "10",
+			ChannelID: "2",
+			GuildID:   "1",
+			Author:    &discordgo.User{ID: "3"},
+		}},
+	}
+}
+
+var _ = Describe("Send", func() {
+	ctx := context.Background()
+
+	It("should ignore unsupported URLs", func() {
+		fake := &fakeDiscord{}
+		b, session := newTestBot(fake)
+
+		err := New(b, newTestCtx(session), SkipModeNone, "https://example.com/1", "https://example.com/unsupported").Send(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fake.Sent()).To(Equal([]string{"2"}))
+
+		_, err = b.RepostDetector.Find(ctx, "2", "unsupported")
+		Expect(err).To(MatchError(repost.ErrNotFound))
+
+		_, err = b.RepostDetector.Find(ctx, "2", "1")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should tag embeds with match IDs of their links when an artwork is skipped", func() {
+		fake := &fakeDiscord{}
+		b, session := newTestBot(fake)
+
+		guild, err := b.Store.Guild(ctx, "1")
+		Expect(err).NotTo(HaveOccurred())
+
+		guild.SkipFirst = true
+		_, err = b.Store.UpdateGuild(ctx, guild)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(New(b, newTestCtx(session), SkipModeNone, "https://example.com/tweet", "https://example.com/1").Send(ctx)).To(Succeed())
+		Expect(fake.Sent()).To(Equal([]string{"2"}))
+
+		parent, ok := b.EmbedCache.Get(ctx, "2", "10")
+		Expect(ok).To(BeTrue())
+		Expect(parent.Children).To(HaveLen(1))
+		Expect(parent.Children[0].ArtworkID).To(Equal("1"))
+	})
+})
+
+var _ = Describe("Update", func() {
+	ctx := context.Background()
+
+	It("should keep embeds of unchanged links", func() {
+		fake := &fakeDiscord{}
+		b, session := newTestBot(fake)
+
+		Expect(New(b, newTestCtx(session), SkipModeNone, "https://example.com/1").Send(ctx)).To(Succeed())
+		Expect(fake.Sent()).To(Equal([]string{"2"}))
+
+		parent, ok := b.EmbedCache.Get(ctx, "2", "10")
+		Expect(ok).To(BeTrue())
+		Expect(parent.Children).To(HaveLen(1))
+		Expect(parent.Children[0].ArtworkID).To(Equal("1"))
+
+		err := New(b, newTestCtx(session), SkipModeNone, "https://example.com/1", "https://example.com/2").Update(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fake.Deleted()).To(BeEmpty())
+		Expect(fake.Sent()).To(Equal([]string{"2", "2"}))
+
+		parent, ok = b.EmbedCache.Get(ctx, "2", "10")
+		Expect(ok).To(BeTrue())
+		Expect(parent.Children).To(HaveLen(2))
+	})
+})
+
+var _ = Describe("Guild crosspost", func() {
+	ctx := context.Background()
+
+	It("should deliver to every channel without switching the original post to crosspost mode", func() {
+		fake := &fakeDiscord{}
+		b, session := newTestBot(fake)
+
+		channels := []string{"21", "22", "23", "24", "25", "26"}
+		group := &store.Group{Name: "art", Parent: "2", Children: channels}
+
+		p := New(b, newTestCtx(session), SkipModeNone, "https://example.com/1")
+		sent, err := p.CrosspostGuild(ctx, "1", group)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sent).To(HaveLen(len(channels)))
+		Expect(fake.Sent()).To(ConsistOf(channels))
+		Expect(p.CrosspostMode).To(BeFalse())
+	})
+})
@@ -0,0 +1,147 @@
+package post
+
+import (
+	"context"
+	"errors"
+
+	"github.com/VTGare/boe-tea-go/internal/cache"
+	"github.com/VTGare/boe-tea-go/repost"
+)
+
+// Update synchronises embeds with an edited message. Urls must contain all URLs of the edited message.
+// Embeds and crossposts of removed links are deleted with their repost entries, added links are posted
+// and crossposted as if they were in a new message.
+func (p *Post) Update(ctx context.Context) error {
+	var (
+		channelID = p.Ctx.Event.ChannelID
+		messageID = p.Ctx.Event.ID
+		log       = p.Bot.Log.With("channel_id", channelID, "message_id", messageID)
+		children  []*cache.MessageInfo
+	)
+
+	if parent, ok := p.Bot.EmbedCache.Get(ctx, channelID, messageID); ok {
+		if !parent.IsParent || parent.AuthorID != p.Ctx.Event.Author.ID {
+			return nil
+		}
+
+		children = parent.Children
+	}
+
+	current := p.match()
+
+	// Links that were posted before are known from the children. Links without embeds (e.g. reposts)
+	// are known from repost entries created for this message.
+	previous := make(map[string]struct{})
+	for _, child := range children {
+		previous[child.ArtworkID] = struct{}{}
+	}
+
+	for id := range current {
+		if _, ok := previous[id]; ok {
+			continue
+		}
+
+		rep, err := p.Bot.RepostDetector.Find(ctx, channelID, id)
+		if err == nil && rep.MessageID == messageID {
+			previous[id] = struct{}{}
+		}
+	}
+
+	var (
+		kept    = make([]*cache.MessageInfo, 0, len(children))
+		removed = make(map[string][]string)
+	)
+
+	for _, child := range children {
+		if _, ok := current[child.ArtworkID]; ok {
+			kept = append(kept, child)
+			continue
+		}
+
+		log.With("child_channel_id", child.ChannelID, "child_message_id", child.MessageID).Info("removing an embed of a removed link")
+		removed[child.ChannelID] = append(removed[child.ChannelID], child.MessageID)
+
+		if err := p.Bot.EmbedCache.Remove(ctx, child.ChannelID, child.MessageID); err != nil {
+			log.With("error", err).Warn("failed to remove a child message from embed cache")
+		}
+
+		if err := p.Bot.RepostDetector.Delete(ctx, child.ChannelID, child.ArtworkID); err != nil && !errors.Is(err, repost.ErrNotFound) {
+			log.With("error", err).Warn("failed to remove repost")
+		}
+	}
+
+	for childChannelID, messageIDs := range removed {
+		if err := p.deleteMessages(childChannelID, messageIDs); err != nil {
+			log.With("error", err, "child_channel_id", childChannelID).Warn("failed to delete removed embeds")
+		}
+	}
+
+	added := make([]string, 0)
+	for _, url := range p.Urls {
+		id, ok := p.matchURL(url)
+		if !ok {
+			continue
+		}
+
+		if _, ok := previous[id]; ok {
+			continue
+		}
+
+		previous[id] = struct{}{}
+		added = append(added, url)
+	}
+
+	sent := make([]*cache.MessageInfo, 0)
+	if len(added) > 0 {
+		p.Urls = added
+
+		var err error
+		if sent, err = p.send(ctx); err != nil {
+			return err
+		}
+	}
+
+	if len(removed) == 0 && len(sent) == 0 {
+		return nil
+	}
+
+	all := append(kept, sent...)
+	if len(all) == 0 {
+		return p.Bot.EmbedCache.Remove(ctx, channelID, messageID)
+	}
+
+	p.cacheSent(ctx, all)
+	return nil
+}
+
+// match returns artwork IDs of all URLs matched by artwork providers.
+func (p *Post) match() map[string]struct{} {
+	matched := make(map[string]struct{})
+	for _, url := range p.Urls {
+		if id, ok := p.matchURL(url); ok {
+			matched[id] = struct{}{}
+		}
+	}
+
+	return matched
+}
+
+// matchURL returns artwork ID of the first provider that matches the URL.
+func (p *Post) matchURL(url string) (string, bool) {
+	for _, provider := range p.Bot.Providers.Providers() {
+		if id, ok := provider.Match(url); ok {
+			return id, true
+		}
+	}
+
+	return "", false
+}
+
+// deleteMessages deletes messages in a channel. Discord doesn't allow to bulk delete a single message.
+func (p *Post) deleteMessages(channelID string, messageIDs []string) error {
+	if len(messageIDs) == 1 {
+		return p.Ctx.Session.ChannelMessageDelete(channelID, messageIDs[0])
+	}
+
+	return p.Ctx.Session.ChannelMessagesBulkDelete(channelID, messageIDs)
+}
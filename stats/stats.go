@@ -1,12 +1,9 @@
 package stats
 
 import (
-	"reflect"
 	"sort"
-	"strings"
 	"sync"
 
-	"github.com/VTGare/boe-tea-go/artworks"
 	"github.com/VTGare/gumi"
 	"go.uber.org/atomic"
 )
@@ -23,7 +20,8 @@ type Item struct {
 	Count int64
 }
 
-func New(router *gumi.Router, providers []artworks.Provider) *Stats {
+// New creates stats for router's commands and artwork providers by their registry names.
+func New(router *gumi.Router, providers []string) *Stats {
 	stats := &Stats{
 		Commands: map[string]*atomic.Int64{},
 		Artworks: map[string]*atomic.Int64{},
@@ -34,9 +32,7 @@ func New(router *gumi.Router, providers []artworks.Provider) *Stats {
 	}
 
 	for _, provider := range providers {
-		t := reflect.TypeOf(provider).String()
-		t = strings.Split(t, ".")[1]
-		stats.Artworks[t] = atomic.NewInt64(0)
+		stats.Artworks[provider] = atomic.NewInt64(0)
 	}
 
 	return stats
@@ -55,17 +51,14 @@ func (m *Stats) IncrementCommand(cmd string) {
 	count.Add(1)
 }
 
-func (m *Stats) IncrementArtwork(provider artworks.Provider) {
+func (m *Stats) IncrementArtwork(provider string) {
 	m.mut.Lock()
 	defer m.mut.Unlock()
 
-	t := reflect.TypeOf(provider).String()
-	t = strings.Split(t, ".")[1]
-
-	count, ok := m.Artworks[t]
+	count, ok := m.Artworks[provider]
 	if !ok {
 		count = atomic.NewInt64(0)
-		m.Commands[t] = count
+		m.Artworks[provider] = count
 	}
 
 	count.Add(1)
@@ -18,6 +18,7 @@ type Artwork struct {
 	URL       string    `json:"url" bson:"url"`
 	Images    []string  `json:"images" bson:"images"`
 	Favorites int       `json:"favourites" bson:"favourites"`
+	AI        bool      `json:"ai_generated" bson:"ai_generated"`
 	CreatedAt time.Time `json:"created_at" bson:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
 }
@@ -58,6 +59,8 @@ type ArtworkFilter struct {
 	Query  string `query:"query"`
 	URL    string `query:"url"`
 	Time   time.Duration
+	// ExcludeAI filters out AI-generated artworks. Like Time, it's ignored by ID, URL and query lookups.
+	ExcludeAI bool
 }
 
 func DefaultSearchOptions() ArtworkSearchOptions {
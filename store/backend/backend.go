@@ -0,0 +1,90 @@
+// Package backend opens the store.Store implementation selected in the configuration.
+package backend
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/VTGare/boe-tea-go/internal/config"
+	"github.com/VTGare/boe-tea-go/store"
+	"github.com/VTGare/boe-tea-go/store/memory"
+	"github.com/VTGare/boe-tea-go/store/mongo"
+	"github.com/VTGare/boe-tea-go/store/sql"
+)
+
+// Open connects to the configured store and initialises it. Mongo is used if store type is omitted.
+func Open(ctx context.Context, cfg *config.Config) (store.Store, error) {
+	var (
+		db  store.Store
+		err error
+	)
+
+	switch storeType := storeType(cfg); storeType {
+	case "mongo":
+		if cfg.Mongo == nil {
+			return nil, fmt.Errorf("mongo configuration is required for %v store", storeType)
+		}
+
+		db, err = mongo.New(ctx, cfg.Mongo.URI, cfg.Mongo.Database)
+	case "memory":
+		db, err = memory.New(cfg.Store.Path)
+	case sql.SQLite, sql.Postgres:
+		db, err = sql.New(ctx, storeType, cfg.Store.DSN)
+	default:
+		return nil, fmt.Errorf("unsupported store type: %v", storeType)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Init(ctx); err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// Describe identifies the configured store without credentials, e.g. "mongo:mongodb://localhost:27017/boetea".
+// Connection strings that aren't URLs may contain passwords, they're replaced with a short hash.
+func Describe(cfg *config.Config) string {
+	switch storeType := storeType(cfg); storeType {
+	case "mongo":
+		if cfg.Mongo == nil {
+			return storeType
+		}
+
+		return storeType + ":" + redact(cfg.Mongo.URI) + "/" + cfg.Mongo.Database
+	case "memory":
+		return storeType + ":" + cfg.Store.Path
+	default:
+		return storeType + ":" + redact(cfg.Store.DSN)
+	}
+}
+
+func storeType(cfg *config.Config) string {
+	if cfg.Store != nil && cfg.Store.Type != "" {
+		return cfg.Store.Type
+	}
+
+	return "mongo"
+}
+
+func redact(dsn string) string {
+	u, err := url.Parse(dsn)
+	if err != nil || u.Scheme == "" || u.Opaque != "" {
+		if !strings.Contains(dsn, "=") {
+			return dsn
+		}
+
+		sum := sha256.Sum256([]byte(dsn))
+		return hex.EncodeToString(sum[:6])
+	}
+
+	u.User = nil
+	u.RawQuery = ""
+	return u.String()
+}
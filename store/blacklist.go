@@ -0,0 +1,70 @@
+package store
+
+import (
+	"slices"
+	"strings"
+)
+
+// Blacklist hides artworks tagged with any of the blacklisted tags. Tags are matched case-insensitively.
+// Guild blacklists apply to artworks posted in the guild, user blacklists apply to artworks crossposted by the user.
+type Blacklist struct {
+	Tags []string      `json:"tags" bson:"tags"`
+	Mode BlacklistMode `json:"mode" bson:"mode"`
+}
+
+// BlacklistMode decides what happens to blacklisted artworks.
+type BlacklistMode string
+
+const (
+	// BlacklistSuppress doesn't embed blacklisted artworks.
+	BlacklistSuppress BlacklistMode = "suppress"
+	// BlacklistSpoiler hides images of blacklisted artworks behind spoilers.
+	BlacklistSpoiler BlacklistMode = "spoiler"
+)
+
+// ModeOrDefault returns the blacklist mode. Blacklists without a mode suppress artworks.
+func (b Blacklist) ModeOrDefault() BlacklistMode {
+	if b.Mode == "" {
+		return BlacklistSuppress
+	}
+
+	return b.Mode
+}
+
+// Match reports whether any of the tags is blacklisted.
+func (b Blacklist) Match(tags []string) bool {
+	return slices.ContainsFunc(tags, func(tag string) bool {
+		return hasTag(b.Tags, tag)
+	})
+}
+
+// Add adds tags to the blacklist skipping duplicates. It returns the number of added tags.
+func (b *Blacklist) Add(tags ...string) int {
+	added := 0
+	for _, tag := range tags {
+		if tag == "" || hasTag(b.Tags, tag) {
+			continue
+		}
+
+		b.Tags = append(b.Tags, tag)
+		added++
+	}
+
+	return added
+}
+
+// Remove removes tags from the blacklist. It returns the number of removed tags.
+func (b *Blacklist) Remove(tags ...string) int {
+	before := len(b.Tags)
+	b.Tags = slices.DeleteFunc(b.Tags, func(tag string) bool {
+		return hasTag(tags, tag)
+	})
+
+	return before - len(b.Tags)
+}
+
+func hasTag(tags []string, tag string) bool {
+	return slices.ContainsFunc(tags, func(t string) bool {
+		return strings.EqualFold(t, tag)
+	})
+}
@@ -11,6 +11,11 @@ type GuildStore interface {
 	UpdateGuild(ctx context.Context, guild *Guild) (*Guild, error)
 	AddArtChannels(ctx context.Context, guildID string, channels []string) (*Guild, error)
 	DeleteArtChannels(ctx context.Context, guildID string, channels []string) (*Guild, error)
+
+	CreateGuildGroup(ctx context.Context, guildID string, group *Group) (*Guild, error)
+	DeleteGuildGroup(ctx context.Context, guildID string, group string) (*Guild, error)
+	AddGuildGroupChannel(ctx context.Context, guildID string, group string, child string) (*Guild, error)
+	DeleteGuildGroupChannel(ctx context.Context, guildID string, group string, child string) (*Guild, error)
 }
 
 type Guild struct {
@@ -22,6 +27,14 @@ type Guild struct {
 	Deviant bool `json:"deviant" bson:"deviant"`
 	Bluesky bool `json:"bluesky" bson:"bluesky"`
 
+	// Provider toggles below are nil for guilds created before the settings were introduced.
+	// Use FanboxEnabled, ArtStationEnabled, MastodonEnabled, TumblrEnabled and NHentaiEnabled.
+	Fanbox     *bool `json:"fanbox" bson:"fanbox"`
+	ArtStation *bool `json:"artstation" bson:"artstation"`
+	Mastodon   *bool `json:"mastodon" bson:"mastodon"`
+	Tumblr     *bool `json:"tumblr" bson:"tumblr"`
+	NHentai    *bool `json:"nhentai" bson:"nhentai"`
+
 	Tags       bool `json:"tags" bson:"tags"`
 	FlavorText bool `json:"flavour_text" bson:"flavour_text"`
 	Crosspost  bool `json:"crosspost" bson:"crosspost"`
@@ -35,6 +48,16 @@ type Guild struct {
 	ArtChannels []string `json:"art_channels" bson:"art_channels" validate:"required"`
 	NSFW        bool     `json:"nsfw" bson:"nsfw"`
 
+	// NSFWPolicy applies to NSFW artworks posted, crossposted or listed in SFW channels.
+	NSFWPolicy NSFWPolicy `json:"nsfw_policy" bson:"nsfw_policy"`
+	// AIPolicy applies to AI-generated artworks posted, crossposted or listed on the leaderboard.
+	AIPolicy AIPolicy `json:"ai_policy" bson:"ai_policy"`
+	// Blacklist applies to artworks posted or crossposted in the guild.
+	Blacklist Blacklist `json:"blacklist" bson:"blacklist"`
+
+	// Groups are crosspost groups managed by server admins. Artworks posted in parent channels are crossposted for everyone.
+	Groups []*Group `json:"groups" bson:"crosspost_groups"`
+
 	CreatedAt time.Time `json:"created_at" bson:"created_at" validate:"required"`
 	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
 }
@@ -47,12 +70,60 @@ const (
 	GuildRepostStrict   GuildRepost = "strict"
 )
 
+// NSFWPolicy decides how NSFW artworks are posted in SFW channels.
+type NSFWPolicy string
+
+const (
+	NSFWPolicyAllow   NSFWPolicy = "allow"
+	NSFWPolicySpoiler NSFWPolicy = "spoiler"
+	NSFWPolicyBlock   NSFWPolicy = "block-in-sfw-channels"
+)
+
+// NSFWPolicyIn returns the policy for a channel. NSFW channels always allow NSFW artworks.
+// Guilds created before the setting was introduced allow them everywhere.
+func (g *Guild) NSFWPolicyIn(channelNSFW bool) NSFWPolicy {
+	if channelNSFW || g.NSFWPolicy == "" {
+		return NSFWPolicyAllow
+	}
+
+	return g.NSFWPolicy
+}
+
+// AIPolicy decides what happens to AI-generated artworks.
+type AIPolicy string
+
+const (
+	// AIPolicyLabel embeds AI-generated artworks with a disclaimer.
+	AIPolicyLabel AIPolicy = "label"
+	// AIPolicyHide doesn't embed AI-generated artworks.
+	AIPolicyHide AIPolicy = "hide"
+	// AIPolicyDelete removes messages with AI-generated artworks the same way as strict reposts.
+	AIPolicyDelete AIPolicy = "delete"
+)
+
+// AIPolicyOrDefault returns the AI policy of the guild. Guilds created before the setting was introduced label AI-generated artworks.
+func (g *Guild) AIPolicyOrDefault() AIPolicy {
+	if g.AIPolicy == "" {
+		return AIPolicyLabel
+	}
+
+	return g.AIPolicy
+}
+
+// HidesAI reports whether AI-generated artworks shouldn't be embedded in the guild.
+func (g *Guild) HidesAI() bool {
+	policy := g.AIPolicyOrDefault()
+	return policy == AIPolicyHide || policy == AIPolicyDelete
+}
+
 func DefaultGuild(id string) *Guild {
 	return &Guild{
 		ID:               id,
 		Prefix:           "bt!",
 		Limit:            10,
 		NSFW:             true,
+		NSFWPolicy:       NSFWPolicyAllow,
+		AIPolicy:         AIPolicyLabel,
 		Pixiv:            true,
 		Twitter:          true,
 		Deviant:          true,
@@ -65,6 +136,7 @@ func DefaultGuild(id string) *Guild {
 		Reactions:        false,
 		SkipFirst:        false,
 		ArtChannels:      make([]string, 0),
+		Groups:           make([]*Group, 0),
 		CreatedAt:        time.Now(),
 		UpdatedAt:        time.Now(),
 	}
@@ -76,6 +148,8 @@ func UserGuild() *Guild {
 		Prefix:           "bt!",
 		Limit:            100,
 		NSFW:             true,
+		NSFWPolicy:       NSFWPolicyAllow,
+		AIPolicy:         AIPolicyLabel,
 		Pixiv:            true,
 		Twitter:          true,
 		Deviant:          true,
@@ -89,3 +163,55 @@ func UserGuild() *Guild {
 		Reactions:        true,
 	}
 }
+
+// FanboxEnabled reports whether Fanbox and Fantia posts are embedded. Guilds created before the setting was introduced embed them.
+func (g *Guild) FanboxEnabled() bool {
+	return enabled(g.Fanbox)
+}
+
+// ArtStationEnabled reports whether ArtStation artworks are embedded. Guilds created before the setting was introduced embed them.
+func (g *Guild) ArtStationEnabled() bool {
+	return enabled(g.ArtStation)
+}
+
+// MastodonEnabled reports whether Mastodon posts are embedded. Guilds created before the setting was introduced embed them.
+func (g *Guild) MastodonEnabled() bool {
+	return enabled(g.Mastodon)
+}
+
+// TumblrEnabled reports whether Tumblr posts are embedded. Guilds created before the setting was introduced embed them.
+func (g *Guild) TumblrEnabled() bool {
+	return enabled(g.Tumblr)
+}
+
+// NHentaiEnabled reports whether nhentai galleries are embedded in NSFW channels.
+// Guilds created before the setting was introduced embed them.
+func (g *Guild) NHentaiEnabled() bool {
+	return enabled(g.NHentai)
+}
+
+// enabled returns the value of a provider toggle. Missing toggles are enabled, like they are in new guilds.
+func enabled(toggle *bool) bool {
+	return toggle == nil || *toggle
+}
+
+// FindGroup finds a guild group by its parent channel.
+func (g *Guild) FindGroup(channelID string) (*Group, bool) {
+	for _, group := range g.Groups {
+		if group.Parent == channelID {
+			return group, true
+		}
+	}
+
+	return nil, false
+}
+
+func (g *Guild) FindGroupByName(name string) (*Group, bool) {
+	for _, group := range g.Groups {
+		if group.Name == name {
+			return group, true
+		}
+	}
+
+	return nil, false
+}
@@ -0,0 +1,167 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/VTGare/boe-tea-go/store"
+)
+
+func (m *memoryStore) Artwork(_ context.Context, id int, url string) (*store.Artwork, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if id != 0 {
+		artwork, ok := m.data.Artworks[id]
+		if !ok || (url != "" && artwork.URL != url) {
+			return nil, store.ErrArtworkNotFound
+		}
+
+		return cloneArtwork(artwork), nil
+	}
+
+	for _, artwork := range m.data.Artworks {
+		if url == "" || artwork.URL == url {
+			return cloneArtwork(artwork), nil
+		}
+	}
+
+	return nil, store.ErrArtworkNotFound
+}
+
+func (m *memoryStore) CreateArtwork(_ context.Context, artwork *store.Artwork) (*store.Artwork, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.data.Counter++
+	artwork.ID = m.data.Counter
+	artwork.CreatedAt = time.Now()
+	artwork.UpdatedAt = time.Now()
+
+	m.data.Artworks[artwork.ID] = cloneArtwork(artwork)
+	if err := m.save(); err != nil {
+		return nil, err
+	}
+
+	return artwork, nil
+}
+
+func (m *memoryStore) SearchArtworks(_ context.Context, filter store.ArtworkFilter, opts ...store.ArtworkSearchOptions) ([]*store.Artwork, error) {
+	opt := store.DefaultSearchOptions()
+	if len(opts) != 0 {
+		opt = opts[0]
+	}
+
+	match, err := matcher(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	artworks := make([]*store.Artwork, 0)
+	for _, artwork := range m.data.Artworks {
+		if match(artwork) {
+			artworks = append(artworks, cloneArtwork(artwork))
+		}
+	}
+	m.mu.RUnlock()
+
+	slices.SortFunc(artworks, func(a, b *store.Artwork) int {
+		var c int
+		switch opt.Sort {
+		case store.ByPopularity:
+			c = cmp.Compare(a.Favorites, b.Favorites)
+		default:
+			c = a.CreatedAt.Compare(b.CreatedAt)
+		}
+
+		if c == 0 {
+			c = cmp.Compare(a.ID, b.ID)
+		}
+
+		if opt.Order == store.Descending {
+			return -c
+		}
+
+		return c
+	})
+
+	if opt.Skip >= int64(len(artworks)) {
+		return make([]*store.Artwork, 0), nil
+	}
+
+	artworks = artworks[opt.Skip:]
+	if opt.Limit > 0 && opt.Limit < int64(len(artworks)) {
+		artworks = artworks[:opt.Limit]
+	}
+
+	return artworks, nil
+}
+
+// matcher builds a predicate equivalent to Mongo store's artwork filter.
+func matcher(f store.ArtworkFilter) (func(*store.Artwork) bool, error) {
+	regex := func(value string) (*regexp.Regexp, error) {
+		re, err := regexp.Compile("(?i)" + value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid search query: %w", err)
+		}
+
+		return re, nil
+	}
+
+	switch {
+	case len(f.IDs) != 0:
+		return func(a *store.Artwork) bool { return slices.Contains(f.IDs, a.ID) }, nil
+	case f.URL != "":
+		return func(a *store.Artwork) bool { return a.URL == f.URL }, nil
+	case f.Query != "":
+		re, err := regex(f.Query)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(a *store.Artwork) bool { return re.MatchString(a.Author) || re.MatchString(a.Title) }, nil
+	}
+
+	var (
+		author, title *regexp.Regexp
+		err           error
+	)
+
+	if f.Author != "" {
+		if author, err = regex(f.Author); err != nil {
+			return nil, err
+		}
+	}
+
+	if f.Title != "" {
+		if title, err = regex(f.Title); err != nil {
+			return nil, err
+		}
+	}
+
+	since := time.Now().Add(-f.Time)
+	return func(a *store.Artwork) bool {
+		if author != nil && !author.MatchString(a.Author) {
+			return false
+		}
+
+		if title != nil && !title.MatchString(a.Title) {
+			return false
+		}
+
+		if f.Time != 0 && a.CreatedAt.Before(since) {
+			return false
+		}
+
+		if f.ExcludeAI && a.AI {
+			return false
+		}
+
+		return true
+	}, nil
+}
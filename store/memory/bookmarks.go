@@ -0,0 +1,99 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/VTGare/boe-tea-go/store"
+)
+
+func (m *memoryStore) ListBookmarks(_ context.Context, userID string, filter store.BookmarkFilter, order store.Order) ([]*store.Bookmark, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	bookmarks := make([]*store.Bookmark, 0)
+	for _, bookmark := range m.data.Bookmarks {
+		if bookmark.UserID != userID {
+			continue
+		}
+
+		if filter != store.BookmarkFilterAll && bookmark.NSFW != (filter == store.BookmarkFilterUnsafe) {
+			continue
+		}
+
+		clone := *bookmark
+		bookmarks = append(bookmarks, &clone)
+	}
+
+	slices.SortStableFunc(bookmarks, func(a, b *store.Bookmark) int {
+		if order == store.Descending {
+			return b.CreatedAt.Compare(a.CreatedAt)
+		}
+
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	return bookmarks, nil
+}
+
+func (m *memoryStore) CountBookmarks(_ context.Context, userID string) (int64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var count int64
+	for _, bookmark := range m.data.Bookmarks {
+		if bookmark.UserID == userID {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (m *memoryStore) AddBookmark(_ context.Context, bookmark *store.Bookmark) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if slices.ContainsFunc(m.data.Bookmarks, sameBookmark(bookmark)) {
+		return false, nil
+	}
+
+	clone := *bookmark
+	m.data.Bookmarks = append(m.data.Bookmarks, &clone)
+	if artwork, ok := m.data.Artworks[bookmark.ArtworkID]; ok {
+		artwork.Favorites++
+	}
+
+	if err := m.save(); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (m *memoryStore) DeleteBookmark(_ context.Context, bookmark *store.Bookmark) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	idx := slices.IndexFunc(m.data.Bookmarks, sameBookmark(bookmark))
+	if idx == -1 {
+		return false, nil
+	}
+
+	m.data.Bookmarks = slices.Delete(m.data.Bookmarks, idx, idx+1)
+	if artwork, ok := m.data.Artworks[bookmark.ArtworkID]; ok {
+		artwork.Favorites--
+	}
+
+	if err := m.save(); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func sameBookmark(bookmark *store.Bookmark) func(*store.Bookmark) bool {
+	return func(b *store.Bookmark) bool {
+		return b.UserID == bookmark.UserID && b.ArtworkID == bookmark.ArtworkID
+	}
+}
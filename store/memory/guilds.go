@@ -0,0 +1,180 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/VTGare/boe-tea-go/store"
+)
+
+func (m *memoryStore) Guild(_ context.Context, id string) (*store.Guild, error) {
+	// If guild ID is empty, return DM guild settings.
+	if id == "" {
+		return store.UserGuild(), nil
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	guild, ok := m.data.Guilds[id]
+	if !ok {
+		return nil, fmt.Errorf("guild %v: %w", id, store.ErrNotFound)
+	}
+
+	return cloneGuild(guild), nil
+}
+
+func (m *memoryStore) CreateGuild(_ context.Context, id string) (*store.Guild, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.data.Guilds[id]; ok {
+		return nil, fmt.Errorf("guild %v already exists", id)
+	}
+
+	guild := store.DefaultGuild(id)
+	m.data.Guilds[id] = cloneGuild(guild)
+	if err := m.save(); err != nil {
+		return nil, err
+	}
+
+	return guild, nil
+}
+
+func (m *memoryStore) UpdateGuild(_ context.Context, guild *store.Guild) (*store.Guild, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	guild.UpdatedAt = time.Now()
+	if _, ok := m.data.Guilds[guild.ID]; !ok {
+		return guild, nil
+	}
+
+	m.data.Guilds[guild.ID] = cloneGuild(guild)
+	if err := m.save(); err != nil {
+		return nil, err
+	}
+
+	return guild, nil
+}
+
+func (m *memoryStore) AddArtChannels(_ context.Context, guildID string, channels []string) (*store.Guild, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	guild, ok := m.data.Guilds[guildID]
+	if !ok || slices.ContainsFunc(channels, func(c string) bool { return slices.Contains(guild.ArtChannels, c) }) {
+		return nil, fmt.Errorf("guild %v: %w", guildID, store.ErrNotFound)
+	}
+
+	for _, channel := range channels {
+		if !slices.Contains(guild.ArtChannels, channel) {
+			guild.ArtChannels = append(guild.ArtChannels, channel)
+		}
+	}
+
+	if err := m.save(); err != nil {
+		return nil, err
+	}
+
+	return cloneGuild(guild), nil
+}
+
+func (m *memoryStore) DeleteArtChannels(_ context.Context, guildID string, channels []string) (*store.Guild, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	guild, ok := m.data.Guilds[guildID]
+	if !ok || slices.ContainsFunc(channels, func(c string) bool { return !slices.Contains(guild.ArtChannels, c) }) {
+		return nil, fmt.Errorf("guild %v: %w", guildID, store.ErrNotFound)
+	}
+
+	guild.ArtChannels = slices.DeleteFunc(guild.ArtChannels, func(c string) bool {
+		return slices.Contains(channels, c)
+	})
+
+	if err := m.save(); err != nil {
+		return nil, err
+	}
+
+	return cloneGuild(guild), nil
+}
+
+func (m *memoryStore) CreateGuildGroup(_ context.Context, guildID string, group *store.Group) (*store.Guild, error) {
+	return m.updateGuild(guildID, func(g *store.Guild) bool {
+		if _, ok := g.FindGroupByName(group.Name); ok {
+			return false
+		}
+
+		if _, ok := g.FindGroup(group.Parent); ok {
+			return false
+		}
+
+		g.Groups = append(g.Groups, cloneGroup(group))
+		return true
+	})
+}
+
+func (m *memoryStore) DeleteGuildGroup(_ context.Context, guildID, group string) (*store.Guild, error) {
+	return m.updateGuild(guildID, func(g *store.Guild) bool {
+		if _, ok := g.FindGroupByName(group); !ok {
+			return false
+		}
+
+		g.Groups = slices.DeleteFunc(g.Groups, func(gr *store.Group) bool { return gr.Name == group })
+		return true
+	})
+}
+
+func (m *memoryStore) AddGuildGroupChannel(_ context.Context, guildID, group, child string) (*store.Guild, error) {
+	return m.updateGuild(guildID, func(g *store.Guild) bool {
+		gr, ok := g.FindGroupByName(group)
+		if !ok {
+			return false
+		}
+
+		if !slices.Contains(gr.Children, child) {
+			gr.Children = append(gr.Children, child)
+		}
+
+		return true
+	})
+}
+
+func (m *memoryStore) DeleteGuildGroupChannel(_ context.Context, guildID, group, child string) (*store.Guild, error) {
+	return m.updateGuild(guildID, func(g *store.Guild) bool {
+		gr, ok := g.FindGroupByName(group)
+		if !ok {
+			return false
+		}
+
+		gr.Children = slices.DeleteFunc(gr.Children, func(c string) bool { return c == child })
+		return true
+	})
+}
+
+// updateGuild applies fn to a copy of the guild and commits it if fn returns true.
+// Otherwise, store.ErrNotFound is returned the same way Mongo's conditional updates don't match any documents.
+func (m *memoryStore) updateGuild(guildID string, fn func(*store.Guild) bool) (*store.Guild, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	guild, ok := m.data.Guilds[guildID]
+	if !ok {
+		return nil, fmt.Errorf("guild %v: %w", guildID, store.ErrNotFound)
+	}
+
+	guild = cloneGuild(guild)
+	if !fn(guild) {
+		return nil, fmt.Errorf("guild %v: %w", guildID, store.ErrNotFound)
+	}
+
+	m.data.Guilds[guildID] = guild
+	if err := m.save(); err != nil {
+		return nil, err
+	}
+
+	return cloneGuild(guild), nil
+}
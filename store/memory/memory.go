@@ -0,0 +1,192 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/VTGare/boe-tea-go/store"
+)
+
+// memoryStore keeps all data in memory. If path is set, the data is loaded from
+// and persisted to a JSON file after every write. Posts are written for every message with artworks,
+// so their writes are persisted in batches by saveLater instead.
+type memoryStore struct {
+	mu   sync.RWMutex
+	path string
+	data *data
+	// pending is a scheduled save, nil if there isn't one.
+	pending *time.Timer
+}
+
+// saveDelay is how long saveLater waits before persisting the data.
+const saveDelay = 10 * time.Second
+
+// data is the persisted state of the memory store.
+type data struct {
+	// Counter mirrors Mongo's "counters" collection and is used to auto-increment artwork IDs.
+	Counter   int                     `json:"counter"`
+	Artworks  map[int]*store.Artwork  `json:"artworks"`
+	Guilds    map[string]*store.Guild `json:"guilds"`
+	Users     map[string]*store.User  `json:"users"`
+	Bookmarks []*store.Bookmark       `json:"bookmarks"`
+	Posts     map[string]*store.Post  `json:"posts"`
+}
+
+// New creates an in-memory store. An empty path keeps the data in memory only.
+func New(path string) (store.Store, error) {
+	m := &memoryStore{
+		path: path,
+		data: &data{
+			Artworks:  make(map[int]*store.Artwork),
+			Guilds:    make(map[string]*store.Guild),
+			Users:     make(map[string]*store.User),
+			Bookmarks: make([]*store.Bookmark, 0),
+			Posts:     make(map[string]*store.Post),
+		},
+	}
+
+	if path == "" {
+		return m, nil
+	}
+
+	file, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return m, nil
+		}
+
+		return nil, fmt.Errorf("failed to read store file: %w", err)
+	}
+
+	if err := json.Unmarshal(file, m.data); err != nil {
+		return nil, fmt.Errorf("failed to decode store file: %w", err)
+	}
+
+	// Files saved before posts were introduced don't have them.
+	if m.data.Posts == nil {
+		m.data.Posts = make(map[string]*store.Post)
+	}
+
+	return m, nil
+}
+
+func (m *memoryStore) Init(context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.save()
+}
+
+func (m *memoryStore) Close(context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.save()
+}
+
+// save writes the data to the file atomically. Must be called with the write lock held.
+func (m *memoryStore) save() error {
+	if m.path == "" {
+		return nil
+	}
+
+	if m.pending != nil {
+		m.pending.Stop()
+		m.pending = nil
+	}
+
+	file, err := json.Marshal(m.data)
+	if err != nil {
+		return fmt.Errorf("failed to encode store: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(m.path), filepath.Base(m.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create store file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(file); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write store file: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write store file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), m.path); err != nil {
+		return fmt.Errorf("failed to replace store file: %w", err)
+	}
+
+	return nil
+}
+
+// saveLater schedules a save if there isn't one already. Failed saves are retried by the next save
+// and Close. Must be called with the write lock held.
+func (m *memoryStore) saveLater() {
+	if m.path == "" || m.pending != nil {
+		return
+	}
+
+	var timer *time.Timer
+	timer = time.AfterFunc(saveDelay, func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		// The data has been saved since the timer fired.
+		if m.pending != timer {
+			return
+		}
+
+		_ = m.save()
+	})
+
+	m.pending = timer
+}
+
+func cloneArtwork(a *store.Artwork) *store.Artwork {
+	clone := *a
+	clone.Images = slices.Clone(a.Images)
+	return &clone
+}
+
+func cloneFilter(f store.GroupFilter) store.GroupFilter {
+	f.Providers = slices.Clone(f.Providers)
+	f.ExcludeProviders = slices.Clone(f.ExcludeProviders)
+	f.Tags = slices.Clone(f.Tags)
+	f.ExcludeTags = slices.Clone(f.ExcludeTags)
+	return f
+}
+
+func cloneGuild(g *store.Guild) *store.Guild {
+	clone := *g
+	clone.ArtChannels = slices.Clone(g.ArtChannels)
+	clone.Blacklist.Tags = slices.Clone(g.Blacklist.Tags)
+	if g.Groups != nil {
+		clone.Groups = make([]*store.Group, 0, len(g.Groups))
+		for _, group := range g.Groups {
+			clone.Groups = append(clone.Groups, cloneGroup(group))
+		}
+	}
+
+	return &clone
+}
+
+func cloneUser(u *store.User) *store.User {
+	clone := *u
+	clone.Blacklist.Tags = slices.Clone(u.Blacklist.Tags)
+	clone.Groups = make([]*store.Group, 0, len(u.Groups))
+	for _, group := range u.Groups {
+		clone.Groups = append(clone.Groups, cloneGroup(group))
+	}
+
+	return &clone
+}
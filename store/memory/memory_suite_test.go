@@ -0,0 +1,97 @@
+package memory_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/VTGare/boe-tea-go/store"
+	"github.com/VTGare/boe-tea-go/store/memory"
+	"github.com/VTGare/boe-tea-go/store/storetest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestMemory(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Memory Store Suite")
+}
+
+var _ = Describe("In-memory store", func() {
+	storetest.Conformance(func() store.Store {
+		s, err := memory.New("")
+		Expect(err).NotTo(HaveOccurred())
+
+		return s
+	})
+})
+
+var _ = Describe("File-backed store", func() {
+	storetest.Conformance(func() store.Store {
+		s, err := memory.New(filepath.Join(GinkgoT().TempDir(), "store.json"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Init(context.Background())).To(Succeed())
+
+		return s
+	})
+
+	It("should persist data between restarts", func() {
+		ctx := context.Background()
+		path := filepath.Join(GinkgoT().TempDir(), "store.json")
+
+		s, err := memory.New(path)
+		Expect(err).NotTo(HaveOccurred())
+
+		artwork, err := s.CreateArtwork(ctx, &store.Artwork{URL: "https://example.com"})
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = s.CreateGuild(ctx, "1")
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = s.AddBookmark(ctx, &store.Bookmark{UserID: "1", ArtworkID: artwork.ID})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Close(ctx)).To(Succeed())
+
+		s, err = memory.New(path)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = s.Guild(ctx, "1")
+		Expect(err).NotTo(HaveOccurred())
+
+		artwork, err = s.Artwork(ctx, artwork.ID, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(artwork.Favorites).To(Equal(1))
+
+		next, err := s.CreateArtwork(ctx, &store.Artwork{URL: "https://example.com/2"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(next.ID).To(Equal(artwork.ID + 1))
+	})
+
+	It("should persist posts in batches", func() {
+		ctx := context.Background()
+		path := filepath.Join(GinkgoT().TempDir(), "store.json")
+
+		s, err := memory.New(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Init(ctx)).To(Succeed())
+
+		post := &store.Post{ChannelID: "1", MessageID: "2", AuthorID: "3", ExpiresAt: time.Now().Add(time.Hour)}
+		Expect(s.SavePosts(ctx, post)).To(Succeed())
+
+		// The file isn't rewritten for every post.
+		file, err := os.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(file)).NotTo(ContainSubstring(`"author_id":"3"`))
+
+		Expect(s.Close(ctx)).To(Succeed())
+
+		s, err = memory.New(path)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = s.Post(ctx, "1", "2")
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
@@ -0,0 +1,172 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"maps"
+	"slices"
+
+	"github.com/VTGare/boe-tea-go/store"
+)
+
+func (m *memoryStore) ExportGuilds(_ context.Context, after string, limit int) ([]*store.Guild, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	guilds := make([]*store.Guild, 0, limit)
+	for _, id := range page(slices.Sorted(maps.Keys(m.data.Guilds)), after, limit) {
+		guilds = append(guilds, cloneGuild(m.data.Guilds[id]))
+	}
+
+	return guilds, nil
+}
+
+func (m *memoryStore) ExportUsers(_ context.Context, after string, limit int) ([]*store.User, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	users := make([]*store.User, 0, limit)
+	for _, id := range page(slices.Sorted(maps.Keys(m.data.Users)), after, limit) {
+		users = append(users, cloneUser(m.data.Users[id]))
+	}
+
+	return users, nil
+}
+
+func (m *memoryStore) ExportArtworks(_ context.Context, after int, limit int) ([]*store.Artwork, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	artworks := make([]*store.Artwork, 0, limit)
+	for _, id := range page(slices.Sorted(maps.Keys(m.data.Artworks)), after, limit) {
+		artworks = append(artworks, cloneArtwork(m.data.Artworks[id]))
+	}
+
+	return artworks, nil
+}
+
+func (m *memoryStore) ExportBookmarks(_ context.Context, after store.BookmarkKey, limit int) ([]*store.Bookmark, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	bookmarks := make([]*store.Bookmark, 0)
+	for _, bookmark := range m.data.Bookmarks {
+		if compareBookmarkKeys(bookmarkKey(bookmark), after) > 0 {
+			clone := *bookmark
+			bookmarks = append(bookmarks, &clone)
+		}
+	}
+
+	slices.SortFunc(bookmarks, func(a, b *store.Bookmark) int {
+		return compareBookmarkKeys(bookmarkKey(a), bookmarkKey(b))
+	})
+
+	return bookmarks[:min(limit, len(bookmarks))], nil
+}
+
+func (m *memoryStore) ExportPosts(_ context.Context, after store.PostKey, limit int) ([]*store.Post, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	posts := make([]*store.Post, 0)
+	for _, post := range m.data.Posts {
+		if comparePostKeys(storePostKey(post), after) > 0 {
+			posts = append(posts, clonePost(post))
+		}
+	}
+
+	slices.SortFunc(posts, func(a, b *store.Post) int {
+		return comparePostKeys(storePostKey(a), storePostKey(b))
+	})
+
+	return posts[:min(limit, len(posts))], nil
+}
+
+func (m *memoryStore) ImportGuilds(_ context.Context, guilds []*store.Guild) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, guild := range guilds {
+		m.data.Guilds[guild.ID] = cloneGuild(guild)
+	}
+
+	return m.save()
+}
+
+func (m *memoryStore) ImportUsers(_ context.Context, users []*store.User) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, user := range users {
+		m.data.Users[user.ID] = cloneUser(user)
+	}
+
+	return m.save()
+}
+
+func (m *memoryStore) ImportArtworks(_ context.Context, artworks []*store.Artwork) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, artwork := range artworks {
+		m.data.Artworks[artwork.ID] = cloneArtwork(artwork)
+		m.data.Counter = max(m.data.Counter, artwork.ID)
+	}
+
+	return m.save()
+}
+
+func (m *memoryStore) ImportBookmarks(_ context.Context, bookmarks []*store.Bookmark) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, bookmark := range bookmarks {
+		clone := *bookmark
+
+		idx := slices.IndexFunc(m.data.Bookmarks, sameBookmark(bookmark))
+		if idx == -1 {
+			m.data.Bookmarks = append(m.data.Bookmarks, &clone)
+		} else {
+			m.data.Bookmarks[idx] = &clone
+		}
+	}
+
+	return m.save()
+}
+
+func (m *memoryStore) ImportPosts(_ context.Context, posts []*store.Post) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, post := range posts {
+		m.data.Posts[postKey(post.ChannelID, post.MessageID)] = clonePost(post)
+	}
+
+	return m.save()
+}
+
+// page returns up to limit sorted keys strictly after the given key.
+func page[K cmp.Ordered](keys []K, after K, limit int) []K {
+	idx, found := slices.BinarySearch(keys, after)
+	if found {
+		idx++
+	}
+
+	return keys[idx:min(idx+limit, len(keys))]
+}
+
+func bookmarkKey(b *store.Bookmark) store.BookmarkKey {
+	return store.BookmarkKey{UserID: b.UserID, ArtworkID: b.ArtworkID}
+}
+
+func compareBookmarkKeys(a, b store.BookmarkKey) int {
+	return cmp.Or(cmp.Compare(a.UserID, b.UserID), cmp.Compare(a.ArtworkID, b.ArtworkID))
+}
+
+func storePostKey(p *store.Post) store.PostKey {
+	return store.PostKey{ChannelID: p.ChannelID, MessageID: p.MessageID}
+}
+
+func comparePostKeys(a, b store.PostKey) int {
+	return cmp.Or(cmp.Compare(a.ChannelID, b.ChannelID), cmp.Compare(a.MessageID, b.MessageID))
+}
@@ -0,0 +1,81 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/VTGare/boe-tea-go/store"
+)
+
+func (m *memoryStore) Post(_ context.Context, channelID, messageID string) (*store.Post, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	post, ok := m.data.Posts[postKey(channelID, messageID)]
+	if !ok || !post.ExpiresAt.After(time.Now()) {
+		return nil, store.ErrPostNotFound
+	}
+
+	return clonePost(post), nil
+}
+
+func (m *memoryStore) SavePosts(_ context.Context, posts ...*store.Post) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, post := range posts {
+		m.data.Posts[postKey(post.ChannelID, post.MessageID)] = clonePost(post)
+	}
+
+	m.saveLater()
+	return nil
+}
+
+func (m *memoryStore) DeletePost(_ context.Context, channelID, messageID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := postKey(channelID, messageID)
+	if _, ok := m.data.Posts[key]; !ok {
+		return nil
+	}
+
+	delete(m.data.Posts, key)
+	m.saveLater()
+	return nil
+}
+
+func (m *memoryStore) DeleteExpiredPosts(_ context.Context, now time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var deleted int64
+	for key, post := range m.data.Posts {
+		if !post.ExpiresAt.After(now) {
+			delete(m.data.Posts, key)
+			deleted++
+		}
+	}
+
+	if deleted == 0 {
+		return 0, nil
+	}
+
+	m.saveLater()
+	return deleted, nil
+}
+
+func postKey(channelID, messageID string) string {
+	return channelID + ":" + messageID
+}
+
+func clonePost(p *store.Post) *store.Post {
+	clone := *p
+	clone.Children = make([]*store.PostMessage, 0, len(p.Children))
+	for _, child := range p.Children {
+		c := *child
+		clone.Children = append(clone.Children, &c)
+	}
+
+	return &clone
+}
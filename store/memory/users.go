@@ -0,0 +1,201 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/VTGare/boe-tea-go/store"
+)
+
+func (m *memoryStore) User(_ context.Context, userID string) (*store.User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	user, ok := m.data.Users[userID]
+	if !ok {
+		user = store.DefaultUser(userID)
+		m.data.Users[userID] = user
+
+		if err := m.save(); err != nil {
+			return nil, err
+		}
+	}
+
+	return cloneUser(user), nil
+}
+
+func (m *memoryStore) CreateUser(_ context.Context, userID string) (*store.User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.data.Users[userID]; ok {
+		return nil, fmt.Errorf("user %v already exists", userID)
+	}
+
+	user := store.DefaultUser(userID)
+	m.data.Users[userID] = cloneUser(user)
+	if err := m.save(); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (m *memoryStore) UpdateUser(_ context.Context, user *store.User) (*store.User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	user.UpdatedAt = time.Now()
+	if _, ok := m.data.Users[user.ID]; !ok {
+		return user, nil
+	}
+
+	m.data.Users[user.ID] = cloneUser(user)
+	if err := m.save(); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (m *memoryStore) CreateCrosspostGroup(_ context.Context, userID string, group *store.Group) (*store.User, error) {
+	return m.updateUser(userID, func(u *store.User) bool {
+		if hasGroup(u, group.Name) || slices.ContainsFunc(u.Groups, func(g *store.Group) bool { return g.Parent == group.Parent }) {
+			return false
+		}
+
+		u.Groups = append(u.Groups, cloneGroup(group))
+		return true
+	})
+}
+
+func (m *memoryStore) CreateCrosspostPair(_ context.Context, userID string, pair *store.Group) (*store.User, error) {
+	return m.updateUser(userID, func(u *store.User) bool {
+		if hasGroup(u, pair.Name) || slices.ContainsFunc(u.Groups, func(g *store.Group) bool { return slices.Contains(pair.Children, g.Parent) }) {
+			return false
+		}
+
+		u.Groups = append(u.Groups, cloneGroup(pair))
+		return true
+	})
+}
+
+func (m *memoryStore) DeleteCrosspostGroup(_ context.Context, userID, group string) (*store.User, error) {
+	return m.updateUser(userID, func(u *store.User) bool {
+		if !hasGroup(u, group) {
+			return false
+		}
+
+		u.Groups = slices.DeleteFunc(u.Groups, func(g *store.Group) bool { return g.Name == group })
+		return true
+	})
+}
+
+func (m *memoryStore) RenameCrosspostGroup(_ context.Context, userID, group, rename string) (*store.User, error) {
+	return m.updateUser(userID, func(u *store.User) bool {
+		if hasGroup(u, rename) {
+			return false
+		}
+
+		g, ok := u.FindGroupByName(group)
+		if !ok {
+			return false
+		}
+
+		g.Name = rename
+		return true
+	})
+}
+
+func (m *memoryStore) AddCrosspostChannel(_ context.Context, userID, group, child string) (*store.User, error) {
+	return m.updateUser(userID, func(u *store.User) bool {
+		g, ok := u.FindGroupByName(group)
+		if !ok {
+			return false
+		}
+
+		if !slices.Contains(g.Children, child) {
+			g.Children = append(g.Children, child)
+		}
+
+		return true
+	})
+}
+
+func (m *memoryStore) DeleteCrosspostChannel(_ context.Context, userID, group, child string) (*store.User, error) {
+	return m.updateUser(userID, func(u *store.User) bool {
+		g, ok := u.FindGroupByName(group)
+		if !ok {
+			return false
+		}
+
+		g.Children = slices.DeleteFunc(g.Children, func(c string) bool { return c == child })
+		return true
+	})
+}
+
+func (m *memoryStore) EditCrosspostParent(_ context.Context, userID, group, parent string) (*store.User, error) {
+	return m.updateUser(userID, func(u *store.User) bool {
+		if slices.ContainsFunc(u.Groups, func(g *store.Group) bool { return g.Parent == parent }) {
+			return false
+		}
+
+		g, ok := u.FindGroupByName(group)
+		if !ok {
+			return false
+		}
+
+		g.Parent = parent
+		return true
+	})
+}
+
+func (m *memoryStore) EditCrosspostFilter(_ context.Context, userID, group string, filter store.GroupFilter) (*store.User, error) {
+	return m.updateUser(userID, func(u *store.User) bool {
+		g, ok := u.FindGroupByName(group)
+		if !ok {
+			return false
+		}
+
+		g.Filter = filter
+		return true
+	})
+}
+
+// updateUser applies fn to a copy of the user and commits it if fn returns true.
+// Otherwise, store.ErrNotFound is returned the same way Mongo's conditional updates don't match any documents.
+func (m *memoryStore) updateUser(userID string, fn func(*store.User) bool) (*store.User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	user, ok := m.data.Users[userID]
+	if !ok {
+		return nil, fmt.Errorf("user %v: %w", userID, store.ErrNotFound)
+	}
+
+	user = cloneUser(user)
+	if !fn(user) {
+		return nil, fmt.Errorf("user %v: %w", userID, store.ErrNotFound)
+	}
+
+	m.data.Users[userID] = user
+	if err := m.save(); err != nil {
+		return nil, err
+	}
+
+	return cloneUser(user), nil
+}
+
+func hasGroup(u *store.User, name string) bool {
+	_, ok := u.FindGroupByName(name)
+	return ok
+}
+
+func cloneGroup(g *store.Group) *store.Group {
+	clone := *g
+	clone.Children = slices.Clone(g.Children)
+	clone.Filter = cloneFilter(g.Filter)
+	return &clone
+}
@@ -0,0 +1,36 @@
+package store
+
+import "context"
+
+// Exporter is implemented by stores that can stream their data in batches ordered by primary key.
+// Each call returns up to limit entries strictly after the given key, an empty batch means the end of data.
+type Exporter interface {
+	ExportGuilds(ctx context.Context, after string, limit int) ([]*Guild, error)
+	ExportUsers(ctx context.Context, after string, limit int) ([]*User, error)
+	ExportArtworks(ctx context.Context, after int, limit int) ([]*Artwork, error)
+	ExportBookmarks(ctx context.Context, after BookmarkKey, limit int) ([]*Bookmark, error)
+	ExportPosts(ctx context.Context, after PostKey, limit int) ([]*Post, error)
+}
+
+// Importer is implemented by stores that can insert data as is, preserving IDs, timestamps and favourite counters.
+// Imports are upserts, so importing the same batch twice is safe. Importing artworks moves the artwork ID
+// counter forward so new artworks don't collide with imported ones.
+type Importer interface {
+	ImportGuilds(ctx context.Context, guilds []*Guild) error
+	ImportUsers(ctx context.Context, users []*User) error
+	ImportArtworks(ctx context.Context, artworks []*Artwork) error
+	ImportBookmarks(ctx context.Context, bookmarks []*Bookmark) error
+	ImportPosts(ctx context.Context, posts []*Post) error
+}
+
+// BookmarkKey is a primary key of a bookmark. Bookmarks are ordered by user ID and then by artwork ID.
+type BookmarkKey struct {
+	UserID    string `json:"user_id"`
+	ArtworkID int    `json:"artwork_id"`
+}
+
+// PostKey is a primary key of a post. Posts are ordered by channel ID and then by message ID.
+type PostKey struct {
+	ChannelID string `json:"channel_id"`
+	MessageID string `json:"message_id"`
+}
@@ -0,0 +1,399 @@
+// Package migrate copies data between store.Store implementations.
+package migrate
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/VTGare/boe-tea-go/store"
+	"go.uber.org/zap"
+)
+
+// Options configure a migration. Checkpoint is a path to a JSON file used to resume interrupted migrations,
+// an empty path always starts from scratch. Dry run reads the source store without writing anything.
+// Source and Destination identify the stores, a checkpoint saved for other stores is refused.
+type Options struct {
+	BatchSize   int
+	DryRun      bool
+	Checkpoint  string
+	Source      string
+	Destination string
+}
+
+// Migrator streams guilds, users, artworks, bookmarks and posts from source to destination in batches.
+type Migrator struct {
+	src         store.Exporter
+	dst         store.Importer
+	dstExporter store.Exporter
+	opts        Options
+	log         *zap.SugaredLogger
+}
+
+// Checkpoint stores the last migrated key of every entity and the stores it belongs to.
+type Checkpoint struct {
+	Source      string            `json:"source"`
+	Destination string            `json:"destination"`
+	Guilds      string            `json:"guilds"`
+	Users       string            `json:"users"`
+	Artworks    int               `json:"artworks"`
+	Bookmarks   store.BookmarkKey `json:"bookmarks"`
+	Posts       store.PostKey     `json:"posts"`
+}
+
+// Stats is a number of migrated entities.
+type Stats struct {
+	Guilds    int
+	Users     int
+	Artworks  int
+	Bookmarks int
+	Posts     int
+}
+
+// Result is a verification result of a single entity.
+type Result struct {
+	Name           string
+	SourceCount    int
+	DestCount      int
+	SourceChecksum string
+	DestChecksum   string
+}
+
+// OK reports whether source and destination contain the same data.
+func (r Result) OK() bool {
+	return r.SourceCount == r.DestCount && r.SourceChecksum == r.DestChecksum
+}
+
+var (
+	ErrNotSupported       = errors.New("store doesn't support migrations")
+	ErrCheckpointMismatch = errors.New("checkpoint belongs to a different migration")
+)
+
+func New(src, dst store.Store, opts Options, log *zap.SugaredLogger) (*Migrator, error) {
+	exporter, ok := src.(store.Exporter)
+	if !ok {
+		return nil, fmt.Errorf("source %T: %w", src, ErrNotSupported)
+	}
+
+	importer, ok := dst.(store.Importer)
+	if !ok {
+		return nil, fmt.Errorf("destination %T: %w", dst, ErrNotSupported)
+	}
+
+	dstExporter, ok := dst.(store.Exporter)
+	if !ok {
+		return nil, fmt.Errorf("destination %T: %w", dst, ErrNotSupported)
+	}
+
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 500
+	}
+
+	return &Migrator{
+		src:         exporter,
+		dst:         importer,
+		dstExporter: dstExporter,
+		opts:        opts,
+		log:         log,
+	}, nil
+}
+
+// Run migrates all entities. Artworks are migrated before bookmarks, so bookmarks never reference missing artworks.
+func (m *Migrator) Run(ctx context.Context) (*Stats, error) {
+	cp, err := m.loadCheckpoint()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		stats Stats
+		save  = func() error { return m.saveCheckpoint(cp) }
+	)
+
+	stats.Guilds, err = migrate(ctx, m, "guilds", &cp.Guilds, m.src.ExportGuilds, m.dst.ImportGuilds,
+		func(g *store.Guild) string { return g.ID }, save)
+	if err != nil {
+		return &stats, err
+	}
+
+	stats.Users, err = migrate(ctx, m, "users", &cp.Users, m.src.ExportUsers, m.dst.ImportUsers,
+		func(u *store.User) string { return u.ID }, save)
+	if err != nil {
+		return &stats, err
+	}
+
+	stats.Artworks, err = migrate(ctx, m, "artworks", &cp.Artworks, m.src.ExportArtworks, m.dst.ImportArtworks,
+		func(a *store.Artwork) int { return a.ID }, save)
+	if err != nil {
+		return &stats, err
+	}
+
+	stats.Bookmarks, err = migrate(ctx, m, "bookmarks", &cp.Bookmarks, m.src.ExportBookmarks, m.dst.ImportBookmarks,
+		func(b *store.Bookmark) store.BookmarkKey {
+			return store.BookmarkKey{UserID: b.UserID, ArtworkID: b.ArtworkID}
+		}, save)
+	if err != nil {
+		return &stats, err
+	}
+
+	stats.Posts, err = migrate(ctx, m, "posts", &cp.Posts, m.src.ExportPosts, m.dst.ImportPosts,
+		func(p *store.Post) store.PostKey {
+			return store.PostKey{ChannelID: p.ChannelID, MessageID: p.MessageID}
+		}, save)
+	if err != nil {
+		return &stats, err
+	}
+
+	return &stats, nil
+}
+
+func migrate[T any, K any](
+	ctx context.Context,
+	m *Migrator,
+	name string,
+	cursor *K,
+	export func(context.Context, K, int) ([]T, error),
+	importFn func(context.Context, []T) error,
+	key func(T) K,
+	save func() error,
+) (int, error) {
+	var count int
+	for {
+		batch, err := export(ctx, *cursor, m.opts.BatchSize)
+		if err != nil {
+			return count, err
+		}
+
+		if len(batch) == 0 {
+			break
+		}
+
+		if !m.opts.DryRun {
+			if err := importFn(ctx, batch); err != nil {
+				return count, err
+			}
+		}
+
+		count += len(batch)
+		*cursor = key(batch[len(batch)-1])
+
+		if !m.opts.DryRun {
+			if err := save(); err != nil {
+				return count, err
+			}
+		}
+
+		m.log.With("entity", name, "migrated", count, "dry_run", m.opts.DryRun).Info("migrated a batch")
+	}
+
+	return count, nil
+}
+
+// Verify compares entity counts and checksums of source and destination stores. Checksums don't depend
+// on the order of entities and timestamps are compared with millisecond precision, the precision of Mongo.
+func (m *Migrator) Verify(ctx context.Context) ([]Result, error) {
+	results := make([]Result, 0, 5)
+
+	verifications := []struct {
+		name     string
+		checksum func(store.Exporter) (int, string, error)
+	}{
+		{"guilds", func(e store.Exporter) (int, string, error) {
+			return checksum(ctx, m.opts.BatchSize, e.ExportGuilds, func(g *store.Guild) string { return g.ID }, normalizeGuild)
+		}},
+		{"users", func(e store.Exporter) (int, string, error) {
+			return checksum(ctx, m.opts.BatchSize, e.ExportUsers, func(u *store.User) string { return u.ID }, normalizeUser)
+		}},
+		{"artworks", func(e store.Exporter) (int, string, error) {
+			return checksum(ctx, m.opts.BatchSize, e.ExportArtworks, func(a *store.Artwork) int { return a.ID }, normalizeArtwork)
+		}},
+		{"bookmarks", func(e store.Exporter) (int, string, error) {
+			return checksum(ctx, m.opts.BatchSize, e.ExportBookmarks, func(b *store.Bookmark) store.BookmarkKey {
+				return store.BookmarkKey{UserID: b.UserID, ArtworkID: b.ArtworkID}
+			}, normalizeBookmark)
+		}},
+		{"posts", func(e store.Exporter) (int, string, error) {
+			return checksum(ctx, m.opts.BatchSize, e.ExportPosts, func(p *store.Post) store.PostKey {
+				return store.PostKey{ChannelID: p.ChannelID, MessageID: p.MessageID}
+			}, normalizePost)
+		}},
+	}
+
+	for _, v := range verifications {
+		res := Result{Name: v.name}
+
+		var err error
+		res.SourceCount, res.SourceChecksum, err = v.checksum(m.src)
+		if err != nil {
+			return nil, err
+		}
+
+		res.DestCount, res.DestChecksum, err = v.checksum(m.dstExporter)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, res)
+	}
+
+	return results, nil
+}
+
+func checksum[T any, K any](
+	ctx context.Context,
+	batchSize int,
+	export func(context.Context, K, int) ([]T, error),
+	key func(T) K,
+	normalize func(T) T,
+) (int, string, error) {
+	var (
+		cursor K
+		count  int
+		sum    [sha256.Size]byte
+	)
+
+	for {
+		batch, err := export(ctx, cursor, batchSize)
+		if err != nil {
+			return 0, "", err
+		}
+
+		if len(batch) == 0 {
+			break
+		}
+
+		for _, entity := range batch {
+			data, err := json.Marshal(normalize(entity))
+			if err != nil {
+				return 0, "", err
+			}
+
+			// XOR keeps the checksum independent of the order, which may differ between databases.
+			hash := sha256.Sum256(data)
+			for i := range sum {
+				sum[i] ^= hash[i]
+			}
+		}
+
+		count += len(batch)
+		cursor = key(batch[len(batch)-1])
+	}
+
+	return count, hex.EncodeToString(sum[:]), nil
+}
+
+func (m *Migrator) loadCheckpoint() (*Checkpoint, error) {
+	cp := &Checkpoint{Source: m.opts.Source, Destination: m.opts.Destination}
+	if m.opts.Checkpoint == "" {
+		return cp, nil
+	}
+
+	file, err := os.ReadFile(m.opts.Checkpoint)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return cp, nil
+		}
+
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+
+	if err := json.Unmarshal(file, cp); err != nil {
+		return nil, fmt.Errorf("failed to decode checkpoint: %w", err)
+	}
+
+	if cp.Source != m.opts.Source || cp.Destination != m.opts.Destination {
+		return nil, fmt.Errorf(
+			"%w: %v saved for %v -> %v, remove it to migrate %v -> %v",
+			ErrCheckpointMismatch, m.opts.Checkpoint, cp.Source, cp.Destination, m.opts.Source, m.opts.Destination,
+		)
+	}
+
+	m.log.With("checkpoint", cp).Info("resuming migration")
+	return cp, nil
+}
+
+func (m *Migrator) saveCheckpoint(cp *Checkpoint) error {
+	if m.opts.Checkpoint == "" {
+		return nil
+	}
+
+	file, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(m.opts.Checkpoint, file, 0o600); err != nil {
+		return fmt.Errorf("failed to save checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+func normalizeTime(t time.Time) time.Time {
+	return t.UTC().Truncate(time.Millisecond)
+}
+
+func normalizeGuild(g *store.Guild) *store.Guild {
+	clone := *g
+	clone.ArtChannels = nonNil(g.ArtChannels)
+	clone.Blacklist.Tags = nonNil(g.Blacklist.Tags)
+	clone.Groups = normalizeGroups(g.Groups)
+	clone.CreatedAt = normalizeTime(g.CreatedAt)
+	clone.UpdatedAt = normalizeTime(g.UpdatedAt)
+	return &clone
+}
+
+func normalizeUser(u *store.User) *store.User {
+	clone := *u
+	clone.Blacklist.Tags = nonNil(u.Blacklist.Tags)
+	clone.CreatedAt = normalizeTime(u.CreatedAt)
+	clone.UpdatedAt = normalizeTime(u.UpdatedAt)
+	clone.Groups = normalizeGroups(u.Groups)
+	return &clone
+}
+
+func normalizeGroups(groups []*store.Group) []*store.Group {
+	clone := make([]*store.Group, 0, len(groups))
+	for _, group := range groups {
+		g := *group
+		g.Children = nonNil(group.Children)
+		clone = append(clone, &g)
+	}
+
+	return clone
+}
+
+func normalizeArtwork(a *store.Artwork) *store.Artwork {
+	clone := *a
+	clone.Images = nonNil(a.Images)
+	clone.CreatedAt = normalizeTime(a.CreatedAt)
+	clone.UpdatedAt = normalizeTime(a.UpdatedAt)
+	return &clone
+}
+
+func normalizeBookmark(b *store.Bookmark) *store.Bookmark {
+	clone := *b
+	clone.CreatedAt = normalizeTime(b.CreatedAt)
+	return &clone
+}
+
+func normalizePost(p *store.Post) *store.Post {
+	clone := *p
+	clone.Children = nonNil(p.Children)
+	clone.CreatedAt = normalizeTime(p.CreatedAt)
+	clone.ExpiresAt = normalizeTime(p.ExpiresAt)
+	return &clone
+}
+
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return make([]T, 0)
+	}
+
+	return s
+}
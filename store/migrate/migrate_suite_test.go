@@ -0,0 +1,172 @@
+package migrate_test
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/VTGare/boe-tea-go/store"
+	"github.com/VTGare/boe-tea-go/store/memory"
+	"github.com/VTGare/boe-tea-go/store/migrate"
+	"github.com/VTGare/boe-tea-go/store/sql"
+	"go.uber.org/zap"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestMigrate(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Migrate Suite")
+}
+
+var _ = Describe("Migrator", func() {
+	var (
+		ctx      = context.Background()
+		src, dst store.Store
+	)
+
+	BeforeEach(func() {
+		var err error
+		src, err = memory.New("")
+		Expect(err).NotTo(HaveOccurred())
+
+		dst, err = sql.New(ctx, sql.SQLite, ":memory:")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dst.Init(ctx)).To(Succeed())
+
+		for i := 1; i <= 7; i++ {
+			id := fmt.Sprint(100 + i)
+
+			_, err := src.CreateGuild(ctx, id)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = src.User(ctx, id)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = src.CreateCrosspostGroup(ctx, id, &store.Group{Name: "art", Parent: id, Children: []string{"1"}})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = src.CreateGuildGroup(ctx, id, &store.Group{Name: "pics", Parent: id, Children: []string{"2", "3"}})
+			Expect(err).NotTo(HaveOccurred())
+
+			err = src.SavePosts(ctx, &store.Post{
+				ChannelID: id,
+				MessageID: "1",
+				AuthorID:  id,
+				IsParent:  true,
+				Children:  []*store.PostMessage{{ChannelID: id, MessageID: "2", ArtworkID: id}},
+				CreatedAt: time.Now(),
+				ExpiresAt: time.Now().Add(time.Hour),
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			artwork, err := src.CreateArtwork(ctx, &store.Artwork{URL: "https://example.com/" + id, Images: []string{id}})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = src.AddBookmark(ctx, &store.Bookmark{UserID: id, ArtworkID: artwork.ID, CreatedAt: time.Now()})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		// Leave a gap in artwork IDs to make sure they're preserved.
+		err = src.(store.Importer).ImportArtworks(ctx, []*store.Artwork{{ID: 42, URL: "https://example.com/42"}})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should migrate all entities and keep artwork IDs", func() {
+		m, err := migrate.New(src, dst, migrate.Options{BatchSize: 3}, zap.NewNop().Sugar())
+		Expect(err).NotTo(HaveOccurred())
+
+		stats, err := m.Run(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*stats).To(Equal(migrate.Stats{Guilds: 7, Users: 7, Artworks: 8, Bookmarks: 7, Posts: 7}))
+
+		results, err := m.Verify(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		for _, res := range results {
+			Expect(res.OK()).To(BeTrue(), res.Name)
+		}
+
+		artwork, err := dst.Artwork(ctx, 42, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(artwork.URL).To(Equal("https://example.com/42"))
+
+		artwork, err = dst.Artwork(ctx, 3, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(artwork.Favorites).To(Equal(1))
+
+		user, err := dst.User(ctx, "103")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(user.Groups).To(HaveLen(1))
+
+		guild, err := dst.Guild(ctx, "103")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(guild.Groups).To(HaveLen(1))
+		Expect(guild.Groups[0].Children).To(Equal([]string{"2", "3"}))
+
+		post, err := dst.Post(ctx, "103", "1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(post.Children).To(HaveLen(1))
+		Expect(post.Children[0].ArtworkID).To(Equal("103"))
+
+		next, err := dst.CreateArtwork(ctx, &store.Artwork{URL: "https://example.com/next"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(next.ID).To(Equal(43))
+	})
+
+	It("should not write anything in dry run", func() {
+		m, err := migrate.New(src, dst, migrate.Options{DryRun: true}, zap.NewNop().Sugar())
+		Expect(err).NotTo(HaveOccurred())
+
+		stats, err := m.Run(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stats.Artworks).To(Equal(8))
+
+		results, err := m.Verify(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		for _, res := range results {
+			Expect(res.DestCount).To(BeZero())
+			Expect(res.OK()).To(BeFalse())
+		}
+	})
+
+	It("should resume from a checkpoint", func() {
+		checkpoint := filepath.Join(GinkgoT().TempDir(), "checkpoint.json")
+
+		m, err := migrate.New(src, dst, migrate.Options{BatchSize: 2, Checkpoint: checkpoint}, zap.NewNop().Sugar())
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = m.Run(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = src.CreateGuild(ctx, "200")
+		Expect(err).NotTo(HaveOccurred())
+
+		stats, err := m.Run(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*stats).To(Equal(migrate.Stats{Guilds: 1}))
+
+		_, err = dst.Guild(ctx, "200")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should refuse a checkpoint of other stores", func() {
+		checkpoint := filepath.Join(GinkgoT().TempDir(), "checkpoint.json")
+		opts := migrate.Options{Checkpoint: checkpoint, Source: "memory:", Destination: "sqlite::memory:"}
+
+		m, err := migrate.New(src, dst, opts, zap.NewNop().Sugar())
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = m.Run(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		opts.Destination = "postgres://localhost/boetea"
+		m, err = migrate.New(src, dst, opts, zap.NewNop().Sugar())
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = m.Run(ctx)
+		Expect(err).To(MatchError(migrate.ErrCheckpointMismatch))
+	})
+})
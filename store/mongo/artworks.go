@@ -162,6 +162,10 @@ func filterBSON(f store.ArtworkFilter) bson.D {
 		if f.Time != 0 {
 			filter = append(filter, bson.E{Key: "created_at", Value: bson.M{"$gte": time.Now().Add(-f.Time)}})
 		}
+
+		if f.ExcludeAI {
+			filter = append(filter, bson.E{Key: "ai_generated", Value: bson.M{"$ne": true}})
+		}
 	}
 
 	return filter
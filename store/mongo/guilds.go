@@ -28,9 +28,11 @@ func (g guildStore) Guild(ctx context.Context, id string) (*store.Guild, error)
 	res := g.col.FindOne(ctx, bson.M{"guild_id": id})
 
 	var guild store.Guild
-	err := res.Decode(&guild)
+	if err := res.Decode(&guild); err != nil {
+		return nil, notFound(err)
+	}
 
-	return &guild, err
+	return &guild, nil
 }
 
 func (g *guildStore) CreateGuild(ctx context.Context, id string) (*store.Guild, error) {
@@ -65,7 +67,7 @@ func (g *guildStore) AddArtChannels(ctx context.Context, guildID string, channel
 	var guild store.Guild
 	err := res.Decode(&guild)
 	if err != nil {
-		return nil, err
+		return nil, notFound(err)
 	}
 
 	return &guild, nil
@@ -82,7 +84,50 @@ func (g *guildStore) DeleteArtChannels(ctx context.Context, guildID string, chan
 	var guild store.Guild
 	err := res.Decode(&guild)
 	if err != nil {
-		return nil, err
+		return nil, notFound(err)
+	}
+
+	return &guild, nil
+}
+
+func (g *guildStore) CreateGuildGroup(ctx context.Context, guildID string, group *store.Group) (*store.Guild, error) {
+	return g.findOneAndUpdate(
+		ctx,
+		bson.M{"guild_id": guildID, "crosspost_groups.name": bson.M{"$ne": group.Name}, "crosspost_groups.parent": bson.M{"$ne": group.Parent}},
+		bson.M{"$push": bson.M{"crosspost_groups": group}},
+	)
+}
+
+func (g *guildStore) DeleteGuildGroup(ctx context.Context, guildID, group string) (*store.Guild, error) {
+	return g.findOneAndUpdate(
+		ctx,
+		bson.M{"guild_id": guildID, "crosspost_groups.name": group},
+		bson.M{"$pull": bson.M{"crosspost_groups": bson.M{"name": group}}},
+	)
+}
+
+func (g *guildStore) AddGuildGroupChannel(ctx context.Context, guildID, group, child string) (*store.Guild, error) {
+	return g.findOneAndUpdate(
+		ctx,
+		bson.M{"guild_id": guildID, "crosspost_groups.name": group},
+		bson.M{"$addToSet": bson.M{"crosspost_groups.$.children": child}},
+	)
+}
+
+func (g *guildStore) DeleteGuildGroupChannel(ctx context.Context, guildID, group, child string) (*store.Guild, error) {
+	return g.findOneAndUpdate(
+		ctx,
+		bson.M{"guild_id": guildID, "crosspost_groups.name": group},
+		bson.M{"$pull": bson.M{"crosspost_groups.$.children": child}},
+	)
+}
+
+func (g *guildStore) findOneAndUpdate(ctx context.Context, filter, update bson.M) (*store.Guild, error) {
+	res := g.col.FindOneAndUpdate(ctx, filter, update, options.FindOneAndUpdate().SetReturnDocument(options.After))
+
+	var guild store.Guild
+	if err := res.Decode(&guild); err != nil {
+		return nil, notFound(err)
 	}
 
 	return &guild, nil
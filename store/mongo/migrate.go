@@ -0,0 +1,181 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/VTGare/boe-tea-go/store"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (m *mongoStore) ExportGuilds(ctx context.Context, after string, limit int) ([]*store.Guild, error) {
+	guilds := make([]*store.Guild, 0, limit)
+	err := export(ctx, m.guildStore.col, bson.M{"guild_id": bson.M{"$gt": after}}, bson.D{{Key: "guild_id", Value: 1}}, limit, &guilds)
+	if err != nil {
+		return nil, err
+	}
+
+	return guilds, nil
+}
+
+func (m *mongoStore) ExportUsers(ctx context.Context, after string, limit int) ([]*store.User, error) {
+	users := make([]*store.User, 0, limit)
+	err := export(ctx, m.userStore.col, bson.M{"user_id": bson.M{"$gt": after}}, bson.D{{Key: "user_id", Value: 1}}, limit, &users)
+	if err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func (m *mongoStore) ExportArtworks(ctx context.Context, after int, limit int) ([]*store.Artwork, error) {
+	artworks := make([]*store.Artwork, 0, limit)
+	err := export(ctx, m.artworkStore.col, bson.M{"artwork_id": bson.M{"$gt": after}}, bson.D{{Key: "artwork_id", Value: 1}}, limit, &artworks)
+	if err != nil {
+		return nil, err
+	}
+
+	return artworks, nil
+}
+
+func (m *mongoStore) ExportBookmarks(ctx context.Context, after store.BookmarkKey, limit int) ([]*store.Bookmark, error) {
+	filter := bson.M{"$or": []bson.M{
+		{"user_id": bson.M{"$gt": after.UserID}},
+		{"user_id": after.UserID, "artwork_id": bson.M{"$gt": after.ArtworkID}},
+	}}
+
+	bookmarks := make([]*store.Bookmark, 0, limit)
+	err := export(ctx, m.bookmarkStore.col, filter, bson.D{{Key: "user_id", Value: 1}, {Key: "artwork_id", Value: 1}}, limit, &bookmarks)
+	if err != nil {
+		return nil, err
+	}
+
+	return bookmarks, nil
+}
+
+func (m *mongoStore) ExportPosts(ctx context.Context, after store.PostKey, limit int) ([]*store.Post, error) {
+	filter := bson.M{"$or": []bson.M{
+		{"channel_id": bson.M{"$gt": after.ChannelID}},
+		{"channel_id": after.ChannelID, "message_id": bson.M{"$gt": after.MessageID}},
+	}}
+
+	posts := make([]*store.Post, 0, limit)
+	err := export(ctx, m.postStore.col, filter, bson.D{{Key: "channel_id", Value: 1}, {Key: "message_id", Value: 1}}, limit, &posts)
+	if err != nil {
+		return nil, err
+	}
+
+	return posts, nil
+}
+
+func (m *mongoStore) ImportGuilds(ctx context.Context, guilds []*store.Guild) error {
+	models := make([]mongo.WriteModel, 0, len(guilds))
+	for _, guild := range guilds {
+		models = append(models, mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"guild_id": guild.ID}).
+			SetReplacement(guild).
+			SetUpsert(true),
+		)
+	}
+
+	return bulkWrite(ctx, m.guildStore.col, models)
+}
+
+func (m *mongoStore) ImportUsers(ctx context.Context, users []*store.User) error {
+	models := make([]mongo.WriteModel, 0, len(users))
+	for _, user := range users {
+		models = append(models, mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"user_id": user.ID}).
+			SetReplacement(user).
+			SetUpsert(true),
+		)
+	}
+
+	return bulkWrite(ctx, m.userStore.col, models)
+}
+
+func (m *mongoStore) ImportArtworks(ctx context.Context, artworks []*store.Artwork) error {
+	if len(artworks) == 0 {
+		return nil
+	}
+
+	var maxID int
+	models := make([]mongo.WriteModel, 0, len(artworks))
+	for _, artwork := range artworks {
+		maxID = max(maxID, artwork.ID)
+		models = append(models, mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"artwork_id": artwork.ID}).
+			SetReplacement(artwork).
+			SetUpsert(true),
+		)
+	}
+
+	if err := bulkWrite(ctx, m.artworkStore.col, models); err != nil {
+		return err
+	}
+
+	_, err := m.database.Collection("counters").UpdateOne(
+		ctx,
+		bson.M{"_id": "artworks"},
+		bson.M{"$max": bson.M{"counter": maxID}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update artwork counter: %w", err)
+	}
+
+	return nil
+}
+
+func (m *mongoStore) ImportBookmarks(ctx context.Context, bookmarks []*store.Bookmark) error {
+	models := make([]mongo.WriteModel, 0, len(bookmarks))
+	for _, bookmark := range bookmarks {
+		models = append(models, mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"user_id": bookmark.UserID, "artwork_id": bookmark.ArtworkID}).
+			SetReplacement(bookmark).
+			SetUpsert(true),
+		)
+	}
+
+	return bulkWrite(ctx, m.bookmarkStore.col, models)
+}
+
+func (m *mongoStore) ImportPosts(ctx context.Context, posts []*store.Post) error {
+	models := make([]mongo.WriteModel, 0, len(posts))
+	for _, post := range posts {
+		models = append(models, mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"channel_id": post.ChannelID, "message_id": post.MessageID}).
+			SetReplacement(post).
+			SetUpsert(true),
+		)
+	}
+
+	return bulkWrite(ctx, m.postStore.col, models)
+}
+
+func export(ctx context.Context, col *mongo.Collection, filter any, sort bson.D, limit int, res any) error {
+	cur, err := col.Find(ctx, filter, options.Find().SetSort(sort).SetLimit(int64(limit)))
+	if err != nil {
+		return fmt.Errorf("failed to export %v: %w", col.Name(), err)
+	}
+
+	if err := cur.All(ctx, res); err != nil {
+		return fmt.Errorf("failed to decode %v: %w", col.Name(), err)
+	}
+
+	return nil
+}
+
+func bulkWrite(ctx context.Context, col *mongo.Collection, models []mongo.WriteModel) error {
+	if len(models) == 0 {
+		return nil
+	}
+
+	if _, err := col.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false)); err != nil {
+		return fmt.Errorf("failed to import %v: %w", col.Name(), err)
+	}
+
+	return nil
+}
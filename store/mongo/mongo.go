@@ -19,6 +19,7 @@ type mongoStore struct {
 	*userStore
 	*guildStore
 	*bookmarkStore
+	*postStore
 }
 
 func New(ctx context.Context, uri string, db string) (store.Store, error) {
@@ -39,11 +40,12 @@ func New(ctx context.Context, uri string, db string) (store.Store, error) {
 		userStore:     &userStore{client, database, database.Collection("users")},
 		guildStore:    &guildStore{client, database, database.Collection("guilds")},
 		bookmarkStore: &bookmarkStore{client, database, database.Collection("bookmarks")},
+		postStore:     &postStore{client, database, database.Collection("posts")},
 	}, nil
 }
 
 func (m *mongoStore) Init(ctx context.Context) error {
-	collections := []string{"artworks", "counters", "guilds", "users", "bookmarks"}
+	collections := []string{"artworks", "counters", "guilds", "users", "bookmarks", "posts"}
 	for _, col := range collections {
 		err := m.database.CreateCollection(ctx, col)
 		if err != nil && !errors.As(err, &mongo.CommandError{}) {
@@ -51,9 +53,18 @@ func (m *mongoStore) Init(ctx context.Context) error {
 		}
 	}
 
-	return nil
+	return m.postStore.init(ctx)
 }
 
 func (m *mongoStore) Close(ctx context.Context) error {
 	return m.client.Disconnect(ctx)
 }
+
+// notFound wraps mongo.ErrNoDocuments with store.ErrNotFound to keep callers independent of the backend.
+func notFound(err error) error {
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return fmt.Errorf("%w: %w", store.ErrNotFound, err)
+	}
+
+	return err
+}
@@ -0,0 +1,48 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/VTGare/boe-tea-go/store"
+	"github.com/VTGare/boe-tea-go/store/storetest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestMongo(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Mongo Store Suite")
+}
+
+// The conformance suite requires a replica set because artworks and bookmarks use transactions.
+// Set BOETEA_TEST_MONGO_URI to run it, e.g. mongodb://localhost:27017/?replicaSet=rs0
+var _ = Describe("Mongo store", func() {
+	uri := os.Getenv("BOETEA_TEST_MONGO_URI")
+
+	BeforeEach(func() {
+		if uri == "" {
+			Skip("BOETEA_TEST_MONGO_URI is not set")
+		}
+	})
+
+	storetest.Conformance(func() store.Store {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		s, err := New(ctx, uri, fmt.Sprintf("boetea-test-%v", time.Now().UnixNano()))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Init(ctx)).To(Succeed())
+
+		DeferCleanup(func(ctx context.Context) {
+			Expect(s.(*mongoStore).database.Drop(ctx)).To(Succeed())
+			Expect(s.Close(ctx)).To(Succeed())
+		})
+
+		return s
+	})
+})
@@ -0,0 +1,96 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/VTGare/boe-tea-go/store"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type postStore struct {
+	client *mongo.Client
+	db     *mongo.Database
+	col    *mongo.Collection
+}
+
+// init creates a unique index on message IDs and a TTL index that lets Mongo remove expired posts by itself.
+func (p *postStore) init(ctx context.Context) error {
+	_, err := p.col.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "channel_id", Value: 1}, {Key: "message_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create post indexes: %w", err)
+	}
+
+	return nil
+}
+
+func (p *postStore) Post(ctx context.Context, channelID, messageID string) (*store.Post, error) {
+	res := p.col.FindOne(ctx, bson.M{
+		"channel_id": channelID,
+		"message_id": messageID,
+		"expires_at": bson.M{"$gt": time.Now()},
+	})
+
+	var post store.Post
+	if err := res.Decode(&post); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, fmt.Errorf("%w: %w", store.ErrPostNotFound, err)
+		}
+
+		return nil, err
+	}
+
+	return &post, nil
+}
+
+func (p *postStore) SavePosts(ctx context.Context, posts ...*store.Post) error {
+	if len(posts) == 0 {
+		return nil
+	}
+
+	models := make([]mongo.WriteModel, 0, len(posts))
+	for _, post := range posts {
+		models = append(models, mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"channel_id": post.ChannelID, "message_id": post.MessageID}).
+			SetReplacement(post).
+			SetUpsert(true),
+		)
+	}
+
+	if _, err := p.col.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false)); err != nil {
+		return fmt.Errorf("failed to save posts: %w", err)
+	}
+
+	return nil
+}
+
+func (p *postStore) DeletePost(ctx context.Context, channelID, messageID string) error {
+	_, err := p.col.DeleteOne(ctx, bson.M{"channel_id": channelID, "message_id": messageID})
+	if err != nil {
+		return fmt.Errorf("failed to delete a post: %w", err)
+	}
+
+	return nil
+}
+
+func (p *postStore) DeleteExpiredPosts(ctx context.Context, now time.Time) (int64, error) {
+	res, err := p.col.DeleteMany(ctx, bson.M{"expires_at": bson.M{"$lte": now}})
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired posts: %w", err)
+	}
+
+	return res.DeletedCount, nil
+}
@@ -130,6 +130,17 @@ func (u *userStore) EditCrosspostParent(ctx context.Context, userID, group, pare
 	return resDecoder(res)
 }
 
+func (u *userStore) EditCrosspostFilter(ctx context.Context, userID, group string, filter store.GroupFilter) (*store.User, error) {
+	res := u.col.FindOneAndUpdate(
+		ctx,
+		bson.M{"user_id": userID, "channel_groups.name": group},
+		bson.M{"$set": bson.M{"channel_groups.$.filter": filter}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	)
+
+	return resDecoder(res)
+}
+
 func (u *userStore) UpdateUser(ctx context.Context, user *store.User) (*store.User, error) {
 	user.UpdatedAt = time.Now()
 	_, err := u.col.ReplaceOne(
@@ -148,7 +159,7 @@ func resDecoder(res *mongo.SingleResult) (*store.User, error) {
 	var user store.User
 	err := res.Decode(&user)
 	if err != nil {
-		return nil, err
+		return nil, notFound(err)
 	}
 
 	return &user, nil
@@ -0,0 +1,35 @@
+package store
+
+import (
+	"context"
+	"time"
+)
+
+// PostStore keeps track of messages with embeds Boe Tea sent, so their authors can remove them later.
+// Expired posts are never returned and are removed by DeleteExpiredPosts.
+type PostStore interface {
+	Post(ctx context.Context, channelID, messageID string) (*Post, error)
+	// SavePosts saves or replaces posts in one write.
+	SavePosts(ctx context.Context, posts ...*Post) error
+	DeletePost(ctx context.Context, channelID, messageID string) error
+	DeleteExpiredPosts(ctx context.Context, now time.Time) (int64, error)
+}
+
+// Post is a message posted by a user. Children are filled for parent messages only and contain
+// all embeds sent by Boe Tea for the message, including crossposted messages.
+type Post struct {
+	ChannelID string         `json:"channel_id" bson:"channel_id"`
+	MessageID string         `json:"message_id" bson:"message_id"`
+	AuthorID  string         `json:"author_id" bson:"author_id"`
+	IsParent  bool           `json:"is_parent" bson:"is_parent"`
+	Children  []*PostMessage `json:"children" bson:"children"`
+	CreatedAt time.Time      `json:"created_at" bson:"created_at"`
+	ExpiresAt time.Time      `json:"expires_at" bson:"expires_at"`
+}
+
+// PostMessage is a message sent by Boe Tea. ArtworkID is the ID the artwork's link was matched with.
+type PostMessage struct {
+	ChannelID string `json:"channel_id" bson:"channel_id"`
+	MessageID string `json:"message_id" bson:"message_id"`
+	ArtworkID string `json:"artwork_id" bson:"artwork_id"`
+}
@@ -0,0 +1,229 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/VTGare/boe-tea-go/store"
+)
+
+const artworkColumns = `artwork_id, title, author, url, images, favourites, ai_generated, created_at, updated_at`
+
+func (s *sqlStore) Artwork(ctx context.Context, id int, url string) (*store.Artwork, error) {
+	var (
+		where = make([]string, 0, 2)
+		args  = make([]any, 0, 2)
+	)
+
+	if id != 0 {
+		where = append(where, "artwork_id = ?")
+		args = append(args, id)
+	}
+
+	if url != "" {
+		where = append(where, "url = ?")
+		args = append(args, url)
+	}
+
+	query := `SELECT ` + artworkColumns + ` FROM artworks`
+	if len(where) != 0 {
+		query += ` WHERE ` + strings.Join(where, " AND ")
+	}
+
+	artwork, err := scanArtwork(s.db.QueryRowContext(ctx, s.rebind(query+` LIMIT 1`), args...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, store.ErrArtworkNotFound
+		}
+
+		return nil, fmt.Errorf("failed to scan an artwork: %w", err)
+	}
+
+	return artwork, nil
+}
+
+func (s *sqlStore) SearchArtworks(ctx context.Context, filter store.ArtworkFilter, opts ...store.ArtworkSearchOptions) ([]*store.Artwork, error) {
+	opt := store.DefaultSearchOptions()
+	if len(opts) != 0 {
+		opt = opts[0]
+	}
+
+	where, args := filterSQL(filter)
+
+	query := `SELECT ` + artworkColumns + ` FROM artworks`
+	if where != "" {
+		query += ` WHERE ` + where
+	}
+
+	direction := "DESC"
+	if opt.Order == store.Ascending {
+		direction = "ASC"
+	}
+
+	limit := opt.Limit
+	if limit <= 0 {
+		limit = math.MaxInt64
+	}
+
+	query += fmt.Sprintf(` ORDER BY %[1]v %[2]v, artwork_id %[2]v LIMIT ? OFFSET ?`, opt.Sort, direction)
+	args = append(args, limit, opt.Skip)
+
+	rows, err := s.db.QueryContext(ctx, s.rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search artworks: %w", err)
+	}
+	defer rows.Close()
+
+	artworks := make([]*store.Artwork, 0)
+	for rows.Next() {
+		artwork, err := scanArtwork(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan an artwork: %w", err)
+		}
+
+		artworks = append(artworks, artwork)
+	}
+
+	return artworks, rows.Err()
+}
+
+func (s *sqlStore) CreateArtwork(ctx context.Context, artwork *store.Artwork) (*store.Artwork, error) {
+	images, err := json.Marshal(nonNil(artwork.Images))
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.tx(ctx, func(tx *sql.Tx) error {
+		var id int
+		err := tx.QueryRowContext(ctx, s.rebind(
+			`INSERT INTO counters (name, counter) VALUES (?, 1)
+			ON CONFLICT (name) DO UPDATE SET counter = counters.counter + 1
+			RETURNING counter`,
+		), "artworks").Scan(&id)
+		if err != nil {
+			return fmt.Errorf("failed to increment artwork counter: %w", err)
+		}
+
+		now := time.Now().UTC()
+		_, err = tx.ExecContext(ctx, s.rebind(
+			`INSERT INTO artworks (`+artworkColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		), id, artwork.Title, artwork.Author, artwork.URL, string(images), artwork.Favorites, artwork.AI, now, now)
+		if err != nil {
+			return fmt.Errorf("failed to insert an artwork: %w", err)
+		}
+
+		artwork.ID = id
+		artwork.CreatedAt = now
+		artwork.UpdatedAt = now
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return artwork, nil
+}
+
+// filterSQL builds a WHERE clause equivalent to Mongo store's artwork filter.
+// Regular expressions are replaced with portable case-insensitive substring matching.
+func filterSQL(f store.ArtworkFilter) (string, []any) {
+	contains := func(column, value string) (string, any) {
+		return "LOWER(" + column + `) LIKE ? ESCAPE '\'`, "%" + escapeLike(strings.ToLower(value)) + "%"
+	}
+
+	switch {
+	case len(f.IDs) != 0:
+		args := make([]any, 0, len(f.IDs))
+		for _, id := range f.IDs {
+			args = append(args, id)
+		}
+
+		return "artwork_id IN (" + placeholders(len(f.IDs)) + ")", args
+	case f.URL != "":
+		return "url = ?", []any{f.URL}
+	case f.Query != "":
+		author, arg := contains("author", f.Query)
+		title, _ := contains("title", f.Query)
+
+		return "(" + author + " OR " + title + ")", []any{arg, arg}
+	}
+
+	var (
+		where = make([]string, 0, 4)
+		args  = make([]any, 0, 4)
+	)
+
+	if f.Author != "" {
+		clause, arg := contains("author", f.Author)
+		where = append(where, clause)
+		args = append(args, arg)
+	}
+
+	if f.Title != "" {
+		clause, arg := contains("title", f.Title)
+		where = append(where, clause)
+		args = append(args, arg)
+	}
+
+	if f.Time != 0 {
+		where = append(where, "created_at >= ?")
+		args = append(args, time.Now().Add(-f.Time).UTC())
+	}
+
+	if f.ExcludeAI {
+		where = append(where, "ai_generated = ?")
+		args = append(args, false)
+	}
+
+	return strings.Join(where, " AND "), args
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanArtwork(row scanner) (*store.Artwork, error) {
+	var (
+		artwork store.Artwork
+		images  []byte
+	)
+
+	err := row.Scan(
+		&artwork.ID,
+		&artwork.Title,
+		&artwork.Author,
+		&artwork.URL,
+		&images,
+		&artwork.Favorites,
+		&artwork.AI,
+		&artwork.CreatedAt,
+		&artwork.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(images, &artwork.Images); err != nil {
+		return nil, fmt.Errorf("failed to decode artwork images: %w", err)
+	}
+
+	return &artwork, nil
+}
+
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return make([]T, 0)
+	}
+
+	return s
+}
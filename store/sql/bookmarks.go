@@ -0,0 +1,119 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/VTGare/boe-tea-go/store"
+)
+
+func (s *sqlStore) ListBookmarks(ctx context.Context, userID string, filter store.BookmarkFilter, order store.Order) ([]*store.Bookmark, error) {
+	query := `SELECT user_id, artwork_id, nsfw, created_at FROM bookmarks WHERE user_id = ?`
+	args := []any{userID}
+
+	if filter != store.BookmarkFilterAll {
+		query += ` AND nsfw = ?`
+		args = append(args, filter == store.BookmarkFilterUnsafe)
+	}
+
+	if order == store.Ascending {
+		query += ` ORDER BY created_at ASC`
+	} else {
+		query += ` ORDER BY created_at DESC`
+	}
+
+	rows, err := s.db.QueryContext(ctx, s.rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find bookmarks: %w", err)
+	}
+	defer rows.Close()
+
+	bookmarks := make([]*store.Bookmark, 0)
+	for rows.Next() {
+		var bookmark store.Bookmark
+		if err := rows.Scan(&bookmark.UserID, &bookmark.ArtworkID, &bookmark.NSFW, &bookmark.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan a bookmark: %w", err)
+		}
+
+		bookmarks = append(bookmarks, &bookmark)
+	}
+
+	return bookmarks, rows.Err()
+}
+
+func (s *sqlStore) CountBookmarks(ctx context.Context, userID string) (int64, error) {
+	var count int64
+	err := s.db.QueryRowContext(ctx, s.rebind(`SELECT COUNT(*) FROM bookmarks WHERE user_id = ?`), userID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count bookmarks: %w", err)
+	}
+
+	return count, nil
+}
+
+func (s *sqlStore) AddBookmark(ctx context.Context, bookmark *store.Bookmark) (bool, error) {
+	var added bool
+	err := s.tx(ctx, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, s.rebind(
+			`INSERT INTO bookmarks (user_id, artwork_id, nsfw, created_at) VALUES (?, ?, ?, ?)
+			ON CONFLICT (user_id, artwork_id) DO NOTHING`,
+		), bookmark.UserID, bookmark.ArtworkID, bookmark.NSFW, bookmark.CreatedAt.UTC())
+		if err != nil {
+			return fmt.Errorf("failed to insert bookmark: %w", err)
+		}
+
+		if added, err = affected(res); err != nil || !added {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, s.rebind(`UPDATE artworks SET favourites = favourites + 1 WHERE artwork_id = ?`), bookmark.ArtworkID)
+		if err != nil {
+			return fmt.Errorf("failed to increment artwork favorite count: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return added, nil
+}
+
+func (s *sqlStore) DeleteBookmark(ctx context.Context, bookmark *store.Bookmark) (bool, error) {
+	var deleted bool
+	err := s.tx(ctx, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, s.rebind(
+			`DELETE FROM bookmarks WHERE user_id = ? AND artwork_id = ?`,
+		), bookmark.UserID, bookmark.ArtworkID)
+		if err != nil {
+			return fmt.Errorf("failed to delete bookmark: %w", err)
+		}
+
+		if deleted, err = affected(res); err != nil || !deleted {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, s.rebind(`UPDATE artworks SET favourites = favourites - 1 WHERE artwork_id = ?`), bookmark.ArtworkID)
+		if err != nil {
+			return fmt.Errorf("failed to decrement artwork favorite count: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return deleted, nil
+}
+
+func affected(res sql.Result) (bool, error) {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return n > 0, nil
+}
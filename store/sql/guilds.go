@@ -0,0 +1,190 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/VTGare/boe-tea-go/store"
+)
+
+func (s *sqlStore) Guild(ctx context.Context, id string) (*store.Guild, error) {
+	// If guild ID is empty, return DM guild settings.
+	if id == "" {
+		return store.UserGuild(), nil
+	}
+
+	return s.guild(ctx, s.db, id, "")
+}
+
+func (s *sqlStore) CreateGuild(ctx context.Context, id string) (*store.Guild, error) {
+	guild := store.DefaultGuild(id)
+
+	data, err := json.Marshal(guild)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.db.ExecContext(ctx, s.rebind(`INSERT INTO guilds (guild_id, data) VALUES (?, ?)`), id, string(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert a guild: %w", err)
+	}
+
+	return guild, nil
+}
+
+func (s *sqlStore) UpdateGuild(ctx context.Context, guild *store.Guild) (*store.Guild, error) {
+	guild.UpdatedAt = time.Now()
+
+	data, err := json.Marshal(guild)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.db.ExecContext(ctx, s.rebind(`UPDATE guilds SET data = ? WHERE guild_id = ?`), string(data), guild.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update a guild: %w", err)
+	}
+
+	return guild, nil
+}
+
+func (s *sqlStore) AddArtChannels(ctx context.Context, guildID string, channels []string) (*store.Guild, error) {
+	return s.updateGuild(ctx, guildID, func(g *store.Guild) bool {
+		if slices.ContainsFunc(channels, func(c string) bool { return slices.Contains(g.ArtChannels, c) }) {
+			return false
+		}
+
+		for _, channel := range channels {
+			if !slices.Contains(g.ArtChannels, channel) {
+				g.ArtChannels = append(g.ArtChannels, channel)
+			}
+		}
+
+		return true
+	})
+}
+
+func (s *sqlStore) DeleteArtChannels(ctx context.Context, guildID string, channels []string) (*store.Guild, error) {
+	return s.updateGuild(ctx, guildID, func(g *store.Guild) bool {
+		if slices.ContainsFunc(channels, func(c string) bool { return !slices.Contains(g.ArtChannels, c) }) {
+			return false
+		}
+
+		g.ArtChannels = slices.DeleteFunc(g.ArtChannels, func(c string) bool {
+			return slices.Contains(channels, c)
+		})
+
+		return true
+	})
+}
+
+func (s *sqlStore) CreateGuildGroup(ctx context.Context, guildID string, group *store.Group) (*store.Guild, error) {
+	return s.updateGuild(ctx, guildID, func(g *store.Guild) bool {
+		if _, ok := g.FindGroupByName(group.Name); ok {
+			return false
+		}
+
+		if _, ok := g.FindGroup(group.Parent); ok {
+			return false
+		}
+
+		g.Groups = append(g.Groups, group)
+		return true
+	})
+}
+
+func (s *sqlStore) DeleteGuildGroup(ctx context.Context, guildID, group string) (*store.Guild, error) {
+	return s.updateGuild(ctx, guildID, func(g *store.Guild) bool {
+		if _, ok := g.FindGroupByName(group); !ok {
+			return false
+		}
+
+		g.Groups = slices.DeleteFunc(g.Groups, func(gr *store.Group) bool { return gr.Name == group })
+		return true
+	})
+}
+
+func (s *sqlStore) AddGuildGroupChannel(ctx context.Context, guildID, group, child string) (*store.Guild, error) {
+	return s.updateGuild(ctx, guildID, func(g *store.Guild) bool {
+		gr, ok := g.FindGroupByName(group)
+		if !ok {
+			return false
+		}
+
+		if !slices.Contains(gr.Children, child) {
+			gr.Children = append(gr.Children, child)
+		}
+
+		return true
+	})
+}
+
+func (s *sqlStore) DeleteGuildGroupChannel(ctx context.Context, guildID, group, child string) (*store.Guild, error) {
+	return s.updateGuild(ctx, guildID, func(g *store.Guild) bool {
+		gr, ok := g.FindGroupByName(group)
+		if !ok {
+			return false
+		}
+
+		gr.Children = slices.DeleteFunc(gr.Children, func(c string) bool { return c == child })
+		return true
+	})
+}
+
+// updateGuild applies fn to the locked guild and saves it if fn returns true.
+// Otherwise, store.ErrNotFound is returned the same way Mongo's conditional updates don't match any documents.
+func (s *sqlStore) updateGuild(ctx context.Context, guildID string, fn func(*store.Guild) bool) (*store.Guild, error) {
+	var guild *store.Guild
+	err := s.tx(ctx, func(tx *sql.Tx) error {
+		var err error
+		guild, err = s.guild(ctx, tx, guildID, s.forUpdate())
+		if err != nil {
+			return err
+		}
+
+		if !fn(guild) {
+			return fmt.Errorf("guild %v: %w", guildID, store.ErrNotFound)
+		}
+
+		data, err := json.Marshal(guild)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, s.rebind(`UPDATE guilds SET data = ? WHERE guild_id = ?`), string(data), guildID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return guild, nil
+}
+
+type querier interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func (s *sqlStore) guild(ctx context.Context, q querier, id, suffix string) (*store.Guild, error) {
+	var data []byte
+	err := q.QueryRowContext(ctx, s.rebind(`SELECT data FROM guilds WHERE guild_id = ?`+suffix), id).Scan(&data)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("guild %v: %w", id, store.ErrNotFound)
+		}
+
+		return nil, fmt.Errorf("failed to find a guild: %w", err)
+	}
+
+	var guild store.Guild
+	if err := json.Unmarshal(data, &guild); err != nil {
+		return nil, fmt.Errorf("failed to decode a guild: %w", err)
+	}
+
+	return &guild, nil
+}
@@ -0,0 +1,256 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/VTGare/boe-tea-go/store"
+)
+
+func (s *sqlStore) ExportGuilds(ctx context.Context, after string, limit int) ([]*store.Guild, error) {
+	rows, err := s.db.QueryContext(ctx, s.rebind(
+		`SELECT data FROM guilds WHERE guild_id > ? ORDER BY guild_id LIMIT ?`,
+	), after, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export guilds: %w", err)
+	}
+
+	return scanDocuments[store.Guild](rows)
+}
+
+func (s *sqlStore) ExportUsers(ctx context.Context, after string, limit int) ([]*store.User, error) {
+	rows, err := s.db.QueryContext(ctx, s.rebind(
+		`SELECT data FROM users WHERE user_id > ? ORDER BY user_id LIMIT ?`,
+	), after, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export users: %w", err)
+	}
+
+	return scanDocuments[store.User](rows)
+}
+
+func (s *sqlStore) ExportArtworks(ctx context.Context, after int, limit int) ([]*store.Artwork, error) {
+	rows, err := s.db.QueryContext(ctx, s.rebind(
+		`SELECT `+artworkColumns+` FROM artworks WHERE artwork_id > ? ORDER BY artwork_id LIMIT ?`,
+	), after, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export artworks: %w", err)
+	}
+	defer rows.Close()
+
+	artworks := make([]*store.Artwork, 0, limit)
+	for rows.Next() {
+		artwork, err := scanArtwork(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan an artwork: %w", err)
+		}
+
+		artworks = append(artworks, artwork)
+	}
+
+	return artworks, rows.Err()
+}
+
+func (s *sqlStore) ExportBookmarks(ctx context.Context, after store.BookmarkKey, limit int) ([]*store.Bookmark, error) {
+	rows, err := s.db.QueryContext(ctx, s.rebind(
+		`SELECT user_id, artwork_id, nsfw, created_at FROM bookmarks
+		WHERE user_id > ? OR (user_id = ? AND artwork_id > ?)
+		ORDER BY user_id, artwork_id LIMIT ?`,
+	), after.UserID, after.UserID, after.ArtworkID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export bookmarks: %w", err)
+	}
+	defer rows.Close()
+
+	bookmarks := make([]*store.Bookmark, 0, limit)
+	for rows.Next() {
+		var bookmark store.Bookmark
+		if err := rows.Scan(&bookmark.UserID, &bookmark.ArtworkID, &bookmark.NSFW, &bookmark.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan a bookmark: %w", err)
+		}
+
+		bookmarks = append(bookmarks, &bookmark)
+	}
+
+	return bookmarks, rows.Err()
+}
+
+func (s *sqlStore) ExportPosts(ctx context.Context, after store.PostKey, limit int) ([]*store.Post, error) {
+	rows, err := s.db.QueryContext(ctx, s.rebind(
+		`SELECT `+postColumns+` FROM posts
+		WHERE channel_id > ? OR (channel_id = ? AND message_id > ?)
+		ORDER BY channel_id, message_id LIMIT ?`,
+	), after.ChannelID, after.ChannelID, after.MessageID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export posts: %w", err)
+	}
+	defer rows.Close()
+
+	posts := make([]*store.Post, 0, limit)
+	for rows.Next() {
+		post, err := scanPost(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan a post: %w", err)
+		}
+
+		posts = append(posts, post)
+	}
+
+	return posts, rows.Err()
+}
+
+func (s *sqlStore) ImportGuilds(ctx context.Context, guilds []*store.Guild) error {
+	return s.tx(ctx, func(tx *sql.Tx) error {
+		for _, guild := range guilds {
+			data, err := json.Marshal(guild)
+			if err != nil {
+				return err
+			}
+
+			_, err = tx.ExecContext(ctx, s.rebind(
+				`INSERT INTO guilds (guild_id, data) VALUES (?, ?)
+				ON CONFLICT (guild_id) DO UPDATE SET data = excluded.data`,
+			), guild.ID, string(data))
+			if err != nil {
+				return fmt.Errorf("failed to import guild %v: %w", guild.ID, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (s *sqlStore) ImportUsers(ctx context.Context, users []*store.User) error {
+	return s.tx(ctx, func(tx *sql.Tx) error {
+		for _, user := range users {
+			data, err := json.Marshal(user)
+			if err != nil {
+				return err
+			}
+
+			_, err = tx.ExecContext(ctx, s.rebind(
+				`INSERT INTO users (user_id, data) VALUES (?, ?)
+				ON CONFLICT (user_id) DO UPDATE SET data = excluded.data`,
+			), user.ID, string(data))
+			if err != nil {
+				return fmt.Errorf("failed to import user %v: %w", user.ID, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (s *sqlStore) ImportArtworks(ctx context.Context, artworks []*store.Artwork) error {
+	return s.tx(ctx, func(tx *sql.Tx) error {
+		var maxID int
+		for _, artwork := range artworks {
+			images, err := json.Marshal(nonNil(artwork.Images))
+			if err != nil {
+				return err
+			}
+
+			_, err = tx.ExecContext(ctx, s.rebind(
+				`INSERT INTO artworks (`+artworkColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+				ON CONFLICT (artwork_id) DO UPDATE SET
+					title = excluded.title,
+					author = excluded.author,
+					url = excluded.url,
+					images = excluded.images,
+					favourites = excluded.favourites,
+					ai_generated = excluded.ai_generated,
+					created_at = excluded.created_at,
+					updated_at = excluded.updated_at`,
+			),
+				artwork.ID,
+				artwork.Title,
+				artwork.Author,
+				artwork.URL,
+				string(images),
+				artwork.Favorites,
+				artwork.AI,
+				artwork.CreatedAt.UTC(),
+				artwork.UpdatedAt.UTC(),
+			)
+			if err != nil {
+				return fmt.Errorf("failed to import artwork %v: %w", artwork.ID, err)
+			}
+
+			maxID = max(maxID, artwork.ID)
+		}
+
+		if maxID == 0 {
+			return nil
+		}
+
+		_, err := tx.ExecContext(ctx, s.rebind(
+			`INSERT INTO counters (name, counter) VALUES (?, ?)
+			ON CONFLICT (name) DO UPDATE SET counter = `+s.greatest("counters.counter", "excluded.counter"),
+		), "artworks", maxID)
+		if err != nil {
+			return fmt.Errorf("failed to update artwork counter: %w", err)
+		}
+
+		return nil
+	})
+}
+
+func (s *sqlStore) ImportBookmarks(ctx context.Context, bookmarks []*store.Bookmark) error {
+	return s.tx(ctx, func(tx *sql.Tx) error {
+		for _, bookmark := range bookmarks {
+			_, err := tx.ExecContext(ctx, s.rebind(
+				`INSERT INTO bookmarks (user_id, artwork_id, nsfw, created_at) VALUES (?, ?, ?, ?)
+				ON CONFLICT (user_id, artwork_id) DO UPDATE SET nsfw = excluded.nsfw, created_at = excluded.created_at`,
+			), bookmark.UserID, bookmark.ArtworkID, bookmark.NSFW, bookmark.CreatedAt.UTC())
+			if err != nil {
+				return fmt.Errorf("failed to import bookmark %v/%v: %w", bookmark.UserID, bookmark.ArtworkID, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (s *sqlStore) ImportPosts(ctx context.Context, posts []*store.Post) error {
+	return s.tx(ctx, func(tx *sql.Tx) error {
+		for _, post := range posts {
+			if err := s.savePost(ctx, tx, post); err != nil {
+				return fmt.Errorf("failed to import post %v/%v: %w", post.ChannelID, post.MessageID, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// greatest returns the larger of two expressions. SQLite uses multi-argument MAX instead of GREATEST.
+func (s *sqlStore) greatest(a, b string) string {
+	if s.dialect == Postgres {
+		return "GREATEST(" + a + ", " + b + ")"
+	}
+
+	return "MAX(" + a + ", " + b + ")"
+}
+
+func scanDocuments[T any](rows *sql.Rows) ([]*T, error) {
+	defer rows.Close()
+
+	docs := make([]*T, 0)
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+
+		var doc T
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, err
+		}
+
+		docs = append(docs, &doc)
+	}
+
+	return docs, rows.Err()
+}
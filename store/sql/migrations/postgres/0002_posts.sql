@@ -0,0 +1,12 @@
+CREATE TABLE posts (
+    channel_id TEXT NOT NULL,
+    message_id TEXT NOT NULL,
+    author_id  TEXT NOT NULL,
+    is_parent  BOOLEAN NOT NULL DEFAULT FALSE,
+    children   JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMPTZ NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (channel_id, message_id)
+);
+
+CREATE INDEX posts_expires_at_idx ON posts (expires_at);
@@ -0,0 +1 @@
+ALTER TABLE artworks ADD COLUMN ai_generated BOOLEAN NOT NULL DEFAULT FALSE;
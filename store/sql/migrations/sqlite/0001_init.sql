@@ -0,0 +1,39 @@
+CREATE TABLE counters (
+    name    TEXT PRIMARY KEY,
+    counter INTEGER NOT NULL
+);
+
+CREATE TABLE artworks (
+    artwork_id INTEGER PRIMARY KEY,
+    title      TEXT NOT NULL DEFAULT '',
+    author     TEXT NOT NULL DEFAULT '',
+    url        TEXT NOT NULL,
+    images     TEXT NOT NULL DEFAULT '[]',
+    favourites INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL
+);
+
+CREATE INDEX artworks_url_idx ON artworks (url);
+CREATE INDEX artworks_created_at_idx ON artworks (created_at);
+CREATE INDEX artworks_favourites_idx ON artworks (favourites);
+
+CREATE TABLE guilds (
+    guild_id TEXT PRIMARY KEY,
+    data     TEXT NOT NULL
+);
+
+CREATE TABLE users (
+    user_id TEXT PRIMARY KEY,
+    data    TEXT NOT NULL
+);
+
+CREATE TABLE bookmarks (
+    user_id    TEXT NOT NULL,
+    artwork_id INTEGER NOT NULL,
+    nsfw       BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (user_id, artwork_id)
+);
+
+CREATE INDEX bookmarks_user_created_at_idx ON bookmarks (user_id, created_at);
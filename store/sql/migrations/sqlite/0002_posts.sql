@@ -0,0 +1,12 @@
+CREATE TABLE posts (
+    channel_id TEXT NOT NULL,
+    message_id TEXT NOT NULL,
+    author_id  TEXT NOT NULL,
+    is_parent  BOOLEAN NOT NULL DEFAULT FALSE,
+    children   TEXT NOT NULL DEFAULT '[]',
+    created_at DATETIME NOT NULL,
+    expires_at DATETIME NOT NULL,
+    PRIMARY KEY (channel_id, message_id)
+);
+
+CREATE INDEX posts_expires_at_idx ON posts (expires_at);
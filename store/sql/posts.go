@@ -0,0 +1,125 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/VTGare/boe-tea-go/store"
+)
+
+func (s *sqlStore) Post(ctx context.Context, channelID, messageID string) (*store.Post, error) {
+	row := s.db.QueryRowContext(ctx, s.rebind(
+		`SELECT `+postColumns+` FROM posts WHERE channel_id = ? AND message_id = ? AND expires_at > ?`,
+	), channelID, messageID, time.Now().UTC())
+
+	post, err := scanPost(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %w", store.ErrPostNotFound, err)
+		}
+
+		return nil, fmt.Errorf("failed to find a post: %w", err)
+	}
+
+	return post, nil
+}
+
+func (s *sqlStore) SavePosts(ctx context.Context, posts ...*store.Post) error {
+	err := s.tx(ctx, func(tx *sql.Tx) error {
+		for _, post := range posts {
+			if err := s.savePost(ctx, tx, post); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save posts: %w", err)
+	}
+
+	return nil
+}
+
+func (s *sqlStore) DeletePost(ctx context.Context, channelID, messageID string) error {
+	_, err := s.db.ExecContext(ctx, s.rebind(
+		`DELETE FROM posts WHERE channel_id = ? AND message_id = ?`,
+	), channelID, messageID)
+	if err != nil {
+		return fmt.Errorf("failed to delete a post: %w", err)
+	}
+
+	return nil
+}
+
+func (s *sqlStore) DeleteExpiredPosts(ctx context.Context, now time.Time) (int64, error) {
+	res, err := s.db.ExecContext(ctx, s.rebind(`DELETE FROM posts WHERE expires_at <= ?`), now.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired posts: %w", err)
+	}
+
+	return res.RowsAffected()
+}
+
+const postColumns = `channel_id, message_id, author_id, is_parent, children, created_at, expires_at`
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+func (s *sqlStore) savePost(ctx context.Context, e execer, post *store.Post) error {
+	children, err := json.Marshal(nonNil(post.Children))
+	if err != nil {
+		return err
+	}
+
+	_, err = e.ExecContext(ctx, s.rebind(
+		`INSERT INTO posts (`+postColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (channel_id, message_id) DO UPDATE SET
+			author_id = excluded.author_id,
+			is_parent = excluded.is_parent,
+			children = excluded.children,
+			created_at = excluded.created_at,
+			expires_at = excluded.expires_at`,
+	),
+		post.ChannelID,
+		post.MessageID,
+		post.AuthorID,
+		post.IsParent,
+		string(children),
+		post.CreatedAt.UTC(),
+		post.ExpiresAt.UTC(),
+	)
+
+	return err
+}
+
+func scanPost(row scanner) (*store.Post, error) {
+	var (
+		post     store.Post
+		children []byte
+	)
+
+	err := row.Scan(
+		&post.ChannelID,
+		&post.MessageID,
+		&post.AuthorID,
+		&post.IsParent,
+		&children,
+		&post.CreatedAt,
+		&post.ExpiresAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(children, &post.Children); err != nil {
+		return nil, fmt.Errorf("failed to decode post children: %w", err)
+	}
+
+	return &post, nil
+}
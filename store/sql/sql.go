@@ -0,0 +1,171 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/VTGare/boe-tea-go/store"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "modernc.org/sqlite"
+)
+
+//go:embed migrations
+var migrations embed.FS
+
+// Supported SQL dialects.
+const (
+	SQLite   = "sqlite"
+	Postgres = "postgres"
+)
+
+// sqlStore implements store.Store on top of SQLite or PostgreSQL. Artworks and bookmarks are stored
+// in regular tables to allow searching and sorting, guilds and users are stored as JSON documents
+// the same way Mongo store keeps them.
+type sqlStore struct {
+	db      *sql.DB
+	dialect string
+}
+
+// New opens a database connection. Dialect is either "sqlite" or "postgres", dsn is passed to the driver as is.
+func New(ctx context.Context, dialect, dsn string) (store.Store, error) {
+	var driver string
+	switch dialect {
+	case SQLite:
+		driver = "sqlite"
+	case Postgres:
+		driver = "pgx"
+	default:
+		return nil, fmt.Errorf("unsupported sql dialect: %v", dialect)
+	}
+
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %v database: %w", dialect, err)
+	}
+
+	// SQLite allows a single writer, serialise all queries to avoid busy errors.
+	// It also keeps in-memory databases alive for the lifetime of the store.
+	if dialect == SQLite {
+		db.SetMaxOpenConns(1)
+	}
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to ping %v database: %w", dialect, err)
+	}
+
+	return &sqlStore{db: db, dialect: dialect}, nil
+}
+
+// Init applies embedded migrations that haven't been applied yet.
+func (s *sqlStore) Init(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER PRIMARY KEY)`)
+	if err != nil {
+		return fmt.Errorf("failed to create migrations table: %w", err)
+	}
+
+	dir := path.Join("migrations", s.dialect)
+	entries, err := fs.ReadDir(migrations, dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		version, err := strconv.Atoi(strings.SplitN(name, "_", 2)[0])
+		if err != nil {
+			return fmt.Errorf("invalid migration name %v: %w", name, err)
+		}
+
+		migration, err := migrations.ReadFile(path.Join(dir, name))
+		if err != nil {
+			return err
+		}
+
+		err = s.tx(ctx, func(tx *sql.Tx) error {
+			var applied int
+			err := tx.QueryRowContext(ctx, s.rebind(`SELECT COUNT(*) FROM schema_migrations WHERE version = ?`), version).Scan(&applied)
+			if err != nil || applied != 0 {
+				return err
+			}
+
+			if _, err := tx.ExecContext(ctx, string(migration)); err != nil {
+				return err
+			}
+
+			_, err = tx.ExecContext(ctx, s.rebind(`INSERT INTO schema_migrations (version) VALUES (?)`), version)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to apply migration %v: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *sqlStore) Close(context.Context) error {
+	return s.db.Close()
+}
+
+// tx runs fn in a transaction and commits it if fn doesn't return an error.
+func (s *sqlStore) tx(ctx context.Context, fn func(*sql.Tx) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin a transaction: %w", err)
+	}
+
+	if err := fn(tx); err != nil {
+		return errors.Join(err, tx.Rollback())
+	}
+
+	return tx.Commit()
+}
+
+// rebind replaces question mark placeholders with numbered ones for PostgreSQL.
+func (s *sqlStore) rebind(query string) string {
+	if s.dialect != Postgres {
+		return query
+	}
+
+	var (
+		sb strings.Builder
+		n  int
+	)
+
+	for _, r := range query {
+		if r != '?' {
+			sb.WriteRune(r)
+			continue
+		}
+
+		n++
+		sb.WriteString("$" + strconv.Itoa(n))
+	}
+
+	return sb.String()
+}
+
+// forUpdate locks selected rows until the end of transaction. SQLite doesn't support row locks,
+// but transactions are serialised anyway.
+func (s *sqlStore) forUpdate() string {
+	if s.dialect == Postgres {
+		return " FOR UPDATE"
+	}
+
+	return ""
+}
+
+// placeholders returns n comma-separated placeholders.
+func placeholders(n int) string {
+	return strings.Join(slices.Repeat([]string{"?"}, n), ", ")
+}
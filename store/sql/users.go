@@ -0,0 +1,219 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/VTGare/boe-tea-go/store"
+)
+
+func (s *sqlStore) User(ctx context.Context, userID string) (*store.User, error) {
+	data, err := json.Marshal(store.DefaultUser(userID))
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.db.ExecContext(ctx, s.rebind(
+		`INSERT INTO users (user_id, data) VALUES (?, ?) ON CONFLICT (user_id) DO NOTHING`,
+	), userID, string(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to upsert a user: %w", err)
+	}
+
+	return s.user(ctx, s.db, userID, "")
+}
+
+func (s *sqlStore) CreateUser(ctx context.Context, userID string) (*store.User, error) {
+	user := store.DefaultUser(userID)
+
+	data, err := json.Marshal(user)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.db.ExecContext(ctx, s.rebind(`INSERT INTO users (user_id, data) VALUES (?, ?)`), userID, string(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert a user: %w", err)
+	}
+
+	return user, nil
+}
+
+func (s *sqlStore) UpdateUser(ctx context.Context, user *store.User) (*store.User, error) {
+	user.UpdatedAt = time.Now()
+
+	data, err := json.Marshal(user)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.db.ExecContext(ctx, s.rebind(`UPDATE users SET data = ? WHERE user_id = ?`), string(data), user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update a user: %w", err)
+	}
+
+	return user, nil
+}
+
+func (s *sqlStore) CreateCrosspostGroup(ctx context.Context, userID string, group *store.Group) (*store.User, error) {
+	return s.updateUser(ctx, userID, func(u *store.User) bool {
+		if hasGroup(u, group.Name) || slices.ContainsFunc(u.Groups, func(g *store.Group) bool { return g.Parent == group.Parent }) {
+			return false
+		}
+
+		u.Groups = append(u.Groups, group)
+		return true
+	})
+}
+
+func (s *sqlStore) CreateCrosspostPair(ctx context.Context, userID string, pair *store.Group) (*store.User, error) {
+	return s.updateUser(ctx, userID, func(u *store.User) bool {
+		if hasGroup(u, pair.Name) || slices.ContainsFunc(u.Groups, func(g *store.Group) bool { return slices.Contains(pair.Children, g.Parent) }) {
+			return false
+		}
+
+		u.Groups = append(u.Groups, pair)
+		return true
+	})
+}
+
+func (s *sqlStore) DeleteCrosspostGroup(ctx context.Context, userID, group string) (*store.User, error) {
+	return s.updateUser(ctx, userID, func(u *store.User) bool {
+		if !hasGroup(u, group) {
+			return false
+		}
+
+		u.Groups = slices.DeleteFunc(u.Groups, func(g *store.Group) bool { return g.Name == group })
+		return true
+	})
+}
+
+func (s *sqlStore) RenameCrosspostGroup(ctx context.Context, userID, group, rename string) (*store.User, error) {
+	return s.updateUser(ctx, userID, func(u *store.User) bool {
+		if hasGroup(u, rename) {
+			return false
+		}
+
+		g, ok := u.FindGroupByName(group)
+		if !ok {
+			return false
+		}
+
+		g.Name = rename
+		return true
+	})
+}
+
+func (s *sqlStore) AddCrosspostChannel(ctx context.Context, userID, group, child string) (*store.User, error) {
+	return s.updateUser(ctx, userID, func(u *store.User) bool {
+		g, ok := u.FindGroupByName(group)
+		if !ok {
+			return false
+		}
+
+		if !slices.Contains(g.Children, child) {
+			g.Children = append(g.Children, child)
+		}
+
+		return true
+	})
+}
+
+func (s *sqlStore) DeleteCrosspostChannel(ctx context.Context, userID, group, child string) (*store.User, error) {
+	return s.updateUser(ctx, userID, func(u *store.User) bool {
+		g, ok := u.FindGroupByName(group)
+		if !ok {
+			return false
+		}
+
+		g.Children = slices.DeleteFunc(g.Children, func(c string) bool { return c == child })
+		return true
+	})
+}
+
+func (s *sqlStore) EditCrosspostParent(ctx context.Context, userID, group, parent string) (*store.User, error) {
+	return s.updateUser(ctx, userID, func(u *store.User) bool {
+		if slices.ContainsFunc(u.Groups, func(g *store.Group) bool { return g.Parent == parent }) {
+			return false
+		}
+
+		g, ok := u.FindGroupByName(group)
+		if !ok {
+			return false
+		}
+
+		g.Parent = parent
+		return true
+	})
+}
+
+func (s *sqlStore) EditCrosspostFilter(ctx context.Context, userID, group string, filter store.GroupFilter) (*store.User, error) {
+	return s.updateUser(ctx, userID, func(u *store.User) bool {
+		g, ok := u.FindGroupByName(group)
+		if !ok {
+			return false
+		}
+
+		g.Filter = filter
+		return true
+	})
+}
+
+// updateUser applies fn to the locked user and saves it if fn returns true.
+// Otherwise, store.ErrNotFound is returned the same way Mongo's conditional updates don't match any documents.
+func (s *sqlStore) updateUser(ctx context.Context, userID string, fn func(*store.User) bool) (*store.User, error) {
+	var user *store.User
+	err := s.tx(ctx, func(tx *sql.Tx) error {
+		var err error
+		user, err = s.user(ctx, tx, userID, s.forUpdate())
+		if err != nil {
+			return err
+		}
+
+		if !fn(user) {
+			return fmt.Errorf("user %v: %w", userID, store.ErrNotFound)
+		}
+
+		data, err := json.Marshal(user)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, s.rebind(`UPDATE users SET data = ? WHERE user_id = ?`), string(data), userID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (s *sqlStore) user(ctx context.Context, q querier, id, suffix string) (*store.User, error) {
+	var data []byte
+	err := q.QueryRowContext(ctx, s.rebind(`SELECT data FROM users WHERE user_id = ?`+suffix), id).Scan(&data)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("user %v: %w", id, store.ErrNotFound)
+		}
+
+		return nil, fmt.Errorf("failed to find a user: %w", err)
+	}
+
+	var user store.User
+	if err := json.Unmarshal(data, &user); err != nil {
+		return nil, fmt.Errorf("failed to decode a user: %w", err)
+	}
+
+	return &user, nil
+}
+
+func hasGroup(u *store.User, name string) bool {
+	_, ok := u.FindGroupByName(name)
+	return ok
+}
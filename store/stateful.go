@@ -4,101 +4,150 @@ import (
 	"context"
 	"sort"
 	"strconv"
+	"time"
 
+	"github.com/VTGare/boe-tea-go/internal/cache"
 	"github.com/julien040/go-ternary"
-	cache "github.com/patrickmn/go-cache"
 )
 
+// StatefulStore caches guilds, users and artworks. Every write invalidates cached entries instead of updating them,
+// so instances sharing a cache backend never read stale settings after a change made by another instance.
 type StatefulStore struct {
 	Store
-	cache *cache.Cache
+	cache cache.Backend
+	ttl   time.Duration
 }
 
-func NewStatefulStore(store Store, c *cache.Cache) Store {
+func NewStatefulStore(store Store, c cache.Backend, ttl time.Duration) Store {
 	return &StatefulStore{
 		Store: store,
 		cache: c,
+		ttl:   ttl,
 	}
 }
 
 func (s *StatefulStore) Guild(ctx context.Context, guildID string) (*Guild, error) {
-	if g, ok := s.cache.Get("guilds:" + guildID); ok {
-		guild := g.(*Guild)
-		return guild, nil
-	}
+	return cached(ctx, s, "guilds:"+guildID, func() (*Guild, error) {
+		return s.Store.Guild(ctx, guildID)
+	})
+}
 
-	guild, err := s.Store.Guild(ctx, guildID)
-	if err != nil {
-		return nil, err
-	}
+func (s *StatefulStore) CreateGuild(ctx context.Context, guildID string) (*Guild, error) {
+	defer s.invalidate(ctx, "guilds:"+guildID)
+	return s.Store.CreateGuild(ctx, guildID)
+}
 
-	s.cache.Set("guilds:"+guildID, guild, 0)
-	return guild, nil
+func (s *StatefulStore) UpdateGuild(ctx context.Context, guild *Guild) (*Guild, error) {
+	defer s.invalidate(ctx, "guilds:"+guild.ID)
+	return s.Store.UpdateGuild(ctx, guild)
 }
 
-func (s *StatefulStore) CreateGuild(ctx context.Context, guildID string) (*Guild, error) {
-	guild, err := s.Store.CreateGuild(ctx, guildID)
-	if err != nil {
-		return nil, err
-	}
+func (s *StatefulStore) AddArtChannels(ctx context.Context, guildID string, channels []string) (*Guild, error) {
+	defer s.invalidate(ctx, "guilds:"+guildID)
+	return s.Store.AddArtChannels(ctx, guildID, channels)
+}
 
-	s.cache.Set("guilds:"+guildID, guild, 0)
-	return guild, nil
+func (s *StatefulStore) DeleteArtChannels(ctx context.Context, guildID string, channels []string) (*Guild, error) {
+	defer s.invalidate(ctx, "guilds:"+guildID)
+	return s.Store.DeleteArtChannels(ctx, guildID, channels)
 }
 
-func (s *StatefulStore) UpdateGuild(ctx context.Context, guild *Guild) (*Guild, error) {
-	guild, err := s.Store.UpdateGuild(ctx, guild)
-	if err != nil {
-		return nil, err
-	}
+func (s *StatefulStore) CreateGuildGroup(ctx context.Context, guildID string, group *Group) (*Guild, error) {
+	defer s.invalidate(ctx, "guilds:"+guildID)
+	return s.Store.CreateGuildGroup(ctx, guildID, group)
+}
 
-	s.cache.Set("guilds:"+guild.ID, guild, 0)
-	return guild, nil
+func (s *StatefulStore) DeleteGuildGroup(ctx context.Context, guildID string, group string) (*Guild, error) {
+	defer s.invalidate(ctx, "guilds:"+guildID)
+	return s.Store.DeleteGuildGroup(ctx, guildID, group)
 }
 
-func (s *StatefulStore) AddArtChannels(ctx context.Context, guildID string, channels []string) (*Guild, error) {
-	guild, err := s.Store.AddArtChannels(ctx, guildID, channels)
-	if err != nil {
-		return nil, err
-	}
+func (s *StatefulStore) AddGuildGroupChannel(ctx context.Context, guildID string, group string, child string) (*Guild, error) {
+	defer s.invalidate(ctx, "guilds:"+guildID)
+	return s.Store.AddGuildGroupChannel(ctx, guildID, group, child)
+}
 
-	s.cache.Set("guilds:"+guild.ID, guild, 0)
-	return guild, nil
+func (s *StatefulStore) DeleteGuildGroupChannel(ctx context.Context, guildID string, group string, child string) (*Guild, error) {
+	defer s.invalidate(ctx, "guilds:"+guildID)
+	return s.Store.DeleteGuildGroupChannel(ctx, guildID, group, child)
 }
 
-func (s *StatefulStore) DeleteArtChannels(ctx context.Context, guildID string, channels []string) (*Guild, error) {
-	guild, err := s.Store.DeleteArtChannels(ctx, guildID, channels)
-	if err != nil {
-		return nil, err
-	}
+func (s *StatefulStore) User(ctx context.Context, userID string) (*User, error) {
+	return cached(ctx, s, "users:"+userID, func() (*User, error) {
+		return s.Store.User(ctx, userID)
+	})
+}
 
-	s.cache.Set("guilds:"+guild.ID, guild, 0)
-	return guild, nil
+func (s *StatefulStore) CreateUser(ctx context.Context, userID string) (*User, error) {
+	defer s.invalidate(ctx, "users:"+userID)
+	return s.Store.CreateUser(ctx, userID)
 }
 
-func (s *StatefulStore) Artwork(ctx context.Context, id int, url string) (*Artwork, error) {
-	if a, ok := s.cache.Get("artworks:" + strconv.Itoa(id)); ok {
-		artwork := a.(*Artwork)
-		return artwork, nil
-	}
+func (s *StatefulStore) UpdateUser(ctx context.Context, user *User) (*User, error) {
+	defer s.invalidate(ctx, "users:"+user.ID)
+	return s.Store.UpdateUser(ctx, user)
+}
 
-	artwork, err := s.Store.Artwork(ctx, id, url)
-	if err != nil {
-		return nil, err
-	}
+func (s *StatefulStore) CreateCrosspostGroup(ctx context.Context, userID string, group *Group) (*User, error) {
+	defer s.invalidate(ctx, "users:"+userID)
+	return s.Store.CreateCrosspostGroup(ctx, userID, group)
+}
 
-	s.cache.Set("artworks:"+strconv.Itoa(artwork.ID), artwork, 0)
-	return artwork, nil
+func (s *StatefulStore) CreateCrosspostPair(ctx context.Context, userID string, pair *Group) (*User, error) {
+	defer s.invalidate(ctx, "users:"+userID)
+	return s.Store.CreateCrosspostPair(ctx, userID, pair)
 }
 
-func (s *StatefulStore) CreateArtwork(ctx context.Context, a *Artwork) (*Artwork, error) {
-	artwork, err := s.Store.CreateArtwork(ctx, a)
-	if err != nil {
-		return nil, err
+func (s *StatefulStore) DeleteCrosspostGroup(ctx context.Context, userID string, group string) (*User, error) {
+	defer s.invalidate(ctx, "users:"+userID)
+	return s.Store.DeleteCrosspostGroup(ctx, userID, group)
+}
+
+func (s *StatefulStore) EditCrosspostParent(ctx context.Context, userID string, group string, parent string) (*User, error) {
+	defer s.invalidate(ctx, "users:"+userID)
+	return s.Store.EditCrosspostParent(ctx, userID, group, parent)
+}
+
+func (s *StatefulStore) RenameCrosspostGroup(ctx context.Context, userID string, name string, newName string) (*User, error) {
+	defer s.invalidate(ctx, "users:"+userID)
+	return s.Store.RenameCrosspostGroup(ctx, userID, name, newName)
+}
+
+func (s *StatefulStore) AddCrosspostChannel(ctx context.Context, userID string, group string, child string) (*User, error) {
+	defer s.invalidate(ctx, "users:"+userID)
+	return s.Store.AddCrosspostChannel(ctx, userID, group, child)
+}
+
+func (s *StatefulStore) DeleteCrosspostChannel(ctx context.Context, userID string, group string, child string) (*User, error) {
+	defer s.invalidate(ctx, "users:"+userID)
+	return s.Store.DeleteCrosspostChannel(ctx, userID, group, child)
+}
+
+func (s *StatefulStore) EditCrosspostFilter(ctx context.Context, userID string, group string, filter GroupFilter) (*User, error) {
+	defer s.invalidate(ctx, "users:"+userID)
+	return s.Store.EditCrosspostFilter(ctx, userID, group, filter)
+}
+
+func (s *StatefulStore) Artwork(ctx context.Context, id int, url string) (*Artwork, error) {
+	// Artworks are cached by ID only, lookups by URL always hit the store.
+	if id == 0 {
+		return s.Store.Artwork(ctx, id, url)
 	}
 
-	s.cache.Set("artworks:"+strconv.Itoa(artwork.ID), artwork, 0)
-	return artwork, nil
+	return cached(ctx, s, "artworks:"+strconv.Itoa(id), func() (*Artwork, error) {
+		return s.Store.Artwork(ctx, id, url)
+	})
+}
+
+func (s *StatefulStore) AddBookmark(ctx context.Context, fav *Bookmark) (bool, error) {
+	// Bookmarks change artwork's favourite count.
+	defer s.invalidate(ctx, "artworks:"+strconv.Itoa(fav.ArtworkID))
+	return s.Store.AddBookmark(ctx, fav)
+}
+
+func (s *StatefulStore) DeleteBookmark(ctx context.Context, fav *Bookmark) (bool, error) {
+	defer s.invalidate(ctx, "artworks:"+strconv.Itoa(fav.ArtworkID))
+	return s.Store.DeleteBookmark(ctx, fav)
 }
 
 func (s *StatefulStore) SearchArtworks(ctx context.Context, filter ArtworkFilter, opts ...ArtworkSearchOptions) ([]*Artwork, error) {
@@ -118,13 +167,13 @@ func (s *StatefulStore) SearchArtworks(ctx context.Context, filter ArtworkFilter
 	)
 
 	for _, id := range filter.IDs {
-		i, ok := s.cache.Get("artworks:" + strconv.Itoa(id))
-		if !ok {
+		artwork, err := cache.GetJSON[Artwork](ctx, s.cache, "artworks:"+strconv.Itoa(id))
+		if err != nil {
 			newIDs = append(newIDs, id)
 			continue
 		}
 
-		artworks = append(artworks, i.(*Artwork))
+		artworks = append(artworks, artwork)
 	}
 
 	if len(newIDs) != 0 {
@@ -135,7 +184,7 @@ func (s *StatefulStore) SearchArtworks(ctx context.Context, filter ArtworkFilter
 		}
 
 		for _, artwork := range newArtworks {
-			s.cache.Set("artworks:"+strconv.Itoa(artwork.ID), artwork, 0)
+			cache.SetJSON(ctx, s.cache, "artworks:"+strconv.Itoa(artwork.ID), artwork, s.ttl)
 		}
 
 		artworks = append(artworks, newArtworks...)
@@ -162,3 +211,23 @@ func (s *StatefulStore) SearchArtworks(ctx context.Context, filter ArtworkFilter
 
 	return artworks, nil
 }
+
+// cached returns a cached value or fetches and caches it. Cache errors are treated as misses.
+func cached[T any](ctx context.Context, s *StatefulStore, key string, fetch func() (*T, error)) (*T, error) {
+	if v, err := cache.GetJSON[T](ctx, s.cache, key); err == nil {
+		return v, nil
+	}
+
+	v, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+
+	cache.SetJSON(ctx, s.cache, key, v, s.ttl)
+	return v, nil
+}
+
+// invalidate removes keys from the cache. A failed invalidation leaves a stale entry until it expires.
+func (s *StatefulStore) invalidate(ctx context.Context, keys ...string) {
+	s.cache.Delete(ctx, keys...)
+}
@@ -10,8 +10,15 @@ type Store interface {
 	GuildStore
 	UserStore
 	BookmarkStore
+	PostStore
 	Init(context.Context) error
 	Close(context.Context) error
 }
 
-var ErrArtworkNotFound = errors.New("artwork not found")
+var (
+	// ErrNotFound is returned when a guild or a user doesn't exist, or a conditional update didn't match anything
+	// (e.g. adding an art channel that is already added or creating a crosspost group with a taken name).
+	ErrNotFound        = errors.New("not found")
+	ErrArtworkNotFound = errors.New("artwork not found")
+	ErrPostNotFound    = errors.New("post not found")
+)
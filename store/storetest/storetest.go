@@ -0,0 +1,488 @@
+// Package storetest provides a conformance test suite shared by store.Store implementations.
+package storetest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/VTGare/boe-tea-go/store"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// Conformance registers specs that every store.Store implementation must pass.
+// newStore is called before each spec and must return an empty, initialised store.
+func Conformance(newStore func() store.Store) {
+	var (
+		s   store.Store
+		ctx context.Context
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		s = newStore()
+	})
+
+	createArtwork := func(title, author string) *store.Artwork {
+		artwork, err := s.CreateArtwork(ctx, &store.Artwork{
+			Title:  title,
+			Author: author,
+			URL:    fmt.Sprintf("https://example.com/%v/%v", author, title),
+			Images: []string{"https://example.com/image.png"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		// Mongo stores timestamps with millisecond precision, make sure sorting by time is deterministic.
+		time.Sleep(2 * time.Millisecond)
+		return artwork
+	}
+
+	Describe("ArtworkStore", func() {
+		It("should auto-increment artwork IDs", func() {
+			first := createArtwork("first", "author")
+			second := createArtwork("second", "author")
+
+			Expect(first.ID).To(BeNumerically(">", 0))
+			Expect(second.ID).To(Equal(first.ID + 1))
+			Expect(first.CreatedAt).NotTo(BeZero())
+		})
+
+		It("should find artworks by ID or URL", func() {
+			artwork := createArtwork("title", "author")
+
+			byID, err := s.Artwork(ctx, artwork.ID, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(byID.URL).To(Equal(artwork.URL))
+			Expect(byID.Images).To(Equal(artwork.Images))
+
+			byURL, err := s.Artwork(ctx, 0, artwork.URL)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(byURL.ID).To(Equal(artwork.ID))
+		})
+
+		It("should return ErrArtworkNotFound", func() {
+			_, err := s.Artwork(ctx, 1000, "")
+			Expect(err).To(MatchError(store.ErrArtworkNotFound))
+
+			_, err = s.Artwork(ctx, 0, "https://example.com/none")
+			Expect(err).To(MatchError(store.ErrArtworkNotFound))
+		})
+
+		It("should search artworks", func() {
+			first := createArtwork("Blue Sky", "Alice")
+			second := createArtwork("Red Sun", "Bob")
+			third := createArtwork("Green Field", "alice")
+
+			res, err := s.SearchArtworks(ctx, store.ArtworkFilter{IDs: []int{first.ID, third.ID}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ids(res)).To(Equal([]int{third.ID, first.ID}))
+
+			res, err = s.SearchArtworks(ctx, store.ArtworkFilter{URL: second.URL})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ids(res)).To(Equal([]int{second.ID}))
+
+			res, err = s.SearchArtworks(ctx, store.ArtworkFilter{Author: "ALICE"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ids(res)).To(Equal([]int{third.ID, first.ID}))
+
+			res, err = s.SearchArtworks(ctx, store.ArtworkFilter{Query: "sun"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ids(res)).To(Equal([]int{second.ID}))
+
+			res, err = s.SearchArtworks(ctx, store.ArtworkFilter{Author: "alice", Title: "field"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ids(res)).To(Equal([]int{third.ID}))
+		})
+
+		It("should exclude AI-generated artworks", func() {
+			human := createArtwork("Sketch", "Alice")
+			generated, err := s.CreateArtwork(ctx, &store.Artwork{Title: "Render", Author: "Bot", URL: "https://example.com/ai", AI: true})
+			Expect(err).NotTo(HaveOccurred())
+
+			artwork, err := s.Artwork(ctx, generated.ID, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(artwork.AI).To(BeTrue())
+
+			res, err := s.SearchArtworks(ctx, store.ArtworkFilter{ExcludeAI: true})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ids(res)).To(Equal([]int{human.ID}))
+
+			res, err = s.SearchArtworks(ctx, store.ArtworkFilter{Time: time.Hour, ExcludeAI: true})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ids(res)).To(Equal([]int{human.ID}))
+		})
+
+		It("should sort, skip and limit search results", func() {
+			first := createArtwork("first", "author")
+			second := createArtwork("second", "author")
+			createArtwork("third", "author")
+
+			_, err := s.AddBookmark(ctx, &store.Bookmark{UserID: "1", ArtworkID: second.ID, CreatedAt: time.Now()})
+			Expect(err).NotTo(HaveOccurred())
+
+			res, err := s.SearchArtworks(ctx, store.ArtworkFilter{}, store.ArtworkSearchOptions{
+				Limit: 2,
+				Order: store.Ascending,
+				Sort:  store.ByTime,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ids(res)).To(Equal([]int{first.ID, second.ID}))
+
+			res, err = s.SearchArtworks(ctx, store.ArtworkFilter{}, store.ArtworkSearchOptions{
+				Limit: 10,
+				Skip:  1,
+				Order: store.Descending,
+				Sort:  store.ByTime,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ids(res)).To(Equal([]int{second.ID, first.ID}))
+
+			res, err = s.SearchArtworks(ctx, store.ArtworkFilter{}, store.ArtworkSearchOptions{
+				Limit: 1,
+				Order: store.Descending,
+				Sort:  store.ByPopularity,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ids(res)).To(Equal([]int{second.ID}))
+		})
+	})
+
+	Describe("GuildStore", func() {
+		It("should return DM settings for an empty guild ID", func() {
+			guild, err := s.Guild(ctx, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(guild).To(Equal(store.UserGuild()))
+		})
+
+		It("should return ErrNotFound for unknown guilds", func() {
+			_, err := s.Guild(ctx, "1")
+			Expect(err).To(MatchError(store.ErrNotFound))
+		})
+
+		It("should create and update guilds", func() {
+			guild, err := s.CreateGuild(ctx, "1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(guild.Prefix).To(Equal("bt!"))
+
+			guild.Prefix = "b."
+			guild.Limit = 5
+			guild.Blacklist = store.Blacklist{Tags: []string{"guro"}, Mode: store.BlacklistSpoiler}
+			_, err = s.UpdateGuild(ctx, guild)
+			Expect(err).NotTo(HaveOccurred())
+
+			guild, err = s.Guild(ctx, "1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(guild.Prefix).To(Equal("b."))
+			Expect(guild.Limit).To(Equal(5))
+			Expect(guild.Blacklist).To(Equal(store.Blacklist{Tags: []string{"guro"}, Mode: store.BlacklistSpoiler}))
+		})
+
+		It("should add and remove art channels", func() {
+			_, err := s.CreateGuild(ctx, "1")
+			Expect(err).NotTo(HaveOccurred())
+
+			guild, err := s.AddArtChannels(ctx, "1", []string{"10", "11"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(guild.ArtChannels).To(ConsistOf("10", "11"))
+
+			_, err = s.AddArtChannels(ctx, "1", []string{"11", "12"})
+			Expect(err).To(MatchError(store.ErrNotFound))
+
+			guild, err = s.DeleteArtChannels(ctx, "1", []string{"10"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(guild.ArtChannels).To(ConsistOf("11"))
+
+			_, err = s.DeleteArtChannels(ctx, "1", []string{"10"})
+			Expect(err).To(MatchError(store.ErrNotFound))
+		})
+
+		It("should manage guild crosspost groups", func() {
+			_, err := s.CreateGuild(ctx, "1")
+			Expect(err).NotTo(HaveOccurred())
+
+			guild, err := s.CreateGuildGroup(ctx, "1", &store.Group{Name: "archive", Parent: "10", Children: []string{}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(guild.Groups).To(HaveLen(1))
+
+			_, err = s.CreateGuildGroup(ctx, "1", &store.Group{Name: "archive", Parent: "11", Children: []string{}})
+			Expect(err).To(MatchError(store.ErrNotFound))
+
+			_, err = s.CreateGuildGroup(ctx, "1", &store.Group{Name: "mirror", Parent: "10", Children: []string{}})
+			Expect(err).To(MatchError(store.ErrNotFound))
+
+			_, err = s.AddGuildGroupChannel(ctx, "1", "archive", "12")
+			Expect(err).NotTo(HaveOccurred())
+
+			guild, err = s.Guild(ctx, "1")
+			Expect(err).NotTo(HaveOccurred())
+
+			group, ok := guild.FindGroup("10")
+			Expect(ok).To(BeTrue())
+			Expect(group.Children).To(ConsistOf("12"))
+
+			guild, err = s.DeleteGuildGroupChannel(ctx, "1", "archive", "12")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(guild.Groups[0].Children).To(BeEmpty())
+
+			guild, err = s.DeleteGuildGroup(ctx, "1", "archive")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(guild.Groups).To(BeEmpty())
+
+			_, err = s.DeleteGuildGroup(ctx, "1", "archive")
+			Expect(err).To(MatchError(store.ErrNotFound))
+		})
+	})
+
+	Describe("UserStore", func() {
+		It("should create a default user on lookup", func() {
+			user, err := s.User(ctx, "1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(user.ID).To(Equal("1"))
+			Expect(user.DM).To(BeTrue())
+			Expect(user.Groups).To(BeEmpty())
+		})
+
+		It("should update users", func() {
+			user, err := s.User(ctx, "1")
+			Expect(err).NotTo(HaveOccurred())
+
+			user.DM = false
+			user.Blacklist.Add("guro")
+			_, err = s.UpdateUser(ctx, user)
+			Expect(err).NotTo(HaveOccurred())
+
+			user, err = s.User(ctx, "1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(user.DM).To(BeFalse())
+			Expect(user.Blacklist.Tags).To(Equal([]string{"guro"}))
+		})
+
+		It("should manage crosspost groups", func() {
+			_, err := s.User(ctx, "1")
+			Expect(err).NotTo(HaveOccurred())
+
+			user, err := s.CreateCrosspostGroup(ctx, "1", &store.Group{Name: "art", Parent: "10", Children: []string{}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(user.Groups).To(HaveLen(1))
+
+			_, err = s.CreateCrosspostGroup(ctx, "1", &store.Group{Name: "art", Parent: "20", Children: []string{}})
+			Expect(err).To(MatchError(store.ErrNotFound))
+
+			_, err = s.CreateCrosspostGroup(ctx, "1", &store.Group{Name: "other", Parent: "10", Children: []string{}})
+			Expect(err).To(MatchError(store.ErrNotFound))
+
+			_, err = s.AddCrosspostChannel(ctx, "1", "art", "11")
+			Expect(err).NotTo(HaveOccurred())
+
+			user, err = s.AddCrosspostChannel(ctx, "1", "art", "11")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(user.Groups[0].Children).To(Equal([]string{"11"}))
+
+			user, err = s.EditCrosspostParent(ctx, "1", "art", "12")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(user.Groups[0].Parent).To(Equal("12"))
+
+			user, err = s.RenameCrosspostGroup(ctx, "1", "art", "lewds")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(user.Groups[0].Name).To(Equal("lewds"))
+
+			user, err = s.DeleteCrosspostChannel(ctx, "1", "lewds", "11")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(user.Groups[0].Children).To(BeEmpty())
+
+			user, err = s.DeleteCrosspostGroup(ctx, "1", "lewds")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(user.Groups).To(BeEmpty())
+
+			_, err = s.DeleteCrosspostGroup(ctx, "1", "lewds")
+			Expect(err).To(MatchError(store.ErrNotFound))
+		})
+
+		It("should not create a pair with a used parent", func() {
+			_, err := s.User(ctx, "1")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = s.CreateCrosspostGroup(ctx, "1", &store.Group{Name: "art", Parent: "10", Children: []string{}})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = s.CreateCrosspostPair(ctx, "1", &store.Group{Name: "pair", Children: []string{"10", "11"}, IsPair: true})
+			Expect(err).To(MatchError(store.ErrNotFound))
+
+			user, err := s.CreateCrosspostPair(ctx, "1", &store.Group{Name: "pair", Children: []string{"11", "12"}, IsPair: true})
+			Expect(err).NotTo(HaveOccurred())
+
+			group, ok := user.FindGroup("12")
+			Expect(ok).To(BeTrue())
+			Expect(group.Name).To(Equal("pair"))
+		})
+
+		It("should edit group filters", func() {
+			_, err := s.User(ctx, "1")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = s.CreateCrosspostGroup(ctx, "1", &store.Group{Name: "art", Parent: "10", Children: []string{"11"}})
+			Expect(err).NotTo(HaveOccurred())
+
+			filter := store.GroupFilter{
+				Providers:   []string{"pixiv"},
+				Content:     store.GroupContentSFW,
+				ExcludeTags: []string{"guro"},
+				ExcludeAI:   true,
+			}
+
+			_, err = s.EditCrosspostFilter(ctx, "1", "art", filter)
+			Expect(err).NotTo(HaveOccurred())
+
+			user, err := s.User(ctx, "1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(user.Groups[0].Filter).To(Equal(filter))
+
+			_, err = s.EditCrosspostFilter(ctx, "1", "missing", filter)
+			Expect(err).To(MatchError(store.ErrNotFound))
+		})
+	})
+
+	Describe("BookmarkStore", func() {
+		It("should add and delete bookmarks maintaining favourite counters", func() {
+			artwork := createArtwork("title", "author")
+			bookmark := &store.Bookmark{UserID: "1", ArtworkID: artwork.ID, CreatedAt: time.Now()}
+
+			added, err := s.AddBookmark(ctx, bookmark)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(added).To(BeTrue())
+
+			added, err = s.AddBookmark(ctx, bookmark)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(added).To(BeFalse())
+
+			artwork, err = s.Artwork(ctx, artwork.ID, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(artwork.Favorites).To(Equal(1))
+
+			count, err := s.CountBookmarks(ctx, "1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(BeEquivalentTo(1))
+
+			deleted, err := s.DeleteBookmark(ctx, bookmark)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleted).To(BeTrue())
+
+			deleted, err = s.DeleteBookmark(ctx, bookmark)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleted).To(BeFalse())
+
+			artwork, err = s.Artwork(ctx, artwork.ID, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(artwork.Favorites).To(Equal(0))
+		})
+
+		It("should filter and order bookmarks", func() {
+			first := createArtwork("first", "author")
+			second := createArtwork("second", "author")
+			now := time.Now()
+
+			_, err := s.AddBookmark(ctx, &store.Bookmark{UserID: "1", ArtworkID: first.ID, CreatedAt: now.Add(-time.Hour)})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = s.AddBookmark(ctx, &store.Bookmark{UserID: "1", ArtworkID: second.ID, NSFW: true, CreatedAt: now})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = s.AddBookmark(ctx, &store.Bookmark{UserID: "2", ArtworkID: second.ID, CreatedAt: now})
+			Expect(err).NotTo(HaveOccurred())
+
+			bookmarks, err := s.ListBookmarks(ctx, "1", store.BookmarkFilterAll, store.Descending)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(artworkIDs(bookmarks)).To(Equal([]int{second.ID, first.ID}))
+
+			bookmarks, err = s.ListBookmarks(ctx, "1", store.BookmarkFilterAll, store.Ascending)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(artworkIDs(bookmarks)).To(Equal([]int{first.ID, second.ID}))
+
+			bookmarks, err = s.ListBookmarks(ctx, "1", store.BookmarkFilterSafe, store.Descending)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(artworkIDs(bookmarks)).To(Equal([]int{first.ID}))
+
+			bookmarks, err = s.ListBookmarks(ctx, "1", store.BookmarkFilterUnsafe, store.Descending)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(artworkIDs(bookmarks)).To(Equal([]int{second.ID}))
+		})
+	})
+
+	Describe("PostStore", func() {
+		newPost := func(messageID string, expiresAt time.Time) *store.Post {
+			return &store.Post{
+				ChannelID: "1",
+				MessageID: messageID,
+				AuthorID:  "2",
+				IsParent:  true,
+				Children: []*store.PostMessage{
+					{ChannelID: "3", MessageID: "4", ArtworkID: "5"},
+				},
+				CreatedAt: time.Now(),
+				ExpiresAt: expiresAt,
+			}
+		}
+
+		It("should save, find and delete posts", func() {
+			Expect(s.SavePosts(ctx, newPost("10", time.Now().Add(time.Hour)))).To(Succeed())
+
+			post, err := s.Post(ctx, "1", "10")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(post.AuthorID).To(Equal("2"))
+			Expect(post.IsParent).To(BeTrue())
+			Expect(post.Children).To(HaveLen(1))
+			Expect(*post.Children[0]).To(Equal(store.PostMessage{ChannelID: "3", MessageID: "4", ArtworkID: "5"}))
+
+			Expect(s.DeletePost(ctx, "1", "10")).To(Succeed())
+			Expect(s.DeletePost(ctx, "1", "10")).To(Succeed())
+
+			_, err = s.Post(ctx, "1", "10")
+			Expect(err).To(MatchError(store.ErrPostNotFound))
+		})
+
+		It("should replace saved posts", func() {
+			Expect(s.SavePosts(ctx, newPost("10", time.Now().Add(time.Hour)))).To(Succeed())
+
+			post := newPost("10", time.Now().Add(time.Hour))
+			post.Children = append(post.Children, &store.PostMessage{ChannelID: "6", MessageID: "7"})
+			Expect(s.SavePosts(ctx, post)).To(Succeed())
+
+			post, err := s.Post(ctx, "1", "10")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(post.Children).To(HaveLen(2))
+		})
+
+		It("should not return and should delete expired posts", func() {
+			Expect(s.SavePosts(ctx, newPost("10", time.Now().Add(-time.Minute)))).To(Succeed())
+			Expect(s.SavePosts(ctx, newPost("11", time.Now().Add(time.Hour)))).To(Succeed())
+
+			_, err := s.Post(ctx, "1", "10")
+			Expect(err).To(MatchError(store.ErrPostNotFound))
+
+			deleted, err := s.DeleteExpiredPosts(ctx, time.Now())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleted).To(BeEquivalentTo(1))
+
+			_, err = s.Post(ctx, "1", "11")
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+}
+
+func ids(artworks []*store.Artwork) []int {
+	res := make([]int, 0, len(artworks))
+	for _, artwork := range artworks {
+		res = append(res, artwork.ID)
+	}
+
+	return res
+}
+
+func artworkIDs(bookmarks []*store.Bookmark) []int {
+	res := make([]int, 0, len(bookmarks))
+	for _, bookmark := range bookmarks {
+		res = append(res, bookmark.ArtworkID)
+	}
+
+	return res
+}
@@ -18,6 +18,7 @@ type UserStore interface {
 	RenameCrosspostGroup(ctx context.Context, userID string, name string, newName string) (*User, error)
 	AddCrosspostChannel(ctx context.Context, userID string, group string, child string) (*User, error)
 	DeleteCrosspostChannel(ctx context.Context, userID string, group string, child string) (*User, error)
+	EditCrosspostFilter(ctx context.Context, userID string, group string, filter GroupFilter) (*User, error)
 }
 
 type User struct {
@@ -26,15 +27,81 @@ type User struct {
 	Crosspost bool      `json:"crosspost" bson:"crosspost"`
 	Ignore    bool      `json:"ignore" bson:"ignore"`
 	Groups    []*Group  `json:"groups" bson:"channel_groups"`
+	Blacklist Blacklist `json:"blacklist" bson:"blacklist"`
 	CreatedAt time.Time `json:"created_at" bson:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
 }
 
 type Group struct {
-	Name     string   `json:"name" bson:"name"`
-	Parent   string   `json:"parent" bson:"parent"`
-	Children []string `json:"children" bson:"children"`
-	IsPair   bool     `json:"is_pair" bson:"is_pair"`
+	Name     string      `json:"name" bson:"name"`
+	Parent   string      `json:"parent" bson:"parent"`
+	Children []string    `json:"children" bson:"children"`
+	IsPair   bool        `json:"is_pair" bson:"is_pair"`
+	Filter   GroupFilter `json:"filter" bson:"filter"`
+}
+
+// GroupContent limits crossposted artworks by their NSFW flag.
+type GroupContent string
+
+const (
+	GroupContentAll  GroupContent = ""
+	GroupContentNSFW GroupContent = "nsfw"
+	GroupContentSFW  GroupContent = "sfw"
+)
+
+// GroupFilter decides which artworks are crossposted by a group. A zero filter crossposts everything.
+// Include lists are ignored if empty, tags are matched case-insensitively.
+type GroupFilter struct {
+	Providers        []string     `json:"providers,omitempty" bson:"providers,omitempty"`
+	ExcludeProviders []string     `json:"exclude_providers,omitempty" bson:"exclude_providers,omitempty"`
+	Content          GroupContent `json:"content,omitempty" bson:"content,omitempty"`
+	Tags             []string     `json:"tags,omitempty" bson:"tags,omitempty"`
+	ExcludeTags      []string     `json:"exclude_tags,omitempty" bson:"exclude_tags,omitempty"`
+	ExcludeAI        bool         `json:"exclude_ai,omitempty" bson:"exclude_ai,omitempty"`
+}
+
+// Empty reports whether the filter crossposts everything.
+func (f GroupFilter) Empty() bool {
+	return len(f.Providers) == 0 && len(f.ExcludeProviders) == 0 && f.Content == GroupContentAll &&
+		len(f.Tags) == 0 && len(f.ExcludeTags) == 0 && !f.ExcludeAI
+}
+
+// Match reports whether an artwork with given properties passes the filter.
+func (f GroupFilter) Match(provider string, nsfw, aiGenerated bool, tags []string) bool {
+	if len(f.Providers) != 0 && !slices.Contains(f.Providers, provider) {
+		return false
+	}
+
+	if slices.Contains(f.ExcludeProviders, provider) {
+		return false
+	}
+
+	switch f.Content {
+	case GroupContentNSFW:
+		if !nsfw {
+			return false
+		}
+	case GroupContentSFW:
+		if nsfw {
+			return false
+		}
+	}
+
+	if f.ExcludeAI && aiGenerated {
+		return false
+	}
+
+	hasAnyTag := func(filterTags []string) bool {
+		return slices.ContainsFunc(tags, func(tag string) bool {
+			return hasTag(filterTags, tag)
+		})
+	}
+
+	if len(f.Tags) != 0 && !hasAnyTag(f.Tags) {
+		return false
+	}
+
+	return !hasAnyTag(f.ExcludeTags)
 }
 
 func DefaultUser(id string) *User {